@@ -14,6 +14,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/manager/signals"
 
+	"code.cloudfoundry.org/quarks-secret/pkg/kube/controllers"
 	"code.cloudfoundry.org/quarks-secret/pkg/kube/operator"
 	"code.cloudfoundry.org/quarks-secret/version"
 	"code.cloudfoundry.org/quarks-utils/pkg/cmd"
@@ -45,6 +46,10 @@ var rootCmd = &cobra.Command{
 		cfg := config.NewDefaultConfig(afero.NewOsFs())
 
 		cmd.MonitoredID(cfg)
+		cmd.OperatorNamespace(cfg, log, "operator-namespace")
+		if !viper.IsSet("operator-namespace") {
+			log.Warnf("Operator namespace is not set, ClusterQuarksSecrets fall back to the '%s' namespace", cfg.OperatorNamespace)
+		}
 
 		log.Infof("Starting quarks-secret %s, monitoring namespaces labeled with '%s'", version.Version, cfg.MonitoredID)
 
@@ -63,6 +68,8 @@ var rootCmd = &cobra.Command{
 		mgr, err := operator.NewManager(ctx, cfg, restConfig, manager.Options{
 			MetricsBindAddress: "0",
 			LeaderElection:     false,
+		}, controllers.Options{
+			AllowUngrantedCopies: viper.GetBool("allow-ungranted-copies"),
 		})
 		if err != nil {
 			return wrapError(err, "Failed to create new manager.")
@@ -103,11 +110,17 @@ func init() {
 	cmd.LoggerFlags(pf, argToEnv)
 	cmd.ApplyCRDsFlags(pf, argToEnv)
 	cmd.MeltdownFlags(pf, argToEnv)
+	cmd.OperatorNamespaceFlags(pf, argToEnv, "operator-namespace")
+	pf.Lookup("operator-namespace").Usage = "The operator namespace, which contains the quarks secrets of ClusterQuarksSecrets and the cluster CAs"
 
 	pf.Int("max-workers", 1, "Maximum number of workers concurrently running the controller")
 	_ = viper.BindPFlag("max-workers", pf.Lookup("max-workers"))
 	argToEnv["max-workers"] = "MAX_WORKERS"
 
+	pf.Bool("allow-ungranted-copies", false, "Copy quarks secrets to other namespaces without a QuarksSecretCopyGrant, unless their namespace contains one")
+	_ = viper.BindPFlag("allow-ungranted-copies", pf.Lookup("allow-ungranted-copies"))
+	argToEnv["allow-ungranted-copies"] = "ALLOW_UNGRANTED_COPIES"
+
 	// Add env variables to help
 	cmd.AddEnvToUsage(rootCmd, argToEnv)
 
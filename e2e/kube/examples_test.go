@@ -2,6 +2,7 @@ package kube_test
 
 import (
 	b64 "encoding/base64"
+	"fmt"
 	"io/ioutil"
 	"os"
 	"path"
@@ -40,6 +41,12 @@ var _ = Describe("Examples Directory", func() {
 		return newExampleFile.Name()
 	}
 
+	// allowCopiesFrom returns a JSON patch, which annotates a namespace to
+	// allow copies from the source namespace
+	allowCopiesFrom := func(source string) string {
+		return fmt.Sprintf(`[{"op": "add", "path": "/metadata/annotations", "value": {"quarks.cloudfoundry.org/allow-copies-from": "%s"}}]`, source)
+	}
+
 	JustBeforeEach(func() {
 		kubectl = cmdHelper.NewKubectl()
 		yamlFilePath = path.Join(example)
@@ -127,6 +134,8 @@ var _ = Describe("Examples Directory", func() {
 
 			err := cmdHelper.CreateNamespace(copyNamespace)
 			Expect(err).ToNot(HaveOccurred())
+			err = cmdHelper.PatchNamespace(copyNamespace, allowCopiesFrom(namespace))
+			Expect(err).ToNot(HaveOccurred())
 
 			// Create a secret in the copy namespace
 
@@ -170,6 +179,8 @@ var _ = Describe("Examples Directory", func() {
 
 			err := cmdHelper.CreateNamespace(copyNamespace)
 			Expect(err).ToNot(HaveOccurred())
+			err = cmdHelper.PatchNamespace(copyNamespace, allowCopiesFrom(namespace))
+			Expect(err).ToNot(HaveOccurred())
 
 			// Create a copy of the example files with the correct namespaces in them
 			dSecretExample := path.Join(examplesDir, "copy-qsecret-destination.yaml")
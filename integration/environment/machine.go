@@ -62,6 +62,36 @@ func (m *Machine) DeleteQuarksSecret(namespace string, name string) error {
 	return client.Delete(context.Background(), name, metav1.DeleteOptions{})
 }
 
+// CreateQuarksSecretCopyGrant creates a QuarksSecretCopyGrant custom resource and returns a function to delete it
+func (m *Machine) CreateQuarksSecretCopyGrant(namespace string, grant qsv1a1.QuarksSecretCopyGrant) (machine.TearDownFunc, error) {
+	client := m.VersionedClientset.QuarkssecretV1alpha1().QuarksSecretCopyGrants(namespace)
+	_, err := client.Create(context.Background(), &grant, metav1.CreateOptions{})
+	return func() error {
+		err := client.Delete(context.Background(), grant.GetName(), metav1.DeleteOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+		return nil
+	}, err
+}
+
+// AllowCopiesFrom annotates the namespace to allow copies from the source namespace
+func (m *Machine) AllowCopiesFrom(namespace string, source string) error {
+	client := m.Clientset.CoreV1().Namespaces()
+	ns, err := client.Get(context.Background(), namespace, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	annotations := ns.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[qsv1a1.AnnotationAllowCopiesFrom] = source
+	ns.SetAnnotations(annotations)
+	_, err = client.Update(context.Background(), ns, metav1.UpdateOptions{})
+	return err
+}
+
 // QuarksSecretChangedFunc returns true if something changed in the quarks secret
 type QuarksSecretChangedFunc func(qsv1a1.QuarksSecret) bool
 
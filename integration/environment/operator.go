@@ -4,6 +4,7 @@ import (
 	_ "k8s.io/client-go/plugin/pkg/client/auth/oidc" //from https://github.com/kubernetes/client-go/issues/345
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 
+	"code.cloudfoundry.org/quarks-secret/pkg/kube/controllers"
 	"code.cloudfoundry.org/quarks-secret/pkg/kube/operator"
 )
 
@@ -23,7 +24,7 @@ func (e *Environment) setupOperator() (manager.Manager, error) {
 	mgr, err := operator.NewManager(ctx, e.Config, e.KubeConfig, manager.Options{
 		MetricsBindAddress: "0",
 		LeaderElection:     false,
-	})
+	}, controllers.Options{})
 
 	return mgr, err
 }
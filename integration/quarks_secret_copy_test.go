@@ -62,6 +62,18 @@ var _ = Describe("QuarksCopies", func() {
 			_, err := env.CreateNamespace(copyNamespace)
 			Expect(err).NotTo(HaveOccurred())
 		})
+
+		By("Allowing copies to the copy namespace", func() {
+			err := env.AllowCopiesFrom(copyNamespace, env.Namespace)
+			Expect(err).NotTo(HaveOccurred())
+
+			tearDown, err := env.CreateQuarksSecretCopyGrant(env.Namespace, qsv1a1.QuarksSecretCopyGrant{
+				ObjectMeta: metav1.ObjectMeta{Name: "copy-grant"},
+				Spec:       qsv1a1.QuarksSecretCopyGrantSpec{Namespaces: []string{copyNamespace}},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			tearDowns = append(tearDowns, tearDown)
+		})
 	})
 
 	AfterEach(func() {
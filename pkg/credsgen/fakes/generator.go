@@ -75,6 +75,20 @@ type FakeGenerator struct {
 		result1 credsgen.SSHKey
 		result2 error
 	}
+	SignCertificateSigningRequestStub        func([]byte, credsgen.CertificateGenerationRequest) ([]byte, error)
+	signCertificateSigningRequestMutex       sync.RWMutex
+	signCertificateSigningRequestArgsForCall []struct {
+		arg1 []byte
+		arg2 credsgen.CertificateGenerationRequest
+	}
+	signCertificateSigningRequestReturns struct {
+		result1 []byte
+		result2 error
+	}
+	signCertificateSigningRequestReturnsOnCall map[int]struct {
+		result1 []byte
+		result2 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -86,15 +100,16 @@ func (fake *FakeGenerator) GenerateCertificate(arg1 string, arg2 credsgen.Certif
 		arg1 string
 		arg2 credsgen.CertificateGenerationRequest
 	}{arg1, arg2})
+	stub := fake.GenerateCertificateStub
+	fakeReturns := fake.generateCertificateReturns
 	fake.recordInvocation("GenerateCertificate", []interface{}{arg1, arg2})
 	fake.generateCertificateMutex.Unlock()
-	if fake.GenerateCertificateStub != nil {
-		return fake.GenerateCertificateStub(arg1, arg2)
+	if stub != nil {
+		return stub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
 	}
-	fakeReturns := fake.generateCertificateReturns
 	return fakeReturns.result1, fakeReturns.result2
 }
 
@@ -149,15 +164,16 @@ func (fake *FakeGenerator) GenerateCertificateSigningRequest(arg1 credsgen.Certi
 	fake.generateCertificateSigningRequestArgsForCall = append(fake.generateCertificateSigningRequestArgsForCall, struct {
 		arg1 credsgen.CertificateGenerationRequest
 	}{arg1})
+	stub := fake.GenerateCertificateSigningRequestStub
+	fakeReturns := fake.generateCertificateSigningRequestReturns
 	fake.recordInvocation("GenerateCertificateSigningRequest", []interface{}{arg1})
 	fake.generateCertificateSigningRequestMutex.Unlock()
-	if fake.GenerateCertificateSigningRequestStub != nil {
-		return fake.GenerateCertificateSigningRequestStub(arg1)
+	if stub != nil {
+		return stub(arg1)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2, ret.result3
 	}
-	fakeReturns := fake.generateCertificateSigningRequestReturns
 	return fakeReturns.result1, fakeReturns.result2, fakeReturns.result3
 }
 
@@ -216,15 +232,16 @@ func (fake *FakeGenerator) GeneratePassword(arg1 string, arg2 credsgen.PasswordG
 		arg1 string
 		arg2 credsgen.PasswordGenerationRequest
 	}{arg1, arg2})
+	stub := fake.GeneratePasswordStub
+	fakeReturns := fake.generatePasswordReturns
 	fake.recordInvocation("GeneratePassword", []interface{}{arg1, arg2})
 	fake.generatePasswordMutex.Unlock()
-	if fake.GeneratePasswordStub != nil {
-		return fake.GeneratePasswordStub(arg1, arg2)
+	if stub != nil {
+		return stub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1
 	}
-	fakeReturns := fake.generatePasswordReturns
 	return fakeReturns.result1
 }
 
@@ -276,15 +293,16 @@ func (fake *FakeGenerator) GenerateRSAKey(arg1 string) (credsgen.RSAKey, error)
 	fake.generateRSAKeyArgsForCall = append(fake.generateRSAKeyArgsForCall, struct {
 		arg1 string
 	}{arg1})
+	stub := fake.GenerateRSAKeyStub
+	fakeReturns := fake.generateRSAKeyReturns
 	fake.recordInvocation("GenerateRSAKey", []interface{}{arg1})
 	fake.generateRSAKeyMutex.Unlock()
-	if fake.GenerateRSAKeyStub != nil {
-		return fake.GenerateRSAKeyStub(arg1)
+	if stub != nil {
+		return stub(arg1)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
 	}
-	fakeReturns := fake.generateRSAKeyReturns
 	return fakeReturns.result1, fakeReturns.result2
 }
 
@@ -339,15 +357,16 @@ func (fake *FakeGenerator) GenerateSSHKey(arg1 string) (credsgen.SSHKey, error)
 	fake.generateSSHKeyArgsForCall = append(fake.generateSSHKeyArgsForCall, struct {
 		arg1 string
 	}{arg1})
+	stub := fake.GenerateSSHKeyStub
+	fakeReturns := fake.generateSSHKeyReturns
 	fake.recordInvocation("GenerateSSHKey", []interface{}{arg1})
 	fake.generateSSHKeyMutex.Unlock()
-	if fake.GenerateSSHKeyStub != nil {
-		return fake.GenerateSSHKeyStub(arg1)
+	if stub != nil {
+		return stub(arg1)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
 	}
-	fakeReturns := fake.generateSSHKeyReturns
 	return fakeReturns.result1, fakeReturns.result2
 }
 
@@ -396,6 +415,76 @@ func (fake *FakeGenerator) GenerateSSHKeyReturnsOnCall(i int, result1 credsgen.S
 	}{result1, result2}
 }
 
+func (fake *FakeGenerator) SignCertificateSigningRequest(arg1 []byte, arg2 credsgen.CertificateGenerationRequest) ([]byte, error) {
+	var arg1Copy []byte
+	if arg1 != nil {
+		arg1Copy = make([]byte, len(arg1))
+		copy(arg1Copy, arg1)
+	}
+	fake.signCertificateSigningRequestMutex.Lock()
+	ret, specificReturn := fake.signCertificateSigningRequestReturnsOnCall[len(fake.signCertificateSigningRequestArgsForCall)]
+	fake.signCertificateSigningRequestArgsForCall = append(fake.signCertificateSigningRequestArgsForCall, struct {
+		arg1 []byte
+		arg2 credsgen.CertificateGenerationRequest
+	}{arg1Copy, arg2})
+	stub := fake.SignCertificateSigningRequestStub
+	fakeReturns := fake.signCertificateSigningRequestReturns
+	fake.recordInvocation("SignCertificateSigningRequest", []interface{}{arg1Copy, arg2})
+	fake.signCertificateSigningRequestMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeGenerator) SignCertificateSigningRequestCallCount() int {
+	fake.signCertificateSigningRequestMutex.RLock()
+	defer fake.signCertificateSigningRequestMutex.RUnlock()
+	return len(fake.signCertificateSigningRequestArgsForCall)
+}
+
+func (fake *FakeGenerator) SignCertificateSigningRequestCalls(stub func([]byte, credsgen.CertificateGenerationRequest) ([]byte, error)) {
+	fake.signCertificateSigningRequestMutex.Lock()
+	defer fake.signCertificateSigningRequestMutex.Unlock()
+	fake.SignCertificateSigningRequestStub = stub
+}
+
+func (fake *FakeGenerator) SignCertificateSigningRequestArgsForCall(i int) ([]byte, credsgen.CertificateGenerationRequest) {
+	fake.signCertificateSigningRequestMutex.RLock()
+	defer fake.signCertificateSigningRequestMutex.RUnlock()
+	argsForCall := fake.signCertificateSigningRequestArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeGenerator) SignCertificateSigningRequestReturns(result1 []byte, result2 error) {
+	fake.signCertificateSigningRequestMutex.Lock()
+	defer fake.signCertificateSigningRequestMutex.Unlock()
+	fake.SignCertificateSigningRequestStub = nil
+	fake.signCertificateSigningRequestReturns = struct {
+		result1 []byte
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeGenerator) SignCertificateSigningRequestReturnsOnCall(i int, result1 []byte, result2 error) {
+	fake.signCertificateSigningRequestMutex.Lock()
+	defer fake.signCertificateSigningRequestMutex.Unlock()
+	fake.SignCertificateSigningRequestStub = nil
+	if fake.signCertificateSigningRequestReturnsOnCall == nil {
+		fake.signCertificateSigningRequestReturnsOnCall = make(map[int]struct {
+			result1 []byte
+			result2 error
+		})
+	}
+	fake.signCertificateSigningRequestReturnsOnCall[i] = struct {
+		result1 []byte
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeGenerator) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
@@ -409,6 +498,8 @@ func (fake *FakeGenerator) Invocations() map[string][][]interface{} {
 	defer fake.generateRSAKeyMutex.RUnlock()
 	fake.generateSSHKeyMutex.RLock()
 	defer fake.generateSSHKeyMutex.RUnlock()
+	fake.signCertificateSigningRequestMutex.RLock()
+	defer fake.signCertificateSigningRequestMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
@@ -1,5 +1,7 @@
 package credsgen
 
+import "time"
+
 const (
 	// DefaultPasswordLength represents the default length of a generated password
 	// (number of characters)
@@ -17,6 +19,12 @@ type CertificateGenerationRequest struct {
 	AlternativeNames []string
 	IsCA             bool
 	CA               Certificate
+	// Duration is the validity of the certificate, the generator's default
+	// is used if it is zero
+	Duration time.Duration
+	// Usages are the key usages of a certificate, which is not a CA. The
+	// generator's default is used if it is empty.
+	Usages []string
 }
 
 // Certificate holds the information about a certificate
@@ -44,6 +52,7 @@ type Generator interface {
 	GeneratePassword(name string, request PasswordGenerationRequest) string
 	GenerateCertificate(name string, request CertificateGenerationRequest) (Certificate, error)
 	GenerateCertificateSigningRequest(request CertificateGenerationRequest) ([]byte, []byte, error)
+	SignCertificateSigningRequest(csr []byte, request CertificateGenerationRequest) ([]byte, error)
 	GenerateSSHKey(name string) (SSHKey, error)
 	GenerateRSAKey(name string) (RSAKey, error)
 }
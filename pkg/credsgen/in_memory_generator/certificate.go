@@ -1,7 +1,6 @@
 package inmemorygenerator
 
 import (
-	"fmt"
 	"time"
 
 	"code.cloudfoundry.org/quarks-secret/pkg/credsgen"
@@ -59,6 +58,23 @@ func (g InMemoryGenerator) GenerateCertificateSigningRequest(request credsgen.Ce
 	return csReq, privateKey, nil
 }
 
+// SignCertificateSigningRequest signs a PEM encoded certificate signing
+// request with the CA of the request. The subject and alternative names are
+// taken from the CSR.
+func (g InMemoryGenerator) SignCertificateSigningRequest(csr []byte, request credsgen.CertificateGenerationRequest) ([]byte, error) {
+	cfssllog.Level = cfssllog.LevelWarning
+
+	if !request.CA.IsCA {
+		return nil, errors.Errorf("The passed CA is not a CA")
+	}
+
+	certificate, err := g.signCertificate(csr, g.signingProfile(request), request)
+	if err != nil {
+		return nil, errors.Wrap(err, "Signing certificate signing request failed.")
+	}
+	return certificate, nil
+}
+
 // generateCertificate Generate a local-issued certificate and private key
 func (g InMemoryGenerator) generateCertificate(request credsgen.CertificateGenerationRequest) (credsgen.Certificate, error) {
 	if !request.CA.IsCA {
@@ -75,12 +91,7 @@ func (g InMemoryGenerator) generateCertificate(request credsgen.CertificateGener
 		return credsgen.Certificate{}, err
 	}
 	// Sign certificate
-	signingProfile := &config.SigningProfile{
-		Usage:        []string{"server auth", "client auth"},
-		Expiry:       time.Duration(g.Expiry*24) * time.Hour,
-		ExpiryString: fmt.Sprintf("%dh", g.Expiry*24),
-	}
-	cert.Certificate, err = g.signCertificate(signingReq, signingProfile, request)
+	cert.Certificate, err = g.signCertificate(signingReq, g.signingProfile(request), request)
 	if err != nil {
 		return credsgen.Certificate{}, err
 	}
@@ -92,7 +103,7 @@ func (g InMemoryGenerator) generateCertificate(request credsgen.CertificateGener
 // generateCACertificate Generate self-signed root CA certificate and private key
 func (g InMemoryGenerator) generateCACertificate(request credsgen.CertificateGenerationRequest) (credsgen.Certificate, error) {
 	req := &csr.CertificateRequest{
-		CA:         &csr.CAConfig{Expiry: fmt.Sprintf("%dh", g.Expiry*24)},
+		CA:         &csr.CAConfig{Expiry: g.expiry(request).String()},
 		CN:         request.CommonName,
 		KeyRequest: &csr.KeyRequest{A: g.Algorithm, S: g.Bits},
 	}
@@ -107,10 +118,14 @@ func (g InMemoryGenerator) generateCACertificate(request credsgen.CertificateGen
 		PrivateKey:  privateKey,
 	}
 	if request.CA.IsCA {
+		expiry := 5 * helpers.OneYear
+		if request.Duration > 0 {
+			expiry = request.Duration
+		}
 		signingProfile := &config.SigningProfile{
 			Usage:        []string{"cert sign", "crl sign"},
-			ExpiryString: "43800h",
-			Expiry:       5 * helpers.OneYear,
+			ExpiryString: expiry.String(),
+			Expiry:       expiry,
 			CAConstraint: config.CAConstraint{
 				IsCA: true,
 			},
@@ -153,3 +168,25 @@ func (g InMemoryGenerator) signCertificate(csr []byte, signingProfile *config.Si
 
 	return certificate, nil
 }
+
+// signingProfile returns the profile for certificates, which are not a CA
+func (g InMemoryGenerator) signingProfile(request credsgen.CertificateGenerationRequest) *config.SigningProfile {
+	usages := []string{"server auth", "client auth"}
+	if len(request.Usages) > 0 {
+		usages = request.Usages
+	}
+	expiry := g.expiry(request)
+	return &config.SigningProfile{
+		Usage:        usages,
+		Expiry:       expiry,
+		ExpiryString: expiry.String(),
+	}
+}
+
+// expiry returns the requested validity or the generator's default
+func (g InMemoryGenerator) expiry(request credsgen.CertificateGenerationRequest) time.Duration {
+	if request.Duration > 0 {
+		return request.Duration
+	}
+	return time.Duration(g.Expiry*24) * time.Hour
+}
@@ -108,6 +108,22 @@ var _ = Describe("InMemoryGenerator", func() {
 					Expect(parsedCert.NotAfter.Before(time.Now().AddDate(0, 0, 2))).To(BeTrue())
 					Expect(len(cert.PrivateKey)).To(Equal(227))
 				})
+
+				It("considers the requested duration and usages", func() {
+					request.Duration = 48 * time.Hour
+					request.Usages = []string{"digital signature", "client auth"}
+
+					cert, err := generator.GenerateCertificate("foo", request)
+					Expect(err).ToNot(HaveOccurred())
+
+					parsedCert, err := parseCert(cert.Certificate)
+					Expect(err).ToNot(HaveOccurred())
+
+					Expect(parsedCert.NotAfter.Before(time.Now().Add(49 * time.Hour))).To(BeTrue())
+					Expect(parsedCert.NotAfter.After(time.Now().Add(47 * time.Hour))).To(BeTrue())
+					Expect(parsedCert.KeyUsage & x509.KeyUsageDigitalSignature).ToNot(BeZero())
+					Expect(parsedCert.ExtKeyUsage).To(Equal([]x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}))
+				})
 			})
 		})
 
@@ -160,6 +176,50 @@ var _ = Describe("InMemoryGenerator", func() {
 			})
 		})
 	})
+
+	Describe("SignCertificateSigningRequest", func() {
+		var (
+			request credsgen.CertificateGenerationRequest
+			csr     []byte
+		)
+
+		BeforeEach(func() {
+			ca, err := generator.GenerateCertificate("testca", credsgen.CertificateGenerationRequest{CommonName: "Fake CA", IsCA: true})
+			Expect(err).ToNot(HaveOccurred())
+			csr, _, err = generator.GenerateCertificateSigningRequest(credsgen.CertificateGenerationRequest{
+				CommonName:       "foo.com",
+				AlternativeNames: []string{"bar.com"},
+			})
+			Expect(err).ToNot(HaveOccurred())
+			request = credsgen.CertificateGenerationRequest{CA: ca}
+		})
+
+		It("fails if the passed CA is not a CA", func() {
+			request.CA = credsgen.Certificate{IsCA: false}
+
+			_, err := generator.SignCertificateSigningRequest(csr, request)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("not a CA"))
+		})
+
+		It("signs the names of the CSR with the duration and usages", func() {
+			request.Duration = 48 * time.Hour
+			request.Usages = []string{"digital signature", "server auth"}
+
+			cert, err := generator.SignCertificateSigningRequest(csr, request)
+			Expect(err).ToNot(HaveOccurred())
+
+			parsedCert, err := parseCert(cert)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(parsedCert.IsCA).To(BeFalse())
+			Expect(parsedCert.Issuer.CommonName).To(Equal("Fake CA"))
+			Expect(parsedCert.Subject.CommonName).To(Equal("foo.com"))
+			Expect(parsedCert.DNSNames).To(ConsistOf("foo.com", "bar.com"))
+			Expect(parsedCert.NotAfter.Before(time.Now().Add(49 * time.Hour))).To(BeTrue())
+			Expect(parsedCert.ExtKeyUsage).To(Equal([]x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}))
+		})
+	})
 })
 
 func parseCert(certificate []byte) (*x509.Certificate, error) {
@@ -0,0 +1,63 @@
+package v1alpha1
+
+import (
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apis "code.cloudfoundry.org/quarks-secret/pkg/kube/apis"
+)
+
+var (
+	// LabelClusterQuarksSecret is set on the quarks secret, which generates
+	// the secret of a cluster quarks secret in the operator namespace
+	LabelClusterQuarksSecret = fmt.Sprintf("%s/cluster-quarks-secret", apis.GroupName)
+	// AnnotationCSRSigner allows the CA of a cluster quarks secret to sign
+	// approved Kubernetes CSRs for its signer name, if set to 'true'
+	AnnotationCSRSigner = fmt.Sprintf("%s/csr-signer", apis.GroupName)
+	// SignerNamePrefix is the prefix of the signer names, the operator
+	// signs CSRs for. It is followed by the name of the cluster quarks secret.
+	SignerNamePrefix = fmt.Sprintf("%s/", apis.GroupName)
+)
+
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ClusterQuarksSecret is the Schema for the ClusterQuarksSecrets API. Its
+// secret is generated in the operator namespace. A certificate CA can be
+// referenced by certificates in all monitored namespaces, without exposing
+// its private key.
+// +k8s:openapi-gen=true
+type ClusterQuarksSecret struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   QuarksSecretSpec   `json:"spec,omitempty"`
+	Status QuarksSecretStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ClusterQuarksSecretList contains a list of ClusterQuarksSecret
+type ClusterQuarksSecretList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ClusterQuarksSecret `json:"items"`
+}
+
+// IsCA returns true, if the cluster quarks secret generates a CA
+// certificate, which can sign other certificates
+func (c *ClusterQuarksSecret) IsCA() bool {
+	return c.Spec.Type == Certificate && c.Spec.Request.CertificateRequest.IsCA
+}
+
+// SignerName returns the signer name of CSRs, which are signed by the CA
+func (c *ClusterQuarksSecret) SignerName() string {
+	return SignerNamePrefix + c.Name
+}
+
+// IsCSRSigner returns true, if the CA signs CSRs for its signer name
+func (c *ClusterQuarksSecret) IsCSRSigner() bool {
+	return c.IsCA() && c.GetAnnotations()[AnnotationCSRSigner] == "true"
+}
@@ -0,0 +1,73 @@
+package v1alpha1
+
+import (
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// QuarksSecretCopyGrantSpec lists the quarks secrets, which may be copied,
+// and the namespaces they may be copied to
+type QuarksSecretCopyGrantSpec struct {
+	// QuarksSecretNames lists quarks secrets in the grant's namespace.
+	// Defaults to all quarks secrets in the namespace.
+	QuarksSecretNames []string `json:"quarksSecretNames,omitempty"`
+	// Namespaces lists the namespaces, which may receive copies, '*'
+	// grants copies to all namespaces
+	Namespaces []string `json:"namespaces"`
+}
+
+// +genclient
+// +genclient:noStatus
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// QuarksSecretCopyGrant is the Schema for the QuarksSecretCopyGrants API.
+// It grants copying quarks secrets to other namespaces. Quarks secrets are
+// only copied to namespaces, which are granted in their namespace.
+// +k8s:openapi-gen=true
+type QuarksSecretCopyGrant struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec QuarksSecretCopyGrantSpec `json:"spec,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// QuarksSecretCopyGrantList contains a list of QuarksSecretCopyGrant
+type QuarksSecretCopyGrantList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []QuarksSecretCopyGrant `json:"items"`
+}
+
+// GetNamespacedName returns the resource name with its namespace
+func (g *QuarksSecretCopyGrant) GetNamespacedName() string {
+	return fmt.Sprintf("%s/%s", g.Namespace, g.Name)
+}
+
+// Grants returns true, if the grant allows copying the quarks secret to
+// the namespace
+func (g *QuarksSecretCopyGrant) Grants(qsecName string, namespace string) bool {
+	if !g.grantsQuarksSecret(qsecName) {
+		return false
+	}
+	for _, ns := range g.Spec.Namespaces {
+		if ns == "*" || ns == namespace {
+			return true
+		}
+	}
+	return false
+}
+
+func (g *QuarksSecretCopyGrant) grantsQuarksSecret(name string) bool {
+	if len(g.Spec.QuarksSecretNames) == 0 {
+		return true
+	}
+	for _, n := range g.Spec.QuarksSecretNames {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,105 @@
+package v1alpha1
+
+import (
+	certv1 "k8s.io/api/certificates/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Valid values for issuer kinds
+const (
+	// QuarksIssuerKind references a QuarksIssuer in the namespace of the
+	// quarks secret
+	QuarksIssuerKind = "QuarksIssuer"
+	// ClusterQuarksIssuerKind references a ClusterQuarksIssuer
+	ClusterQuarksIssuerKind = "ClusterQuarksIssuer"
+)
+
+// IssuerReference references the issuer of a certificate
+type IssuerReference struct {
+	Name string `json:"name"`
+	// Kind is QuarksIssuer or ClusterQuarksIssuer, defaults to QuarksIssuer
+	Kind string `json:"kind,omitempty"`
+}
+
+// IssuerKind returns the kind of the referenced issuer
+func (r IssuerReference) IssuerKind() string {
+	if r.Kind == "" {
+		return QuarksIssuerKind
+	}
+	return r.Kind
+}
+
+// QuarksIssuerSpec defines the signer of certificates and the defaults for
+// the certificates it issues
+type QuarksIssuerSpec struct {
+	// SignerType is the signer of the certificates, defaults to local
+	SignerType SignerType `json:"signerType,omitempty"`
+	// SignerName is the signer of the CSRs, if the cluster signer is used
+	SignerName string `json:"signerName,omitempty"`
+	// CARef and CAKeyRef reference the CA of the local signer. They are not
+	// supported by cluster issuers.
+	CARef    SecretReference `json:"CARef,omitempty"`
+	CAKeyRef SecretReference `json:"CAKeyRef,omitempty"`
+	// ClusterCARef is the name of a ClusterQuarksSecret, whose CA is used by
+	// the local signer
+	ClusterCARef string `json:"clusterCARef,omitempty"`
+	// Duration is the default validity of issued certificates
+	Duration *metav1.Duration `json:"duration,omitempty"`
+	// Usages are the default key usages of issued certificates
+	Usages []certv1.KeyUsage `json:"usages,omitempty"`
+}
+
+// QuarksIssuerStatus defines the observed state of an issuer
+type QuarksIssuerStatus struct {
+	// ObservedGeneration is the generation of the spec, which the
+	// certificates of the issuer were issued with
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+}
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// QuarksIssuer is the Schema for the QuarksIssuers API. Certificates in its
+// namespace reference it by name, instead of configuring the signer
+// themselves.
+// +k8s:openapi-gen=true
+type QuarksIssuer struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   QuarksIssuerSpec   `json:"spec,omitempty"`
+	Status QuarksIssuerStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// QuarksIssuerList contains a list of QuarksIssuer
+type QuarksIssuerList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []QuarksIssuer `json:"items"`
+}
+
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ClusterQuarksIssuer is the Schema for the ClusterQuarksIssuers API. It is
+// a QuarksIssuer, which can be referenced from all namespaces.
+// +k8s:openapi-gen=true
+type ClusterQuarksIssuer struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   QuarksIssuerSpec   `json:"spec,omitempty"`
+	Status QuarksIssuerStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ClusterQuarksIssuerList contains a list of ClusterQuarksIssuer
+type ClusterQuarksIssuerList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ClusterQuarksIssuer `json:"items"`
+}
@@ -20,6 +20,36 @@ const (
 	QuarksSecretResourceKind = "QuarksSecret"
 	// QuarksSecretResourcePlural is the plural name of QuarksSecret
 	QuarksSecretResourcePlural = "quarkssecrets"
+
+	// QuarksSecretRotationResourceKind is the kind name of QuarksSecretRotation
+	QuarksSecretRotationResourceKind = "QuarksSecretRotation"
+	// QuarksSecretRotationResourcePlural is the plural name of QuarksSecretRotation
+	QuarksSecretRotationResourcePlural = "quarkssecretrotations"
+
+	// QuarksSecretCopyGrantResourceKind is the kind name of QuarksSecretCopyGrant
+	QuarksSecretCopyGrantResourceKind = "QuarksSecretCopyGrant"
+	// QuarksSecretCopyGrantResourcePlural is the plural name of QuarksSecretCopyGrant
+	QuarksSecretCopyGrantResourcePlural = "quarkssecretcopygrants"
+
+	// ClusterQuarksSecretResourceKind is the kind name of ClusterQuarksSecret
+	ClusterQuarksSecretResourceKind = "ClusterQuarksSecret"
+	// ClusterQuarksSecretResourcePlural is the plural name of ClusterQuarksSecret
+	ClusterQuarksSecretResourcePlural = "clusterquarkssecrets"
+
+	// QuarksIssuerResourceKind is the kind name of QuarksIssuer
+	QuarksIssuerResourceKind = "QuarksIssuer"
+	// QuarksIssuerResourcePlural is the plural name of QuarksIssuer
+	QuarksIssuerResourcePlural = "quarksissuers"
+
+	// ClusterQuarksIssuerResourceKind is the kind name of ClusterQuarksIssuer
+	ClusterQuarksIssuerResourceKind = "ClusterQuarksIssuer"
+	// ClusterQuarksIssuerResourcePlural is the plural name of ClusterQuarksIssuer
+	ClusterQuarksIssuerResourcePlural = "clusterquarksissuers"
+
+	// QuarksTrustBundleResourceKind is the kind name of QuarksTrustBundle
+	QuarksTrustBundleResourceKind = "QuarksTrustBundle"
+	// QuarksTrustBundleResourcePlural is the plural name of QuarksTrustBundle
+	QuarksTrustBundleResourcePlural = "quarkstrustbundles"
 )
 
 var (
@@ -78,7 +108,7 @@ var (
 						},
 						"copies": {
 							Type:        "array",
-							Description: "A list of namespaced names where to copy generated secrets",
+							Description: "A list of namespaced names where to copy generated secrets, or names with a namespaceSelector",
 							Items: &extv1.JSONSchemaPropsOrArray{
 								Schema: &extv1.JSONSchemaProps{
 									Type:                   "object",
@@ -86,6 +116,25 @@ var (
 								},
 							},
 						},
+						"paused": {
+							Type:        "boolean",
+							Description: "Stops all controllers from reconciling the quarks secret",
+						},
+						"providedValues": {
+							Type:        "object",
+							Description: "Keys of the generated secret, whose values are read from other secrets instead of being generated",
+							AdditionalProperties: &extv1.JSONSchemaPropsOrBool{
+								Schema: &extv1.JSONSchemaProps{
+									Type: "object",
+									Properties: map[string]extv1.JSONSchemaProps{
+										"name":      {Type: "string", MinLength: pointers.Int64(1)},
+										"key":       {Type: "string", MinLength: pointers.Int64(1)},
+										"namespace": {Type: "string"},
+									},
+									Required: []string{"name", "key"},
+								},
+							},
+						},
 						"secretLabels": {
 							Type:                   "object",
 							XPreserveUnknownFields: pointers.Bool(true),
@@ -94,6 +143,52 @@ var (
 							Type:                   "object",
 							XPreserveUnknownFields: pointers.Bool(true),
 						},
+						"driftPolicy": {
+							Type:        "string",
+							Description: "What to do, when the generated secret is deleted or modified: regenerate, restore, report-only",
+							Enum: []extv1.JSON{
+								{Raw: []byte(`"regenerate"`)},
+								{Raw: []byte(`"restore"`)},
+								{Raw: []byte(`"report-only"`)},
+							},
+						},
+						"existingSecretPolicy": {
+							Type:        "string",
+							Description: "What to do, when a secret with the same name exists, which was not generated: skip, adopt",
+							Enum: []extv1.JSON{
+								{Raw: []byte(`"skip"`)},
+								{Raw: []byte(`"adopt"`)},
+							},
+						},
+						"publicOutput": {
+							Type:        "object",
+							Description: "Publishes the public parts of the generated secret in a config map",
+							Properties: map[string]extv1.JSONSchemaProps{
+								"configMapName": {
+									Type:      "string",
+									MinLength: pointers.Int64(1),
+								},
+								"namespaceSelector": {
+									Type:                   "object",
+									Description:            "Selects namespaces, to which the config map is copied",
+									XPreserveUnknownFields: pointers.Bool(true),
+								},
+							},
+							Required: []string{"configMapName"},
+						},
+						"deletionPolicy": {
+							Type:        "string",
+							Description: "What to do with the generated secret and its copies, when the quarks secret is deleted: delete, orphan",
+							Enum: []extv1.JSON{
+								{Raw: []byte(`"delete"`)},
+								{Raw: []byte(`"orphan"`)},
+							},
+						},
+						"rolloutTargets": {
+							Type:                   "object",
+							Description:            "Workloads to roll out, when the generated secret changes",
+							XPreserveUnknownFields: pointers.Bool(true),
+						},
 					},
 					Required: []string{
 						"secretName",
@@ -109,10 +204,61 @@ var (
 						"copied": {
 							Type: "boolean",
 						},
+						"copies": {
+							Type: "array",
+							Items: &extv1.JSONSchemaPropsOrArray{
+								Schema: &extv1.JSONSchemaProps{
+									Type:                   "object",
+									XPreserveUnknownFields: pointers.Bool(true),
+								},
+							},
+						},
+						"deniedCopies": {
+							Type: "array",
+							Items: &extv1.JSONSchemaPropsOrArray{
+								Schema: &extv1.JSONSchemaProps{
+									Type:                   "object",
+									XPreserveUnknownFields: pointers.Bool(true),
+								},
+							},
+						},
 						"lastReconcile": {
 							Type:     "string",
 							Nullable: true,
 						},
+						"inputsHash": {
+							Type: "string",
+						},
+						"certificate": {
+							Type:                   "object",
+							XPreserveUnknownFields: pointers.Bool(true),
+						},
+						"keyFingerprint": {
+							Type: "string",
+						},
+						"driftDetected": {
+							Type:     "string",
+							Nullable: true,
+						},
+						"pendingWork": {
+							Type: "array",
+							Items: &extv1.JSONSchemaPropsOrArray{
+								Schema: &extv1.JSONSchemaProps{Type: "string"},
+							},
+						},
+						"observedGeneration": {
+							Type:   "integer",
+							Format: "int64",
+						},
+						"conditions": {
+							Type: "array",
+							Items: &extv1.JSONSchemaPropsOrArray{
+								Schema: &extv1.JSONSchemaProps{
+									Type:                   "object",
+									XPreserveUnknownFields: pointers.Bool(true),
+								},
+							},
+						},
 					},
 				},
 			},
@@ -136,6 +282,40 @@ var (
 			Type:     "boolean",
 			JSONPath: ".status.generated",
 		},
+		{
+			Name:     "ready",
+			Type:     "string",
+			JSONPath: `.status.conditions[?(@.type=="Ready")].status`,
+		},
+		{
+			Name:     "paused",
+			Type:     "boolean",
+			JSONPath: ".spec.paused",
+			Priority: 1,
+		},
+		{
+			Name:     "expires",
+			Type:     "date",
+			JSONPath: ".status.certificate.notAfter",
+		},
+		{
+			Name:     "issuer",
+			Type:     "string",
+			JSONPath: ".status.certificate.issuer",
+			Priority: 1,
+		},
+		{
+			Name:     "serial",
+			Type:     "string",
+			JSONPath: ".status.certificate.serialNumber",
+			Priority: 1,
+		},
+		{
+			Name:     "fingerprint",
+			Type:     "string",
+			JSONPath: ".status.certificate.fingerprint",
+			Priority: 1,
+		},
 		{
 			Name:     "age",
 			Type:     "date",
@@ -150,6 +330,306 @@ var (
 	// QuarksSecretResourceName is the resource name of QuarksSecret
 	QuarksSecretResourceName = fmt.Sprintf("%s.%s", QuarksSecretResourcePlural, apis.GroupName)
 
+	// QuarksSecretRotationResourceShortNames is the short names of QuarksSecretRotation
+	QuarksSecretRotationResourceShortNames = []string{"qsrot", "qsrots"}
+
+	// QuarksSecretRotationValidation is the validation schema for QuarksSecretRotation
+	QuarksSecretRotationValidation = extv1.CustomResourceValidation{
+		OpenAPIV3Schema: &extv1.JSONSchemaProps{
+			Type: "object",
+			Properties: map[string]extv1.JSONSchemaProps{
+				"spec": {
+					Type: "object",
+					Properties: map[string]extv1.JSONSchemaProps{
+						"secretNames": {
+							Type:        "array",
+							Description: "Names of quarks secrets in the rotation's namespace",
+							Items: &extv1.JSONSchemaPropsOrArray{
+								Schema: &extv1.JSONSchemaProps{Type: "string"},
+							},
+						},
+						"selector": {
+							Type:                   "object",
+							Description:            "Selects quarks secrets by labels",
+							XPreserveUnknownFields: pointers.Bool(true),
+						},
+						"types": {
+							Type:        "array",
+							Description: "Selects quarks secrets by type",
+							Items: &extv1.JSONSchemaPropsOrArray{
+								Schema: &extv1.JSONSchemaProps{Type: "string"},
+							},
+						},
+						"namespaces": {
+							Type:        "array",
+							Description: "Namespaces to select quarks secrets in",
+							Items: &extv1.JSONSchemaPropsOrArray{
+								Schema: &extv1.JSONSchemaProps{Type: "string"},
+							},
+						},
+						"dryRun": {
+							Type:        "boolean",
+							Description: "Only report which quarks secrets would be rotated",
+						},
+						"requireApproval": {
+							Type:        "boolean",
+							Description: "Hold the rotation until it is approved",
+						},
+					},
+				},
+				"status": {
+					Type:                   "object",
+					XPreserveUnknownFields: pointers.Bool(true),
+				},
+			},
+		},
+	}
+
+	// QuarksSecretRotationAdditionalPrinterColumns are used by `kubectl get`
+	QuarksSecretRotationAdditionalPrinterColumns = []extv1.CustomResourceColumnDefinition{
+		{
+			Name:     "dryrun",
+			Type:     "boolean",
+			JSONPath: ".spec.dryRun",
+		},
+		{
+			Name:     "phase",
+			Type:     "string",
+			JSONPath: ".status.phase",
+		},
+		{
+			Name:     "age",
+			Type:     "date",
+			JSONPath: ".metadata.creationTimestamp",
+		},
+	}
+
+	// QuarksSecretRotationResourceName is the resource name of QuarksSecretRotation
+	QuarksSecretRotationResourceName = fmt.Sprintf("%s.%s", QuarksSecretRotationResourcePlural, apis.GroupName)
+
+	// QuarksSecretCopyGrantResourceShortNames is the short names of QuarksSecretCopyGrant
+	QuarksSecretCopyGrantResourceShortNames = []string{"qscg", "qscgs"}
+
+	// QuarksSecretCopyGrantValidation is the validation schema for QuarksSecretCopyGrant
+	QuarksSecretCopyGrantValidation = extv1.CustomResourceValidation{
+		OpenAPIV3Schema: &extv1.JSONSchemaProps{
+			Type: "object",
+			Properties: map[string]extv1.JSONSchemaProps{
+				"spec": {
+					Type: "object",
+					Properties: map[string]extv1.JSONSchemaProps{
+						"quarksSecretNames": {
+							Type:        "array",
+							Description: "Names of quarks secrets in the grant's namespace, which may be copied. Defaults to all.",
+							Items: &extv1.JSONSchemaPropsOrArray{
+								Schema: &extv1.JSONSchemaProps{Type: "string"},
+							},
+						},
+						"namespaces": {
+							Type:        "array",
+							Description: "Namespaces, which may receive copies, '*' for all namespaces",
+							Items: &extv1.JSONSchemaPropsOrArray{
+								Schema: &extv1.JSONSchemaProps{Type: "string"},
+							},
+						},
+					},
+					Required: []string{
+						"namespaces",
+					},
+				},
+			},
+		},
+	}
+
+	// QuarksSecretCopyGrantAdditionalPrinterColumns are used by `kubectl get`
+	QuarksSecretCopyGrantAdditionalPrinterColumns = []extv1.CustomResourceColumnDefinition{
+		{
+			Name:     "namespaces",
+			Type:     "string",
+			JSONPath: ".spec.namespaces",
+		},
+		{
+			Name:     "age",
+			Type:     "date",
+			JSONPath: ".metadata.creationTimestamp",
+		},
+	}
+
+	// QuarksSecretCopyGrantResourceName is the resource name of QuarksSecretCopyGrant
+	QuarksSecretCopyGrantResourceName = fmt.Sprintf("%s.%s", QuarksSecretCopyGrantResourcePlural, apis.GroupName)
+
+	// QuarksTrustBundleResourceShortNames is the short names of QuarksTrustBundle
+	QuarksTrustBundleResourceShortNames = []string{"qtb", "qtbs"}
+
+	// QuarksTrustBundleValidation is the validation schema for QuarksTrustBundle
+	QuarksTrustBundleValidation = extv1.CustomResourceValidation{
+		OpenAPIV3Schema: &extv1.JSONSchemaProps{
+			Type: "object",
+			Properties: map[string]extv1.JSONSchemaProps{
+				"spec": {
+					Type: "object",
+					Properties: map[string]extv1.JSONSchemaProps{
+						"configMapName": {
+							Type:        "string",
+							Description: "Name of the config map, which holds the bundle",
+							MinLength:   pointers.Int64(1),
+						},
+						"key": {
+							Type:        "string",
+							Description: "Config map key of the bundle, defaults to 'ca-bundle.crt'",
+						},
+						"quarksSecretSelector": {
+							Type:                   "object",
+							Description:            "Selects the quarks secrets, whose CA certificates are bundled",
+							XPreserveUnknownFields: pointers.Bool(true),
+						},
+						"sourceNamespaceSelector": {
+							Type:                   "object",
+							Description:            "Restricts the namespaces of the quarks secrets, defaults to all monitored namespaces",
+							XPreserveUnknownFields: pointers.Bool(true),
+						},
+						"namespaceSelector": {
+							Type:                   "object",
+							Description:            "Selects the namespaces, which receive the bundle, defaults to all monitored namespaces",
+							XPreserveUnknownFields: pointers.Bool(true),
+						},
+					},
+					Required: []string{
+						"configMapName",
+						"quarksSecretSelector",
+					},
+				},
+				"status": {
+					Type:                   "object",
+					XPreserveUnknownFields: pointers.Bool(true),
+				},
+			},
+		},
+	}
+
+	// QuarksTrustBundleAdditionalPrinterColumns are used by `kubectl get`
+	QuarksTrustBundleAdditionalPrinterColumns = []extv1.CustomResourceColumnDefinition{
+		{
+			Name:     "configmap",
+			Type:     "string",
+			JSONPath: ".spec.configMapName",
+		},
+		{
+			Name:     "lastreconcile",
+			Type:     "date",
+			JSONPath: ".status.lastReconcile",
+		},
+		{
+			Name:     "age",
+			Type:     "date",
+			JSONPath: ".metadata.creationTimestamp",
+		},
+	}
+
+	// QuarksTrustBundleResourceName is the resource name of QuarksTrustBundle
+	QuarksTrustBundleResourceName = fmt.Sprintf("%s.%s", QuarksTrustBundleResourcePlural, apis.GroupName)
+
+	// ClusterQuarksSecretResourceShortNames is the short names of ClusterQuarksSecret
+	ClusterQuarksSecretResourceShortNames = []string{"cqsec", "cqsecs"}
+
+	// ClusterQuarksSecretValidation is the validation schema for
+	// ClusterQuarksSecret, which has the same spec as QuarksSecret
+	ClusterQuarksSecretValidation = QuarksSecretValidation
+
+	// ClusterQuarksSecretAdditionalPrinterColumns are used by `kubectl get`
+	ClusterQuarksSecretAdditionalPrinterColumns = QuarksSecretAdditionalPrinterColumns
+
+	// ClusterQuarksSecretResourceName is the resource name of ClusterQuarksSecret
+	ClusterQuarksSecretResourceName = fmt.Sprintf("%s.%s", ClusterQuarksSecretResourcePlural, apis.GroupName)
+
+	// QuarksIssuerResourceShortNames is the short names of QuarksIssuer
+	QuarksIssuerResourceShortNames = []string{"qissuer", "qissuers"}
+
+	// QuarksIssuerValidation is the validation schema for QuarksIssuer
+	QuarksIssuerValidation = extv1.CustomResourceValidation{
+		OpenAPIV3Schema: &extv1.JSONSchemaProps{
+			Type: "object",
+			Properties: map[string]extv1.JSONSchemaProps{
+				"spec": {
+					Type: "object",
+					Properties: map[string]extv1.JSONSchemaProps{
+						"signerType": {
+							Type:        "string",
+							Description: "Signer of the issued certificates, defaults to local",
+							Enum: []extv1.JSON{
+								{Raw: []byte(`"local"`)},
+								{Raw: []byte(`"cluster"`)},
+							},
+						},
+						"signerName": {
+							Type:        "string",
+							Description: "Signer of the CSRs, if the cluster signer is used",
+						},
+						"CARef": {
+							Type:                   "object",
+							Description:            "Secret with the CA certificate of the local signer",
+							XPreserveUnknownFields: pointers.Bool(true),
+						},
+						"CAKeyRef": {
+							Type:                   "object",
+							Description:            "Secret with the CA private key of the local signer",
+							XPreserveUnknownFields: pointers.Bool(true),
+						},
+						"clusterCARef": {
+							Type:        "string",
+							Description: "Name of a ClusterQuarksSecret, whose CA is used by the local signer",
+						},
+						"duration": {
+							Type:        "string",
+							Description: "Default validity of the issued certificates, e.g. 2160h",
+						},
+						"usages": {
+							Type:        "array",
+							Description: "Default key usages of the issued certificates",
+							Items: &extv1.JSONSchemaPropsOrArray{
+								Schema: &extv1.JSONSchemaProps{Type: "string"},
+							},
+						},
+					},
+				},
+				"status": {
+					Type:                   "object",
+					XPreserveUnknownFields: pointers.Bool(true),
+				},
+			},
+		},
+	}
+
+	// QuarksIssuerAdditionalPrinterColumns are used by `kubectl get`
+	QuarksIssuerAdditionalPrinterColumns = []extv1.CustomResourceColumnDefinition{
+		{
+			Name:     "signer",
+			Type:     "string",
+			JSONPath: ".spec.signerType",
+		},
+		{
+			Name:     "age",
+			Type:     "date",
+			JSONPath: ".metadata.creationTimestamp",
+		},
+	}
+
+	// QuarksIssuerResourceName is the resource name of QuarksIssuer
+	QuarksIssuerResourceName = fmt.Sprintf("%s.%s", QuarksIssuerResourcePlural, apis.GroupName)
+
+	// ClusterQuarksIssuerResourceShortNames is the short names of ClusterQuarksIssuer
+	ClusterQuarksIssuerResourceShortNames = []string{"cqissuer", "cqissuers"}
+
+	// ClusterQuarksIssuerValidation is the validation schema for
+	// ClusterQuarksIssuer, which has the same spec as QuarksIssuer
+	ClusterQuarksIssuerValidation = QuarksIssuerValidation
+
+	// ClusterQuarksIssuerAdditionalPrinterColumns are used by `kubectl get`
+	ClusterQuarksIssuerAdditionalPrinterColumns = QuarksIssuerAdditionalPrinterColumns
+
+	// ClusterQuarksIssuerResourceName is the resource name of ClusterQuarksIssuer
+	ClusterQuarksIssuerResourceName = fmt.Sprintf("%s.%s", ClusterQuarksIssuerResourcePlural, apis.GroupName)
+
 	// SchemeGroupVersion is group version used to register these objects
 	SchemeGroupVersion = schema.GroupVersion{Group: apis.GroupName, Version: "v1alpha1"}
 )
@@ -169,6 +649,18 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 	scheme.AddKnownTypes(SchemeGroupVersion,
 		&QuarksSecret{},
 		&QuarksSecretList{},
+		&QuarksSecretRotation{},
+		&QuarksSecretRotationList{},
+		&QuarksSecretCopyGrant{},
+		&QuarksSecretCopyGrantList{},
+		&QuarksTrustBundle{},
+		&QuarksTrustBundleList{},
+		&ClusterQuarksSecret{},
+		&ClusterQuarksSecretList{},
+		&QuarksIssuer{},
+		&QuarksIssuerList{},
+		&ClusterQuarksIssuer{},
+		&ClusterQuarksIssuerList{},
 	)
 
 	metav1.AddToGroupVersion(scheme, SchemeGroupVersion)
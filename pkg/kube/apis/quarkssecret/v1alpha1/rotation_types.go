@@ -0,0 +1,99 @@
+package v1alpha1
+
+import (
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RotationPhase is the state of a QuarksSecretRotation
+type RotationPhase = string
+
+// Valid values for rotation phases
+const (
+	// RotationPhasePlanned means the rotation is a dry run, status.planned
+	// lists what would change
+	RotationPhasePlanned RotationPhase = "Planned"
+	// RotationPhasePendingApproval means the rotation waits for
+	// status.approved to be set
+	RotationPhasePendingApproval RotationPhase = "PendingApproval"
+	// RotationPhaseCompleted means the selected quarks secrets were rotated,
+	// status.results lists what did change. Completed rotations are never run
+	// again.
+	RotationPhaseCompleted RotationPhase = "Completed"
+)
+
+// QuarksSecretRotationSpec selects the quarks secrets to rotate
+type QuarksSecretRotationSpec struct {
+	// SecretNames lists quarks secrets in the rotation's namespace
+	SecretNames []string `json:"secretNames,omitempty"`
+	// Selector selects quarks secrets by labels
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+	// Types selects quarks secrets by their type
+	Types []SecretType `json:"types,omitempty"`
+	// Namespaces are monitored namespaces, in which selector and types are
+	// applied. Defaults to the rotation's namespace. Other namespaces have
+	// to allow rotations from the rotation's namespace.
+	Namespaces []string `json:"namespaces,omitempty"`
+	// DryRun only reports which quarks secrets would be rotated
+	DryRun bool `json:"dryRun,omitempty"`
+	// RequireApproval holds the rotation until status.approved is set
+	RequireApproval bool `json:"requireApproval,omitempty"`
+}
+
+// QuarksSecretRotationStatus defines the observed state of QuarksSecretRotation
+type QuarksSecretRotationStatus struct {
+	Phase RotationPhase `json:"phase,omitempty"`
+	// Approved approves a rotation, which requires approval. It is set
+	// through the status subresource, so approving needs the permission to
+	// update 'quarkssecretrotations/status'. The approval only applies to
+	// the observed generation.
+	Approved bool `json:"approved,omitempty"`
+	// The generation of the spec, which the status refers to
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Planned maps the selected quarks secrets to the result rotating them will have
+	Planned map[string]RotationResult `json:"planned,omitempty"`
+	// Results maps the selected quarks secrets to the result of rotating them
+	Results map[string]RotationResult `json:"results,omitempty"`
+	// Timestamp for when the rotation was completed
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+}
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// QuarksSecretRotation is the Schema for the QuarksSecretRotations API. It
+// triggers the regeneration of the selected quarks secrets.
+// +k8s:openapi-gen=true
+type QuarksSecretRotation struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   QuarksSecretRotationSpec   `json:"spec,omitempty"`
+	Status QuarksSecretRotationStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// QuarksSecretRotationList contains a list of QuarksSecretRotation
+type QuarksSecretRotationList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []QuarksSecretRotation `json:"items"`
+}
+
+// GetNamespacedName returns the resource name with its namespace
+func (r *QuarksSecretRotation) GetNamespacedName() string {
+	return fmt.Sprintf("%s/%s", r.Namespace, r.Name)
+}
+
+// IsCompleted returns true if the rotation is completed, later changes to
+// the spec don't run it again
+func (r *QuarksSecretRotation) IsCompleted() bool {
+	return r.Status.Phase == RotationPhaseCompleted
+}
+
+// IsApproved returns true if the current spec of the rotation was approved
+func (r *QuarksSecretRotation) IsApproved() bool {
+	return r.Status.Approved && r.Status.ObservedGeneration == r.Generation
+}
@@ -0,0 +1,93 @@
+package v1alpha1
+
+import (
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apis "code.cloudfoundry.org/quarks-secret/pkg/kube/apis"
+)
+
+var (
+	// AnnotationTrustBundleOf is set on config maps, which hold a trust bundle
+	AnnotationTrustBundleOf = fmt.Sprintf("%s/trust-bundle-of", apis.GroupName)
+)
+
+const (
+	// TrustBundleConfigMapKind is the kind of config maps, which hold a trust
+	// bundle
+	TrustBundleConfigMapKind = "trust-bundle"
+	// DefaultTrustBundleKey is the config map key of the bundle, if no key
+	// is configured
+	DefaultTrustBundleKey = "ca-bundle.crt"
+)
+
+// QuarksTrustBundleSpec selects the quarks secrets, whose CA certificates
+// are bundled, and the namespaces, which receive the bundle
+type QuarksTrustBundleSpec struct {
+	// ConfigMapName is the name of the config map, which holds the bundle
+	ConfigMapName string `json:"configMapName"`
+	// Key is the config map key of the bundle, defaults to 'ca-bundle.crt'
+	Key string `json:"key,omitempty"`
+	// QuarksSecretSelector selects the certificate quarks secrets by labels
+	QuarksSecretSelector metav1.LabelSelector `json:"quarksSecretSelector"`
+	// SourceNamespaceSelector restricts the namespaces of the quarks
+	// secrets. Defaults to all monitored namespaces.
+	SourceNamespaceSelector *metav1.LabelSelector `json:"sourceNamespaceSelector,omitempty"`
+	// NamespaceSelector selects the namespaces, which receive the bundle.
+	// Defaults to all monitored namespaces.
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+}
+
+// BundledCertificate describes a CA certificate in the trust bundle
+type BundledCertificate struct {
+	// QuarksSecret is the namespaced name of the quarks secret, which
+	// provided the certificate first
+	QuarksSecret string       `json:"quarksSecret"`
+	Subject      string       `json:"subject"`
+	Fingerprint  string       `json:"fingerprint"`
+	NotAfter     *metav1.Time `json:"notAfter,omitempty"`
+}
+
+// QuarksTrustBundleStatus defines the observed state of QuarksTrustBundle
+type QuarksTrustBundleStatus struct {
+	// Certificates lists the certificates in the bundle, in bundle order
+	Certificates []BundledCertificate `json:"certificates,omitempty"`
+	// Namespaces lists the namespaces, which received the bundle
+	Namespaces []string `json:"namespaces,omitempty"`
+	// LastReconcile is the timestamp of the last update of the bundle
+	LastReconcile *metav1.Time `json:"lastReconcile,omitempty"`
+}
+
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// QuarksTrustBundle is the Schema for the QuarksTrustBundles API. It
+// maintains a config map with the merged CA certificates of the selected
+// quarks secrets in the selected namespaces.
+// +k8s:openapi-gen=true
+type QuarksTrustBundle struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   QuarksTrustBundleSpec   `json:"spec,omitempty"`
+	Status QuarksTrustBundleStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// QuarksTrustBundleList contains a list of QuarksTrustBundle
+type QuarksTrustBundleList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []QuarksTrustBundle `json:"items"`
+}
+
+// BundleKey returns the config map key of the bundle
+func (b *QuarksTrustBundle) BundleKey() string {
+	if b.Spec.Key == "" {
+		return DefaultTrustBundleKey
+	}
+	return b.Spec.Key
+}
@@ -3,7 +3,7 @@ package v1alpha1
 import (
 	"fmt"
 
-	certv1 "k8s.io/api/certificates/v1beta1"
+	certv1 "k8s.io/api/certificates/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
@@ -39,6 +39,58 @@ const (
 	TemplatedConfig  SecretType = "templatedconfig"
 )
 
+// WorkloadKind defines the kind of a workload, which can be rolled out
+type WorkloadKind = string
+
+// Valid values for workload kinds
+const (
+	DeploymentKind  WorkloadKind = "Deployment"
+	StatefulSetKind WorkloadKind = "StatefulSet"
+	DaemonSetKind   WorkloadKind = "DaemonSet"
+)
+
+// DriftPolicy defines how the operator reacts, when a generated secret was
+// deleted or its data was modified
+type DriftPolicy = string
+
+// Valid values for drift policies
+const (
+	// DriftRegenerate generates a deleted secret again, with new values.
+	// Modified secrets are only reported, so their consumers keep working.
+	DriftRegenerate DriftPolicy = "regenerate"
+	// DriftRestore restores the secret from a backup, which the operator
+	// keeps next to the generated secret
+	DriftRestore DriftPolicy = "restore"
+	// DriftReportOnly only reports the drift in an event and the status
+	DriftReportOnly DriftPolicy = "report-only"
+)
+
+// ExistingSecretPolicy defines how the operator treats a secret, which
+// already exists and was not generated by the operator
+type ExistingSecretPolicy = string
+
+// Valid values for existing secret policies
+const (
+	// ExistingSecretSkip leaves the existing secret alone
+	ExistingSecretSkip ExistingSecretPolicy = "skip"
+	// ExistingSecretAdopt validates the existing secret, takes ownership and
+	// manages it like a generated secret, without changing its values
+	ExistingSecretAdopt ExistingSecretPolicy = "adopt"
+)
+
+// DeletionPolicy defines what happens to the secrets managed by a quarks
+// secret, when the quarks secret is deleted
+type DeletionPolicy = string
+
+// Valid values for deletion policies
+const (
+	// DeletionDelete deletes the generated secret and its copies
+	DeletionDelete DeletionPolicy = "delete"
+	// DeletionOrphan keeps the generated secret and its copies, they are no
+	// longer managed by the operator
+	DeletionOrphan DeletionPolicy = "orphan"
+)
+
 // SignerType defines the type of the certificate signer
 type SignerType = string
 
@@ -71,21 +123,145 @@ var (
 	// LabelSecretRotationTrigger is set on a config map to trigger secret
 	// rotation. If set, then creating the config map will trigger secret
 	// rotation.
+	// Deprecated: create a QuarksSecretRotation instead.
 	LabelSecretRotationTrigger = fmt.Sprintf("%s/secret-rotation", apis.GroupName)
 	// RotateQSecretListName is the name of the config map entry, which
 	// contains a JSON array of quarks secret names to rotate
 	RotateQSecretListName = "secrets"
+	// RotateQSecretSelectorName is the name of the config map entry, which
+	// contains a label selector for quarks secrets to rotate, e.g. 'app=db'
+	RotateQSecretSelectorName = "selector"
+	// RotateQSecretTypesName is the name of the config map entry, which
+	// contains a JSON array of secret types to rotate, e.g. '["password"]'
+	RotateQSecretTypesName = "types"
+	// RotateQSecretNamespacesName is the name of the config map entry, which
+	// contains a JSON array of monitored namespaces, in which the selector
+	// and types are applied. Defaults to the config map's namespace.
+	RotateQSecretNamespacesName = "namespaces"
+	// AnnotationSecretRotationResult is set on the rotation config map after
+	// processing it. It contains a JSON object, which maps each listed
+	// quarks secret name to its RotationResult.
+	AnnotationSecretRotationResult = fmt.Sprintf("%s/secret-rotation-result", apis.GroupName)
+	// AnnotationSecretRotationDelete can be set to "true" on a rotation
+	// config map, to delete it once it has been processed
+	AnnotationSecretRotationDelete = fmt.Sprintf("%s/secret-rotation-delete", apis.GroupName)
+	// AnnotationContentHash is set on generated secrets, it holds the
+	// checksum of the data the operator generated
+	AnnotationContentHash = fmt.Sprintf("%s/content-hash", apis.GroupName)
+	// AnnotationSecretChecksumPrefix is the prefix of the pod template
+	// annotation key, which holds the checksum of a generated secret
+	AnnotationSecretChecksumPrefix = fmt.Sprintf("%s/checksum-", apis.GroupName)
+	// AnnotationAllowCopiesFrom is set on a namespace to allow the operator
+	// to create copies in it, for quarks secrets with a namespace selector.
+	// It contains a comma separated list of source namespaces or '*'.
+	AnnotationAllowCopiesFrom = fmt.Sprintf("%s/allow-copies-from", apis.GroupName)
+	// AnnotationAllowReferencesFrom is set on a secret or a namespace to
+	// allow quarks secrets in other namespaces to read its values. It
+	// contains a comma separated list of namespaces or '*'.
+	AnnotationAllowReferencesFrom = fmt.Sprintf("%s/allow-references-from", apis.GroupName)
+	// AnnotationAllowRotationsFrom is set on a namespace to allow rotation
+	// requests in other namespaces to rotate its quarks secrets. It
+	// contains a comma separated list of namespaces or '*'.
+	AnnotationAllowRotationsFrom = fmt.Sprintf("%s/allow-rotations-from", apis.GroupName)
+	// AnnotationAllowedCSRNames is set on a namespace to restrict the names
+	// of certificates, which are signed by the cluster signer. It contains
+	// a comma separated list of glob patterns, e.g. '*.svc,*.svc.cluster.local'.
+	AnnotationAllowedCSRNames = fmt.Sprintf("%s/allowed-csr-names", apis.GroupName)
+	// AnnotationInjectCAFrom is set on webhook configurations, API services
+	// and CRDs with a conversion webhook. It holds the namespaced name of a
+	// quarks secret, whose CA is injected into their caBundle.
+	AnnotationInjectCAFrom = fmt.Sprintf("%s/inject-ca-from", apis.GroupName)
+	// AnnotationPublicOutputOf is set on config maps, which hold the public
+	// parts of a generated secret
+	AnnotationPublicOutputOf = fmt.Sprintf("%s/public-output-of", apis.GroupName)
+	// FinalizerCleanup is set on quarks secrets, to clean up copies and
+	// CSR artifacts in other namespaces before they are deleted
+	FinalizerCleanup = fmt.Sprintf("%s/cleanup", apis.GroupName)
 )
 
 const (
 	// GeneratedSecretKind is the kind of generated secret
 	GeneratedSecretKind = "generated"
+	// BackupSecretKind is the kind of secrets, which hold a backup of a
+	// generated secret
+	BackupSecretKind = "backup"
+	// CopySecretKind is the kind of copies, which were created by the
+	// operator in a namespace selected by a copy's namespace selector
+	CopySecretKind = "copy"
+	// PublicConfigMapKind is the kind of config maps, which hold the public
+	// parts of a generated secret
+	PublicConfigMapKind = "public"
+)
+
+// Condition types of a QuarksSecret
+const (
+	// ConditionReady is true, once the secret has been generated and all
+	// other conditions are met
+	ConditionReady = "Ready"
+	// ConditionGenerated is true, once the secret has been generated
+	ConditionGenerated = "Generated"
+	// ConditionCopied is true, once the secret has been copied to all
+	// namespaces listed in the copies
+	ConditionCopied = "Copied"
+	// ConditionCAReady is true, once the CA to sign a certificate was found
+	ConditionCAReady = "CAReady"
+	// ConditionDependenciesReady is true, once the secrets referenced in
+	// the request were found
+	ConditionDependenciesReady = "DependenciesReady"
+	// ConditionPaused is true, while the reconciliation of the quarks secret
+	// is paused
+	ConditionPaused = "Paused"
+)
+
+// Work is the work of a controller, which was deferred while the quarks
+// secret was paused
+type Work = string
+
+// Valid values for deferred work
+const (
+	// WorkGeneration generates the secret
+	WorkGeneration Work = "generation"
+	// WorkCopy copies the secret to the copies' namespaces
+	WorkCopy Work = "copy"
+	// WorkSecretMeta applies the secret labels and annotations
+	WorkSecretMeta Work = "secret-metadata"
+	// WorkCertificate approves the CSR and creates the certificate secret
+	WorkCertificate Work = "certificate"
+	// WorkRollout rolls out the workloads
+	WorkRollout Work = "rollout"
+	// WorkDriftRepair repairs a deleted or modified secret
+	WorkDriftRepair Work = "drift-repair"
+	// WorkPublicOutput publishes the public parts of the secret
+	WorkPublicOutput Work = "public-output"
+	// WorkCAInjection injects the CA into the caBundle of annotated resources
+	WorkCAInjection Work = "ca-injection"
+)
+
+// RotationResult is the outcome of rotating a single quarks secret
+type RotationResult = string
+
+// Valid values for rotation results
+const (
+	// RotationRotated means the secret will be regenerated
+	RotationRotated RotationResult = "rotated"
+	// RotationSkippedNotGenerated means the secret was not generated by the
+	// operator or has not been generated yet
+	RotationSkippedNotGenerated RotationResult = "skipped-not-generated"
+	// RotationNotFound means the quarks secret does not exist
+	RotationNotFound RotationResult = "not-found"
+	// RotationFailed means the quarks secret could not be rotated, the
+	// error is reported in an event
+	RotationFailed RotationResult = "failed"
 )
 
 // SecretReference specifies a reference to another secret
 type SecretReference struct {
 	Name string `json:"name"`
 	Key  string `json:"key"`
+	// Namespace of the secret, defaults to the namespace of the quarks
+	// secret. The secret or its namespace has to allow references from
+	// other namespaces with AnnotationAllowReferencesFrom.
+	Namespace string `json:"namespace,omitempty"`
 }
 
 // ServiceReference specifies a reference to a service
@@ -95,13 +271,27 @@ type ServiceReference struct {
 
 // CertificateRequest specifies the details for the certificate generation
 type CertificateRequest struct {
-	CommonName                  string             `json:"commonName"`
-	AlternativeNames            []string           `json:"alternativeNames"`
-	IsCA                        bool               `json:"isCA"`
-	CARef                       SecretReference    `json:"CARef"`
-	CAKeyRef                    SecretReference    `json:"CAKeyRef"`
-	SignerType                  SignerType         `json:"signerType,omitempty"`
-	Usages                      []certv1.KeyUsage  `json:"usages"`
+	CommonName       string          `json:"commonName"`
+	AlternativeNames []string        `json:"alternativeNames"`
+	IsCA             bool            `json:"isCA"`
+	CARef            SecretReference `json:"CARef"`
+	CAKeyRef         SecretReference `json:"CAKeyRef"`
+	// ClusterCARef is the name of a ClusterQuarksSecret, whose CA signs the
+	// certificate. It can't be combined with CARef.
+	ClusterCARef string `json:"clusterCARef,omitempty"`
+	// IssuerRef references the issuer, which signs the certificate. It
+	// can't be combined with the signer fields CARef, CAKeyRef,
+	// clusterCARef, signerType and signerName.
+	IssuerRef  *IssuerReference `json:"issuerRef,omitempty"`
+	SignerType SignerType       `json:"signerType,omitempty"`
+	// SignerName is the signer of the CSR, if the cluster signer is used,
+	// e.g. 'kubernetes.io/kubelet-serving'. Without it, the CSR is created
+	// with certificates.k8s.io/v1beta1 for the legacy-unknown signer.
+	SignerName string            `json:"signerName,omitempty"`
+	Usages     []certv1.KeyUsage `json:"usages"`
+	// Duration is the validity of the certificate. The cluster signer
+	// receives it as the requested expiration of the CSR.
+	Duration                    *metav1.Duration   `json:"duration,omitempty"`
 	ServiceRef                  []ServiceReference `json:"serviceRef"`
 	ActivateEKSWorkaroundForSAN bool               `json:"activateEKSWorkaroundForSAN,omitempty"`
 }
@@ -139,13 +329,44 @@ type Request struct {
 // We can't use types.NamespacedName because it doesn't marshal properly
 type Copy struct {
 	Name      string `json:"name"`
-	Namespace string `json:"namespace"`
+	Namespace string `json:"namespace,omitempty"`
+	// NamespaceSelector selects the namespaces to copy to, instead of a
+	// single namespace. The operator creates the copies in the selected
+	// namespaces, which allow copies from the quarks secret's namespace.
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+	// Keys maps the keys of the generated secret, which are copied, to
+	// their name in the copy. An empty name keeps the key's name. All keys
+	// are copied, if no keys are listed.
+	Keys map[string]string `json:"keys,omitempty"`
 }
 
 func (c *Copy) String() string {
 	return fmt.Sprintf("%s/%s", c.Namespace, c.Name)
 }
 
+// Workload references a workload in the namespace of the QuarksSecret
+type Workload struct {
+	Kind WorkloadKind `json:"kind"`
+	Name string       `json:"name"`
+}
+
+// RolloutTargets lists the workloads, which are rolled out after the
+// generated secret changed. Workloads can be listed explicitly or selected
+// by labels.
+type RolloutTargets struct {
+	Workloads []Workload            `json:"workloads,omitempty"`
+	Selector  *metav1.LabelSelector `json:"selector,omitempty"`
+}
+
+// PublicOutput publishes the public parts of a generated secret, like
+// certificates and public keys, in a config map
+type PublicOutput struct {
+	ConfigMapName string `json:"configMapName"`
+	// NamespaceSelector selects namespaces, to which the config map is
+	// copied. An empty selector selects all namespaces.
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+}
+
 // QuarksSecretSpec defines the desired state of QuarksSecret
 type QuarksSecretSpec struct {
 	Type              SecretType        `json:"type"`
@@ -154,6 +375,33 @@ type QuarksSecretSpec struct {
 	Copies            []Copy            `json:"copies,omitempty"`
 	SecretLabels      map[string]string `json:"secretLabels,omitempty"`
 	SecretAnnotations map[string]string `json:"secretAnnotations,omitempty"`
+	RolloutTargets    *RolloutTargets   `json:"rolloutTargets,omitempty"`
+	// DriftPolicy defaults to regenerate
+	DriftPolicy DriftPolicy `json:"driftPolicy,omitempty"`
+	// ExistingSecretPolicy defaults to skip
+	ExistingSecretPolicy ExistingSecretPolicy `json:"existingSecretPolicy,omitempty"`
+	// Paused stops all controllers from reconciling the quarks secret, the
+	// deferred work is applied once it is resumed
+	Paused bool `json:"paused,omitempty"`
+	// ProvidedValues maps keys of the generated secret to keys in other
+	// secrets. These values are taken as they are, only the missing keys
+	// are generated.
+	ProvidedValues map[string]SecretReference `json:"providedValues,omitempty"`
+	// DeletionPolicy defaults to delete
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
+	PublicOutput   *PublicOutput  `json:"publicOutput,omitempty"`
+}
+
+// CertificateStatus describes a generated certificate
+type CertificateStatus struct {
+	SerialNumber     string       `json:"serialNumber,omitempty"`
+	Issuer           string       `json:"issuer,omitempty"`
+	Subject          string       `json:"subject,omitempty"`
+	NotBefore        *metav1.Time `json:"notBefore,omitempty"`
+	NotAfter         *metav1.Time `json:"notAfter,omitempty"`
+	AlternativeNames []string     `json:"alternativeNames,omitempty"`
+	// SHA256 fingerprint of the DER encoded certificate
+	Fingerprint string `json:"fingerprint,omitempty"`
 }
 
 // QuarksSecretStatus defines the observed state of QuarksSecret
@@ -164,6 +412,29 @@ type QuarksSecretStatus struct {
 	Generated *bool `json:"generated"`
 	// Indicates if the copy secrets have been updated
 	Copied *bool `json:"copied"`
+	// Copies, which received the secret. They are pruned, once they are
+	// removed from the spec.
+	Copies []Copy `json:"copies,omitempty"`
+	// Copies, which were denied, because they are not granted
+	DeniedCopies []Copy `json:"deniedCopies,omitempty"`
+	// Checksum of the request and the referenced secret values, which were
+	// used to render the secret
+	InputsHash string `json:"inputsHash,omitempty"`
+	// Metadata of the generated certificate
+	Certificate *CertificateStatus `json:"certificate,omitempty"`
+	// SHA256 fingerprint of the generated RSA or SSH public key
+	KeyFingerprint string `json:"keyFingerprint,omitempty"`
+	// Timestamp for when the data of the generated secret was found to be
+	// modified, reset once it matches again
+	DriftDetected *metav1.Time `json:"driftDetected,omitempty"`
+	// Work, which was deferred while the quarks secret was paused
+	PendingWork []Work `json:"pendingWork,omitempty"`
+	// The generation of the spec, which was last reconciled
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Conditions describe the state of the generated secret
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
 // IsCopied returns true if the copied field is a true value
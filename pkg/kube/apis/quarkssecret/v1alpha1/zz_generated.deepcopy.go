@@ -10,7 +10,8 @@ Don't alter this file, it was generated.
 package v1alpha1
 
 import (
-	v1beta1 "k8s.io/api/certificates/v1beta1"
+	v1 "k8s.io/api/certificates/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -30,6 +31,26 @@ func (in *BasicAuthRequest) DeepCopy() *BasicAuthRequest {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BundledCertificate) DeepCopyInto(out *BundledCertificate) {
+	*out = *in
+	if in.NotAfter != nil {
+		in, out := &in.NotAfter, &out.NotAfter
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BundledCertificate.
+func (in *BundledCertificate) DeepCopy() *BundledCertificate {
+	if in == nil {
+		return nil
+	}
+	out := new(BundledCertificate)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CertificateRequest) DeepCopyInto(out *CertificateRequest) {
 	*out = *in
@@ -40,11 +61,21 @@ func (in *CertificateRequest) DeepCopyInto(out *CertificateRequest) {
 	}
 	out.CARef = in.CARef
 	out.CAKeyRef = in.CAKeyRef
+	if in.IssuerRef != nil {
+		in, out := &in.IssuerRef, &out.IssuerRef
+		*out = new(IssuerReference)
+		**out = **in
+	}
 	if in.Usages != nil {
 		in, out := &in.Usages, &out.Usages
-		*out = make([]v1beta1.KeyUsage, len(*in))
+		*out = make([]v1.KeyUsage, len(*in))
 		copy(*out, *in)
 	}
+	if in.Duration != nil {
+		in, out := &in.Duration, &out.Duration
+		*out = new(metav1.Duration)
+		**out = **in
+	}
 	if in.ServiceRef != nil {
 		in, out := &in.ServiceRef, &out.ServiceRef
 		*out = make([]ServiceReference, len(*in))
@@ -58,74 +89,526 @@ func (in *CertificateRequest) DeepCopy() *CertificateRequest {
 	if in == nil {
 		return nil
 	}
-	out := new(CertificateRequest)
-	in.DeepCopyInto(out)
-	return out
+	out := new(CertificateRequest)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertificateStatus) DeepCopyInto(out *CertificateStatus) {
+	*out = *in
+	if in.NotBefore != nil {
+		in, out := &in.NotBefore, &out.NotBefore
+		*out = (*in).DeepCopy()
+	}
+	if in.NotAfter != nil {
+		in, out := &in.NotAfter, &out.NotAfter
+		*out = (*in).DeepCopy()
+	}
+	if in.AlternativeNames != nil {
+		in, out := &in.AlternativeNames, &out.AlternativeNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificateStatus.
+func (in *CertificateStatus) DeepCopy() *CertificateStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CertificateStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterQuarksIssuer) DeepCopyInto(out *ClusterQuarksIssuer) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	out.Status = in.Status
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterQuarksIssuer.
+func (in *ClusterQuarksIssuer) DeepCopy() *ClusterQuarksIssuer {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterQuarksIssuer)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterQuarksIssuer) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterQuarksIssuerList) DeepCopyInto(out *ClusterQuarksIssuerList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ClusterQuarksIssuer, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterQuarksIssuerList.
+func (in *ClusterQuarksIssuerList) DeepCopy() *ClusterQuarksIssuerList {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterQuarksIssuerList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterQuarksIssuerList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterQuarksSecret) DeepCopyInto(out *ClusterQuarksSecret) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterQuarksSecret.
+func (in *ClusterQuarksSecret) DeepCopy() *ClusterQuarksSecret {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterQuarksSecret)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterQuarksSecret) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterQuarksSecretList) DeepCopyInto(out *ClusterQuarksSecretList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ClusterQuarksSecret, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterQuarksSecretList.
+func (in *ClusterQuarksSecretList) DeepCopy() *ClusterQuarksSecretList {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterQuarksSecretList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterQuarksSecretList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Copy) DeepCopyInto(out *Copy) {
+	*out = *in
+	if in.NamespaceSelector != nil {
+		in, out := &in.NamespaceSelector, &out.NamespaceSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Keys != nil {
+		in, out := &in.Keys, &out.Keys
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Copy.
+func (in *Copy) DeepCopy() *Copy {
+	if in == nil {
+		return nil
+	}
+	out := new(Copy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageCredentialsRequest) DeepCopyInto(out *ImageCredentialsRequest) {
+	*out = *in
+	out.Username = in.Username
+	out.Password = in.Password
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageCredentialsRequest.
+func (in *ImageCredentialsRequest) DeepCopy() *ImageCredentialsRequest {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageCredentialsRequest)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IssuerReference) DeepCopyInto(out *IssuerReference) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IssuerReference.
+func (in *IssuerReference) DeepCopy() *IssuerReference {
+	if in == nil {
+		return nil
+	}
+	out := new(IssuerReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PublicOutput) DeepCopyInto(out *PublicOutput) {
+	*out = *in
+	if in.NamespaceSelector != nil {
+		in, out := &in.NamespaceSelector, &out.NamespaceSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PublicOutput.
+func (in *PublicOutput) DeepCopy() *PublicOutput {
+	if in == nil {
+		return nil
+	}
+	out := new(PublicOutput)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QuarksIssuer) DeepCopyInto(out *QuarksIssuer) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	out.Status = in.Status
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QuarksIssuer.
+func (in *QuarksIssuer) DeepCopy() *QuarksIssuer {
+	if in == nil {
+		return nil
+	}
+	out := new(QuarksIssuer)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *QuarksIssuer) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QuarksIssuerList) DeepCopyInto(out *QuarksIssuerList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]QuarksIssuer, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QuarksIssuerList.
+func (in *QuarksIssuerList) DeepCopy() *QuarksIssuerList {
+	if in == nil {
+		return nil
+	}
+	out := new(QuarksIssuerList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *QuarksIssuerList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QuarksIssuerSpec) DeepCopyInto(out *QuarksIssuerSpec) {
+	*out = *in
+	out.CARef = in.CARef
+	out.CAKeyRef = in.CAKeyRef
+	if in.Duration != nil {
+		in, out := &in.Duration, &out.Duration
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.Usages != nil {
+		in, out := &in.Usages, &out.Usages
+		*out = make([]v1.KeyUsage, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QuarksIssuerSpec.
+func (in *QuarksIssuerSpec) DeepCopy() *QuarksIssuerSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(QuarksIssuerSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QuarksIssuerStatus) DeepCopyInto(out *QuarksIssuerStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QuarksIssuerStatus.
+func (in *QuarksIssuerStatus) DeepCopy() *QuarksIssuerStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(QuarksIssuerStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QuarksSecret) DeepCopyInto(out *QuarksSecret) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	if in.SecretLabels != nil {
+		in, out := &in.SecretLabels, &out.SecretLabels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QuarksSecret.
+func (in *QuarksSecret) DeepCopy() *QuarksSecret {
+	if in == nil {
+		return nil
+	}
+	out := new(QuarksSecret)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *QuarksSecret) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QuarksSecretCopyGrant) DeepCopyInto(out *QuarksSecretCopyGrant) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QuarksSecretCopyGrant.
+func (in *QuarksSecretCopyGrant) DeepCopy() *QuarksSecretCopyGrant {
+	if in == nil {
+		return nil
+	}
+	out := new(QuarksSecretCopyGrant)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *QuarksSecretCopyGrant) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QuarksSecretCopyGrantList) DeepCopyInto(out *QuarksSecretCopyGrantList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]QuarksSecretCopyGrant, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QuarksSecretCopyGrantList.
+func (in *QuarksSecretCopyGrantList) DeepCopy() *QuarksSecretCopyGrantList {
+	if in == nil {
+		return nil
+	}
+	out := new(QuarksSecretCopyGrantList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *QuarksSecretCopyGrantList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Copy) DeepCopyInto(out *Copy) {
+func (in *QuarksSecretCopyGrantSpec) DeepCopyInto(out *QuarksSecretCopyGrantSpec) {
 	*out = *in
+	if in.QuarksSecretNames != nil {
+		in, out := &in.QuarksSecretNames, &out.QuarksSecretNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Copy.
-func (in *Copy) DeepCopy() *Copy {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QuarksSecretCopyGrantSpec.
+func (in *QuarksSecretCopyGrantSpec) DeepCopy() *QuarksSecretCopyGrantSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(Copy)
+	out := new(QuarksSecretCopyGrantSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ImageCredentialsRequest) DeepCopyInto(out *ImageCredentialsRequest) {
+func (in *QuarksSecretList) DeepCopyInto(out *QuarksSecretList) {
 	*out = *in
-	out.Username = in.Username
-	out.Password = in.Password
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]QuarksSecret, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageCredentialsRequest.
-func (in *ImageCredentialsRequest) DeepCopy() *ImageCredentialsRequest {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QuarksSecretList.
+func (in *QuarksSecretList) DeepCopy() *QuarksSecretList {
 	if in == nil {
 		return nil
 	}
-	out := new(ImageCredentialsRequest)
+	out := new(QuarksSecretList)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *QuarksSecretList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *QuarksSecret) DeepCopyInto(out *QuarksSecret) {
+func (in *QuarksSecretRotation) DeepCopyInto(out *QuarksSecretRotation) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
 	in.Status.DeepCopyInto(&out.Status)
-	if in.SecretLabels != nil {
-		in, out := &in.SecretLabels, &out.SecretLabels
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QuarksSecret.
-func (in *QuarksSecret) DeepCopy() *QuarksSecret {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QuarksSecretRotation.
+func (in *QuarksSecretRotation) DeepCopy() *QuarksSecretRotation {
 	if in == nil {
 		return nil
 	}
-	out := new(QuarksSecret)
+	out := new(QuarksSecretRotation)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *QuarksSecret) DeepCopyObject() runtime.Object {
+func (in *QuarksSecretRotation) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -133,13 +616,13 @@ func (in *QuarksSecret) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *QuarksSecretList) DeepCopyInto(out *QuarksSecretList) {
+func (in *QuarksSecretRotationList) DeepCopyInto(out *QuarksSecretRotationList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]QuarksSecret, len(*in))
+		*out = make([]QuarksSecretRotation, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -147,24 +630,94 @@ func (in *QuarksSecretList) DeepCopyInto(out *QuarksSecretList) {
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QuarksSecretList.
-func (in *QuarksSecretList) DeepCopy() *QuarksSecretList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QuarksSecretRotationList.
+func (in *QuarksSecretRotationList) DeepCopy() *QuarksSecretRotationList {
 	if in == nil {
 		return nil
 	}
-	out := new(QuarksSecretList)
+	out := new(QuarksSecretRotationList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *QuarksSecretList) DeepCopyObject() runtime.Object {
+func (in *QuarksSecretRotationList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QuarksSecretRotationSpec) DeepCopyInto(out *QuarksSecretRotationSpec) {
+	*out = *in
+	if in.SecretNames != nil {
+		in, out := &in.SecretNames, &out.SecretNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Types != nil {
+		in, out := &in.Types, &out.Types
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QuarksSecretRotationSpec.
+func (in *QuarksSecretRotationSpec) DeepCopy() *QuarksSecretRotationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(QuarksSecretRotationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QuarksSecretRotationStatus) DeepCopyInto(out *QuarksSecretRotationStatus) {
+	*out = *in
+	if in.Planned != nil {
+		in, out := &in.Planned, &out.Planned
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Results != nil {
+		in, out := &in.Results, &out.Results
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QuarksSecretRotationStatus.
+func (in *QuarksSecretRotationStatus) DeepCopy() *QuarksSecretRotationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(QuarksSecretRotationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *QuarksSecretSpec) DeepCopyInto(out *QuarksSecretSpec) {
 	*out = *in
@@ -172,7 +725,9 @@ func (in *QuarksSecretSpec) DeepCopyInto(out *QuarksSecretSpec) {
 	if in.Copies != nil {
 		in, out := &in.Copies, &out.Copies
 		*out = make([]Copy, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 	if in.SecretLabels != nil {
 		in, out := &in.SecretLabels, &out.SecretLabels
@@ -188,6 +743,23 @@ func (in *QuarksSecretSpec) DeepCopyInto(out *QuarksSecretSpec) {
 			(*out)[key] = val
 		}
 	}
+	if in.RolloutTargets != nil {
+		in, out := &in.RolloutTargets, &out.RolloutTargets
+		*out = new(RolloutTargets)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ProvidedValues != nil {
+		in, out := &in.ProvidedValues, &out.ProvidedValues
+		*out = make(map[string]SecretReference, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.PublicOutput != nil {
+		in, out := &in.PublicOutput, &out.PublicOutput
+		*out = new(PublicOutput)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -213,6 +785,46 @@ func (in *QuarksSecretStatus) DeepCopyInto(out *QuarksSecretStatus) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.Copied != nil {
+		in, out := &in.Copied, &out.Copied
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Copies != nil {
+		in, out := &in.Copies, &out.Copies
+		*out = make([]Copy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.DeniedCopies != nil {
+		in, out := &in.DeniedCopies, &out.DeniedCopies
+		*out = make([]Copy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Certificate != nil {
+		in, out := &in.Certificate, &out.Certificate
+		*out = new(CertificateStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DriftDetected != nil {
+		in, out := &in.DriftDetected, &out.DriftDetected
+		*out = (*in).DeepCopy()
+	}
+	if in.PendingWork != nil {
+		in, out := &in.PendingWork, &out.PendingWork
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -226,6 +838,126 @@ func (in *QuarksSecretStatus) DeepCopy() *QuarksSecretStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QuarksTrustBundle) DeepCopyInto(out *QuarksTrustBundle) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QuarksTrustBundle.
+func (in *QuarksTrustBundle) DeepCopy() *QuarksTrustBundle {
+	if in == nil {
+		return nil
+	}
+	out := new(QuarksTrustBundle)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *QuarksTrustBundle) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QuarksTrustBundleList) DeepCopyInto(out *QuarksTrustBundleList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]QuarksTrustBundle, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QuarksTrustBundleList.
+func (in *QuarksTrustBundleList) DeepCopy() *QuarksTrustBundleList {
+	if in == nil {
+		return nil
+	}
+	out := new(QuarksTrustBundleList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *QuarksTrustBundleList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QuarksTrustBundleSpec) DeepCopyInto(out *QuarksTrustBundleSpec) {
+	*out = *in
+	in.QuarksSecretSelector.DeepCopyInto(&out.QuarksSecretSelector)
+	if in.SourceNamespaceSelector != nil {
+		in, out := &in.SourceNamespaceSelector, &out.SourceNamespaceSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NamespaceSelector != nil {
+		in, out := &in.NamespaceSelector, &out.NamespaceSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QuarksTrustBundleSpec.
+func (in *QuarksTrustBundleSpec) DeepCopy() *QuarksTrustBundleSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(QuarksTrustBundleSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QuarksTrustBundleStatus) DeepCopyInto(out *QuarksTrustBundleStatus) {
+	*out = *in
+	if in.Certificates != nil {
+		in, out := &in.Certificates, &out.Certificates
+		*out = make([]BundledCertificate, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.LastReconcile != nil {
+		in, out := &in.LastReconcile, &out.LastReconcile
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QuarksTrustBundleStatus.
+func (in *QuarksTrustBundleStatus) DeepCopy() *QuarksTrustBundleStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(QuarksTrustBundleStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Request) DeepCopyInto(out *Request) {
 	*out = *in
@@ -246,6 +978,32 @@ func (in *Request) DeepCopy() *Request {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutTargets) DeepCopyInto(out *RolloutTargets) {
+	*out = *in
+	if in.Workloads != nil {
+		in, out := &in.Workloads, &out.Workloads
+		*out = make([]Workload, len(*in))
+		copy(*out, *in)
+	}
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutTargets.
+func (in *RolloutTargets) DeepCopy() *RolloutTargets {
+	if in == nil {
+		return nil
+	}
+	out := new(RolloutTargets)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SecretReference) DeepCopyInto(out *SecretReference) {
 	*out = *in
@@ -307,3 +1065,19 @@ func (in *TemplatedConfigRequest) DeepCopy() *TemplatedConfigRequest {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Workload) DeepCopyInto(out *Workload) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Workload.
+func (in *Workload) DeepCopy() *Workload {
+	if in == nil {
+		return nil
+	}
+	out := new(Workload)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -21,6 +21,30 @@ func (c *FakeQuarkssecretV1alpha1) QuarksSecrets(namespace string) v1alpha1.Quar
 	return &FakeQuarksSecrets{c, namespace}
 }
 
+func (c *FakeQuarkssecretV1alpha1) ClusterQuarksIssuers() v1alpha1.ClusterQuarksIssuerInterface {
+	return &FakeClusterQuarksIssuers{c}
+}
+
+func (c *FakeQuarkssecretV1alpha1) QuarksIssuers(namespace string) v1alpha1.QuarksIssuerInterface {
+	return &FakeQuarksIssuers{c, namespace}
+}
+
+func (c *FakeQuarkssecretV1alpha1) ClusterQuarksSecrets() v1alpha1.ClusterQuarksSecretInterface {
+	return &FakeClusterQuarksSecrets{c}
+}
+
+func (c *FakeQuarkssecretV1alpha1) QuarksTrustBundles() v1alpha1.QuarksTrustBundleInterface {
+	return &FakeQuarksTrustBundles{c}
+}
+
+func (c *FakeQuarkssecretV1alpha1) QuarksSecretCopyGrants(namespace string) v1alpha1.QuarksSecretCopyGrantInterface {
+	return &FakeQuarksSecretCopyGrants{c, namespace}
+}
+
+func (c *FakeQuarkssecretV1alpha1) QuarksSecretRotations(namespace string) v1alpha1.QuarksSecretRotationInterface {
+	return &FakeQuarksSecretRotations{c, namespace}
+}
+
 // RESTClient returns a RESTClient that is used to communicate
 // with API server by this client implementation.
 func (c *FakeQuarkssecretV1alpha1) RESTClient() rest.Interface {
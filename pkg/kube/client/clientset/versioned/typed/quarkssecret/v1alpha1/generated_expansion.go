@@ -8,3 +8,15 @@ Don't alter this file, it was generated.
 package v1alpha1
 
 type QuarksSecretExpansion interface{}
+
+type QuarksSecretRotationExpansion interface{}
+
+type QuarksSecretCopyGrantExpansion interface{}
+
+type QuarksTrustBundleExpansion interface{}
+
+type ClusterQuarksSecretExpansion interface{}
+
+type QuarksIssuerExpansion interface{}
+
+type ClusterQuarksIssuerExpansion interface{}
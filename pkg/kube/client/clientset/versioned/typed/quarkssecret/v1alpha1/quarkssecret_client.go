@@ -16,6 +16,12 @@ import (
 type QuarkssecretV1alpha1Interface interface {
 	RESTClient() rest.Interface
 	QuarksSecretsGetter
+	ClusterQuarksIssuersGetter
+	QuarksIssuersGetter
+	ClusterQuarksSecretsGetter
+	QuarksTrustBundlesGetter
+	QuarksSecretCopyGrantsGetter
+	QuarksSecretRotationsGetter
 }
 
 // QuarkssecretV1alpha1Client is used to interact with features provided by the quarkssecret group.
@@ -27,6 +33,30 @@ func (c *QuarkssecretV1alpha1Client) QuarksSecrets(namespace string) QuarksSecre
 	return newQuarksSecrets(c, namespace)
 }
 
+func (c *QuarkssecretV1alpha1Client) ClusterQuarksIssuers() ClusterQuarksIssuerInterface {
+	return newClusterQuarksIssuers(c)
+}
+
+func (c *QuarkssecretV1alpha1Client) QuarksIssuers(namespace string) QuarksIssuerInterface {
+	return newQuarksIssuers(c, namespace)
+}
+
+func (c *QuarkssecretV1alpha1Client) ClusterQuarksSecrets() ClusterQuarksSecretInterface {
+	return newClusterQuarksSecrets(c)
+}
+
+func (c *QuarkssecretV1alpha1Client) QuarksTrustBundles() QuarksTrustBundleInterface {
+	return newQuarksTrustBundles(c)
+}
+
+func (c *QuarkssecretV1alpha1Client) QuarksSecretCopyGrants(namespace string) QuarksSecretCopyGrantInterface {
+	return newQuarksSecretCopyGrants(c, namespace)
+}
+
+func (c *QuarkssecretV1alpha1Client) QuarksSecretRotations(namespace string) QuarksSecretRotationInterface {
+	return newQuarksSecretRotations(c, namespace)
+}
+
 // NewForConfig creates a new QuarkssecretV1alpha1Client for the given config.
 func NewForConfig(c *rest.Config) (*QuarkssecretV1alpha1Client, error) {
 	config := *c
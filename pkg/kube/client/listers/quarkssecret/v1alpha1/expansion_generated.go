@@ -14,3 +14,39 @@ type QuarksSecretListerExpansion interface{}
 // QuarksSecretNamespaceListerExpansion allows custom methods to be added to
 // QuarksSecretNamespaceLister.
 type QuarksSecretNamespaceListerExpansion interface{}
+
+// QuarksSecretRotationListerExpansion allows custom methods to be added to
+// QuarksSecretRotationLister.
+type QuarksSecretRotationListerExpansion interface{}
+
+// QuarksSecretRotationNamespaceListerExpansion allows custom methods to be added to
+// QuarksSecretRotationNamespaceLister.
+type QuarksSecretRotationNamespaceListerExpansion interface{}
+
+// QuarksSecretCopyGrantListerExpansion allows custom methods to be added to
+// QuarksSecretCopyGrantLister.
+type QuarksSecretCopyGrantListerExpansion interface{}
+
+// QuarksSecretCopyGrantNamespaceListerExpansion allows custom methods to be added to
+// QuarksSecretCopyGrantNamespaceLister.
+type QuarksSecretCopyGrantNamespaceListerExpansion interface{}
+
+// QuarksTrustBundleListerExpansion allows custom methods to be added to
+// QuarksTrustBundleLister.
+type QuarksTrustBundleListerExpansion interface{}
+
+// ClusterQuarksSecretListerExpansion allows custom methods to be added to
+// ClusterQuarksSecretLister.
+type ClusterQuarksSecretListerExpansion interface{}
+
+// QuarksIssuerListerExpansion allows custom methods to be added to
+// QuarksIssuerLister.
+type QuarksIssuerListerExpansion interface{}
+
+// QuarksIssuerNamespaceListerExpansion allows custom methods to be added to
+// QuarksIssuerNamespaceLister.
+type QuarksIssuerNamespaceListerExpansion interface{}
+
+// ClusterQuarksIssuerListerExpansion allows custom methods to be added to
+// ClusterQuarksIssuerLister.
+type ClusterQuarksIssuerListerExpansion interface{}
@@ -16,24 +16,42 @@ import (
 // itself is started.
 var addToManagerFuncs = []func(context.Context, *config.Config, manager.Manager) error{
 	quarkssecret.AddCertificateSigningRequest,
-	quarkssecret.AddCopy,
 	quarkssecret.AddQuarksSecret,
 	quarkssecret.AddSecretRotation,
+	quarkssecret.AddQuarksSecretRotation,
 	quarkssecret.AddQuarksSecretSecretMeta,
+	quarkssecret.AddRollout,
+	quarkssecret.AddDrift,
+	quarkssecret.AddPause,
+	quarkssecret.AddCleanup,
+	quarkssecret.AddPublicOutput,
+	quarkssecret.AddTrustBundle,
+	quarkssecret.AddCAInjection,
+	quarkssecret.AddClusterQuarksSecret,
+	quarkssecret.AddIssuer,
+	quarkssecret.AddCertificateSigner,
 }
 
 var addToSchemes = runtime.SchemeBuilder{
 	qsv1a1.AddToScheme,
 }
 
+// Options are the options of the controllers, which are not part of the
+// config shared with the other quarks operators
+type Options struct {
+	// AllowUngrantedCopies copies quarks secrets to other namespaces without
+	// a QuarksSecretCopyGrant, unless their namespace contains one
+	AllowUngrantedCopies bool
+}
+
 // AddToManager adds all Controllers to the Manager
-func AddToManager(ctx context.Context, config *config.Config, m manager.Manager) error {
+func AddToManager(ctx context.Context, config *config.Config, m manager.Manager, options Options) error {
 	for _, f := range addToManagerFuncs {
 		if err := f(ctx, config, m); err != nil {
 			return err
 		}
 	}
-	return nil
+	return quarkssecret.AddCopy(ctx, config, m, options.AllowUngrantedCopies)
 }
 
 // AddToScheme adds all Resources to the Scheme
@@ -0,0 +1,258 @@
+package quarkssecret
+
+import (
+	"context"
+	"crypto"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/ssh"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	qsv1a1 "code.cloudfoundry.org/quarks-secret/pkg/kube/apis/quarkssecret/v1alpha1"
+	"code.cloudfoundry.org/quarks-utils/pkg/ctxlog"
+)
+
+// reasonAdoptionFailed is the reason of the Generated condition, if an
+// existing secret could not be adopted
+const reasonAdoptionFailed = "AdoptionFailed"
+
+// adoptSecret takes ownership of an existing secret, which was not generated
+// by the operator, if the quarks secret's existingSecretPolicy is adopt. The
+// values of the secret are validated against the type of the quarks secret,
+// but never changed. Returns true, if the secret was adopted.
+func (r *ReconcileQuarksSecret) adoptSecret(ctx context.Context, qsec *qsv1a1.QuarksSecret) (bool, error) {
+	if qsec.Spec.ExistingSecretPolicy != qsv1a1.ExistingSecretAdopt {
+		return false, nil
+	}
+
+	secret := &corev1.Secret{}
+	err := r.client.Get(ctx, types.NamespacedName{Name: qsec.Spec.SecretName, Namespace: qsec.GetNamespace()}, secret)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, errors.Wrapf(err, "could not get secret '%s/%s'", qsec.GetNamespace(), qsec.Spec.SecretName)
+	}
+
+	if secret.GetLabels()[qsv1a1.LabelKind] == qsv1a1.GeneratedSecretKind {
+		return false, nil
+	}
+
+	if err := validateSecret(qsec, secret); err != nil {
+		return false, ctxlog.WithEvent(qsec, "AdoptionFailed").Errorf(ctx, "Cannot adopt secret '%s/%s': %s", secret.Namespace, secret.Name, err)
+	}
+
+	// store the hash of the current inputs, so the adopted values are not
+	// rendered again
+	hash, err := r.adoptedInputsHash(ctx, qsec)
+	if err != nil {
+		return false, errors.Wrapf(err, "could not read inputs of QuarksSecret '%s'", qsec.GetNamespacedName())
+	}
+
+	labels := secret.GetLabels()
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	for k, v := range qsec.Spec.SecretLabels {
+		labels[k] = v
+	}
+	labels[qsv1a1.LabelKind] = qsv1a1.GeneratedSecretKind
+	secret.SetLabels(labels)
+
+	annotations := secret.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	for k, v := range qsec.Spec.SecretAnnotations {
+		annotations[k] = v
+	}
+	secret.SetAnnotations(annotations)
+
+	if err := r.setReference(qsec, secret, r.scheme); err != nil {
+		return false, errors.Wrapf(err, "error setting owner for secret '%s' to QuarksSecret '%s'", secret.GetName(), qsec.GetNamespacedName())
+	}
+
+	if err := setContentHash(secret); err != nil {
+		return false, err
+	}
+
+	if err := r.client.Update(ctx, secret); err != nil {
+		return false, errors.Wrapf(err, "could not update secret '%s/%s'", secret.Namespace, secret.Name)
+	}
+
+	if qsec.Spec.DriftPolicy == qsv1a1.DriftRestore {
+		if err := storeBackup(ctx, r.client, r.scheme, r.setReference, qsec, secret); err != nil {
+			return false, err
+		}
+	}
+
+	recordSecretMetadata(ctx, qsec, secret)
+	qsec.Status.InputsHash = hash
+
+	ctxlog.WithEvent(qsec, "SecretAdopted").Infof(ctx, "Adopted existing secret '%s/%s'", secret.Namespace, secret.Name)
+	setCondition(qsec, qsv1a1.ConditionGenerated, metav1.ConditionTrue, "Adopted", fmt.Sprintf("Secret '%s' has been adopted", secret.Name))
+
+	return true, nil
+}
+
+// adoptedInputsHash returns the hash of the inputs, which are rendered into
+// templated configs and docker configs
+func (r *ReconcileQuarksSecret) adoptedInputsHash(ctx context.Context, qsec *qsv1a1.QuarksSecret) (string, error) {
+	var hash string
+	var err error
+	switch qsec.Spec.Type {
+	case qsv1a1.TemplatedConfig:
+		_, _, hash, err = r.templatedConfigInputs(ctx, qsec)
+	case qsv1a1.DockerConfigJSON:
+		_, _, hash, err = r.dockerConfigInputs(ctx, qsec)
+	}
+	return hash, err
+}
+
+// recordSecretMetadata sets the certificate and key metadata of an adopted
+// secret in the quarks secret status
+func recordSecretMetadata(ctx context.Context, qsec *qsv1a1.QuarksSecret, secret *corev1.Secret) {
+	var err error
+	switch qsec.Spec.Type {
+	case qsv1a1.Certificate:
+		qsec.Status.Certificate, err = certificateStatus(secret.Data["certificate"])
+	case qsv1a1.TLS:
+		qsec.Status.Certificate, err = certificateStatus(secret.Data[corev1.TLSCertKey])
+	case qsv1a1.RSAKey:
+		qsec.Status.KeyFingerprint, err = rsaKeyFingerprint(secret.Data["public_key"])
+	case qsv1a1.SSHKey:
+		qsec.Status.KeyFingerprint, err = sshKeyFingerprint(secret.Data["public_key"])
+	}
+	if err != nil {
+		ctxlog.Errorf(ctx, "Failed to read metadata of secret '%s/%s': %v", secret.Namespace, secret.Name, err)
+	}
+}
+
+// validateSecret checks the keys of an existing secret against the keys,
+// which the operator would generate for the quarks secret's type
+func validateSecret(qsec *qsv1a1.QuarksSecret, secret *corev1.Secret) error {
+	data := secret.Data
+	require := func(keys ...string) error {
+		for _, key := range keys {
+			if len(data[key]) == 0 {
+				return errors.Errorf("missing key '%s'", key)
+			}
+		}
+		return nil
+	}
+
+	switch qsec.Spec.Type {
+	case qsv1a1.Password:
+		return require("password")
+	case qsv1a1.BasicAuth:
+		if err := require("username", "password"); err != nil {
+			return err
+		}
+		username := qsec.Spec.Request.BasicAuthRequest.Username
+		if username != "" && username != string(data["username"]) {
+			return errors.Errorf("username does not match '%s'", username)
+		}
+		return nil
+	case qsv1a1.Certificate:
+		if err := require("certificate", "private_key"); err != nil {
+			return err
+		}
+		return validateCertificate(data["certificate"], data["private_key"])
+	case qsv1a1.TLS:
+		if err := require(corev1.TLSCertKey, corev1.TLSPrivateKeyKey); err != nil {
+			return err
+		}
+		return validateCertificate(data[corev1.TLSCertKey], data[corev1.TLSPrivateKeyKey])
+	case qsv1a1.RSAKey:
+		if err := require("private_key", "public_key"); err != nil {
+			return err
+		}
+		return validateRSAKey(data["private_key"], data["public_key"])
+	case qsv1a1.SSHKey:
+		if err := require("private_key", "public_key"); err != nil {
+			return err
+		}
+		return validateSSHKey(data["private_key"], data["public_key"])
+	case qsv1a1.DockerConfigJSON:
+		if err := require(corev1.DockerConfigJsonKey); err != nil {
+			return err
+		}
+		var config map[string]interface{}
+		if err := json.Unmarshal(data[corev1.DockerConfigJsonKey], &config); err != nil {
+			return errors.Wrapf(err, "invalid '%s'", corev1.DockerConfigJsonKey)
+		}
+		return nil
+	case qsv1a1.TemplatedConfig:
+		for key := range qsec.Spec.Request.TemplatedConfigRequest.Templates {
+			if _, ok := data[key]; !ok {
+				return errors.Errorf("missing key '%s'", key)
+			}
+		}
+		return nil
+	}
+	return errors.Errorf("secrets of type '%s' cannot be adopted", qsec.Spec.Type)
+}
+
+// validateCertificate checks that the certificate can be parsed and matches
+// the private key
+func validateCertificate(cert, key []byte) error {
+	if _, err := tls.X509KeyPair(cert, key); err != nil {
+		return errors.Wrap(err, "invalid certificate")
+	}
+	return nil
+}
+
+// validateRSAKey checks that the PEM encoded public key belongs to the
+// private key
+func validateRSAKey(private, public []byte) error {
+	key, err := ssh.ParseRawPrivateKey(private)
+	if err != nil {
+		return errors.Wrap(err, "invalid private key")
+	}
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return errors.New("invalid private key")
+	}
+
+	block, _ := pem.Decode(public)
+	if block == nil {
+		return errors.New("failed to decode public key PEM")
+	}
+	publicKey, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return errors.Wrap(err, "invalid public key")
+	}
+
+	equal, ok := signer.Public().(interface{ Equal(crypto.PublicKey) bool })
+	if !ok || !equal.Equal(publicKey) {
+		return errors.New("public key does not match private key")
+	}
+	return nil
+}
+
+// validateSSHKey checks that the public key in the authorized keys format
+// belongs to the private key
+func validateSSHKey(private, public []byte) error {
+	signer, err := ssh.ParsePrivateKey(private)
+	if err != nil {
+		return errors.Wrap(err, "invalid private key")
+	}
+
+	publicKey, _, _, _, err := ssh.ParseAuthorizedKey(public)
+	if err != nil {
+		return errors.Wrap(err, "invalid public key")
+	}
+
+	if string(signer.PublicKey().Marshal()) != string(publicKey.Marshal()) {
+		return errors.New("public key does not match private key")
+	}
+	return nil
+}
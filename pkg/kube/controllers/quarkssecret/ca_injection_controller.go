@@ -0,0 +1,114 @@
+package quarkssecret
+
+import (
+	"context"
+	"reflect"
+	"strings"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	qsv1a1 "code.cloudfoundry.org/quarks-secret/pkg/kube/apis/quarkssecret/v1alpha1"
+	"code.cloudfoundry.org/quarks-utils/pkg/config"
+	"code.cloudfoundry.org/quarks-utils/pkg/ctxlog"
+)
+
+// AddCAInjection creates a new CA injection controller, which keeps the
+// caBundle of webhook configurations, API services and CRD conversion
+// webhooks in sync with the CA of the QuarksSecret they are annotated with.
+func AddCAInjection(ctx context.Context, config *config.Config, mgr manager.Manager) error {
+	ctx = ctxlog.NewContextWithRecorder(ctx, "ca-injection-reconciler", mgr.GetEventRecorderFor("ca-injection-recorder"))
+	r := NewCAInjectionReconciler(ctx, config, mgr)
+
+	c, err := controller.New("ca-injection-controller", mgr, controller.Options{
+		Reconciler:              r,
+		MaxConcurrentReconciles: config.MaxQuarksSecretWorkers,
+	})
+	if err != nil {
+		return errors.Wrap(err, "Adding CA injection controller to manager failed.")
+	}
+
+	nsPred := newNSPredicate(ctx, mgr.GetClient(), config.MonitoredID)
+
+	// Watch for generated secrets, whose data changes, e.g. on CA rotation
+	p := predicate.Funcs{
+		CreateFunc:  func(e event.CreateEvent) bool { return isGeneratedSecret(e.Object) },
+		DeleteFunc:  func(e event.DeleteEvent) bool { return false },
+		GenericFunc: func(e event.GenericEvent) bool { return false },
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			n := e.ObjectNew.(*corev1.Secret)
+			o := e.ObjectOld.(*corev1.Secret)
+
+			return isGeneratedSecret(n) && !reflect.DeepEqual(n.Data, o.Data)
+		},
+	}
+	err = c.Watch(&source.Kind{Type: &corev1.Secret{}}, ownerQuarksSecretHandler(ctx), nsPred, p)
+	if err != nil {
+		return errors.Wrapf(err, "Watching generated secrets failed in CA injection controller.")
+	}
+
+	// Watch for QuarksSecrets, which are resumed with a pending injection
+	err = c.Watch(&source.Kind{Type: &qsv1a1.QuarksSecret{}}, &handler.EnqueueRequestForObject{}, nsPred, resumedPredicate(qsv1a1.WorkCAInjection))
+	if err != nil {
+		return errors.Wrapf(err, "Watching quarks secrets failed in CA injection controller.")
+	}
+
+	// Watch for annotated resources, which appear or whose caBundle changes
+	p = predicate.Funcs{
+		CreateFunc:  func(e event.CreateEvent) bool { return injectCAFrom(e.Object) != "" },
+		DeleteFunc:  func(e event.DeleteEvent) bool { return false },
+		GenericFunc: func(e event.GenericEvent) bool { return false },
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			n := e.ObjectNew.(*unstructured.Unstructured)
+			o := e.ObjectOld.(*unstructured.Unstructured)
+
+			if injectCAFrom(n) == "" {
+				return false
+			}
+			return injectCAFrom(n) != injectCAFrom(o) || !reflect.DeepEqual(caBundles(n), caBundles(o))
+		},
+	}
+	for _, gvk := range caInjectionKinds {
+		u := &unstructured.Unstructured{}
+		u.SetGroupVersionKind(gvk)
+		err = c.Watch(&source.Kind{Type: u}, handler.EnqueueRequestsFromMapFunc(
+			func(a client.Object) []reconcile.Request {
+				namespace, name, ok := splitNamespacedName(injectCAFrom(a))
+				if !ok {
+					ctxlog.Errorf(ctx, "Invalid annotation '%s' on '%s': expected '<namespace>/<name>'", qsv1a1.AnnotationInjectCAFrom, a.GetName())
+					return []reconcile.Request{}
+				}
+				return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: name, Namespace: namespace}}}
+			}), p)
+		if err != nil {
+			return errors.Wrapf(err, "Watching %s failed in CA injection controller.", gvk.Kind)
+		}
+	}
+
+	return nil
+}
+
+// injectCAFrom returns the namespaced name of the quarks secret, whose CA
+// is injected into the object
+func injectCAFrom(o client.Object) string {
+	return o.GetAnnotations()[qsv1a1.AnnotationInjectCAFrom]
+}
+
+// splitNamespacedName splits '<namespace>/<name>'
+func splitNamespacedName(s string) (string, string, bool) {
+	parts := strings.Split(s, "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", false
+	}
+	return parts[0], parts[1], true
+}
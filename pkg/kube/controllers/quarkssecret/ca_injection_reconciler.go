@@ -0,0 +1,249 @@
+package quarkssecret
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	qsv1a1 "code.cloudfoundry.org/quarks-secret/pkg/kube/apis/quarkssecret/v1alpha1"
+	"code.cloudfoundry.org/quarks-utils/pkg/config"
+	"code.cloudfoundry.org/quarks-utils/pkg/ctxlog"
+)
+
+// caInjectionKinds are the kinds of resources, which have a caBundle
+var caInjectionKinds = []schema.GroupVersionKind{
+	{Group: "admissionregistration.k8s.io", Version: "v1", Kind: "ValidatingWebhookConfiguration"},
+	{Group: "admissionregistration.k8s.io", Version: "v1", Kind: "MutatingWebhookConfiguration"},
+	{Group: "apiregistration.k8s.io", Version: "v1", Kind: "APIService"},
+	{Group: "apiextensions.k8s.io", Version: "v1", Kind: "CustomResourceDefinition"},
+}
+
+// NewCAInjectionReconciler returns a new ReconcileCAInjection
+func NewCAInjectionReconciler(ctx context.Context, config *config.Config, mgr manager.Manager) reconcile.Reconciler {
+	return &ReconcileCAInjection{
+		ctx:    ctx,
+		config: config,
+		client: mgr.GetClient(),
+	}
+}
+
+// ReconcileCAInjection injects the CA of a QuarksSecret into the caBundle of
+// annotated resources
+type ReconcileCAInjection struct {
+	ctx    context.Context
+	client client.Client
+	config *config.Config
+}
+
+// Reconcile finds the resources, which are annotated with the
+// QuarksSecret, and patches their caBundle with the CA of the generated
+// secret.
+func (r *ReconcileCAInjection) Reconcile(_ context.Context, request reconcile.Request) (reconcile.Result, error) {
+	qsec := &qsv1a1.QuarksSecret{}
+
+	// Set the ctx to be Background, as the top-level context for incoming requests.
+	ctx, cancel := context.WithTimeout(r.ctx, r.config.CtxTimeOut)
+	defer cancel()
+
+	ctxlog.Infof(ctx, "Reconciling CA injection of QuarksSecret %s", request.NamespacedName)
+	err := r.client.Get(ctx, request.NamespacedName, qsec)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			ctxlog.Info(ctx, "Skip reconcile: quarks secret not found")
+			return reconcile.Result{}, nil
+		}
+		ctxlog.Info(ctx, "Error reading the object")
+		return reconcile.Result{}, errors.Wrap(err, "Error reading quarksSecret")
+	}
+
+	if !qsec.GetDeletionTimestamp().IsZero() {
+		ctxlog.Debugf(ctx, "Skip reconcile: QuarksSecret '%s' is being deleted", qsec.GetNamespacedName())
+		return reconcile.Result{}, nil
+	}
+
+	// Annotated resources are cluster scoped and not filtered by the
+	// namespace predicate
+	ns := &corev1.Namespace{}
+	if err := r.client.Get(ctx, types.NamespacedName{Name: qsec.Namespace}, ns); err != nil {
+		return reconcile.Result{}, errors.Wrapf(err, "could not get namespace '%s'", qsec.Namespace)
+	}
+	if !qsv1a1.IsMonitoredNamespace(ns, r.config.MonitoredID) {
+		ctxlog.Debugf(ctx, "Skip reconcile: namespace '%s' is not monitored", qsec.Namespace)
+		return reconcile.Result{}, nil
+	}
+
+	targets, err := r.listTargets(ctx, qsec)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if len(targets) == 0 {
+		return reconcile.Result{}, nil
+	}
+
+	if qsec.Spec.Paused {
+		return reconcile.Result{}, deferWork(ctx, r.client, qsec, qsv1a1.WorkCAInjection)
+	}
+
+	secret := &corev1.Secret{}
+	err = r.client.Get(ctx, types.NamespacedName{Name: qsec.Spec.SecretName, Namespace: qsec.Namespace}, secret)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			ctxlog.Debugf(ctx, "Skip reconcile: secret '%s/%s' not found", qsec.Namespace, qsec.Spec.SecretName)
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, errors.Wrapf(err, "could not get secret '%s/%s'", qsec.Namespace, qsec.Spec.SecretName)
+	}
+
+	bundle, err := injectedCA(qsec, secret)
+	if err != nil {
+		return reconcile.Result{}, ctxlog.WithEvent(qsec, "CAInjectionFailed").Errorf(ctx, "Failed to inject CA of QuarksSecret '%s': %s", qsec.GetNamespacedName(), err)
+	}
+
+	for _, target := range targets {
+		patch := client.MergeFrom(target.DeepCopy())
+		changed, err := setCABundle(target, bundle)
+		if err != nil {
+			ctxlog.WithEvent(qsec, "CAInjectionFailed").Errorf(ctx, "Failed to inject CA into %s '%s': %s", target.GetKind(), target.GetName(), err)
+			continue
+		}
+		if !changed {
+			continue
+		}
+
+		if err := r.client.Patch(ctx, target, patch); err != nil {
+			return reconcile.Result{}, ctxlog.WithEvent(qsec, "CAInjectionFailed").Errorf(ctx, "Failed to patch caBundle of %s '%s': %s", target.GetKind(), target.GetName(), err)
+		}
+		ctxlog.WithEvent(qsec, "CAInjection").Infof(ctx, "Injected CA of QuarksSecret '%s' into %s '%s'", qsec.GetNamespacedName(), target.GetKind(), target.GetName())
+	}
+
+	return reconcile.Result{}, nil
+}
+
+// listTargets lists the resources, which are annotated with the quarks
+// secret
+func (r *ReconcileCAInjection) listTargets(ctx context.Context, qsec *qsv1a1.QuarksSecret) ([]*unstructured.Unstructured, error) {
+	result := []*unstructured.Unstructured{}
+	for _, gvk := range caInjectionKinds {
+		list := &unstructured.UnstructuredList{}
+		list.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+		if err := r.client.List(ctx, list); err != nil {
+			return result, errors.Wrapf(err, "could not list %s", gvk.Kind)
+		}
+
+		for i := range list.Items {
+			if injectCAFrom(&list.Items[i]) == qsec.GetNamespacedName() {
+				result = append(result, &list.Items[i])
+			}
+		}
+	}
+	return result, nil
+}
+
+// injectedCA returns the CA of the generated secret. A certificate without
+// a CA is injected itself, as it is self-signed.
+func injectedCA(qsec *qsv1a1.QuarksSecret, secret *corev1.Secret) ([]byte, error) {
+	var key string
+	switch qsec.Spec.Type {
+	case qsv1a1.Certificate:
+		key = "certificate"
+	case qsv1a1.TLS:
+		key = corev1.TLSCertKey
+	default:
+		return nil, fmt.Errorf("secrets of type '%s' have no CA", qsec.Spec.Type)
+	}
+
+	if ca := secret.Data["ca"]; len(ca) > 0 {
+		return ca, nil
+	}
+	if cert := secret.Data[key]; len(cert) > 0 {
+		return cert, nil
+	}
+	return nil, fmt.Errorf("secret '%s/%s' has neither 'ca' nor '%s'", secret.Namespace, secret.Name, key)
+}
+
+// caBundles returns the caBundle fields of the resource
+func caBundles(u *unstructured.Unstructured) []string {
+	result := []string{}
+	switch u.GetKind() {
+	case "ValidatingWebhookConfiguration", "MutatingWebhookConfiguration":
+		webhooks, _, _ := unstructured.NestedSlice(u.Object, "webhooks")
+		for _, webhook := range webhooks {
+			if w, ok := webhook.(map[string]interface{}); ok {
+				bundle, _, _ := unstructured.NestedString(w, "clientConfig", "caBundle")
+				result = append(result, bundle)
+			}
+		}
+	case "APIService":
+		bundle, _, _ := unstructured.NestedString(u.Object, "spec", "caBundle")
+		result = append(result, bundle)
+	case "CustomResourceDefinition":
+		bundle, _, _ := unstructured.NestedString(u.Object, "spec", "conversion", "webhook", "clientConfig", "caBundle")
+		result = append(result, bundle)
+	}
+	return result
+}
+
+// setCABundle sets the caBundle fields of the resource and returns true, if
+// one of them changed
+func setCABundle(u *unstructured.Unstructured, ca []byte) (bool, error) {
+	bundle := base64.StdEncoding.EncodeToString(ca)
+	changed := false
+
+	switch u.GetKind() {
+	case "ValidatingWebhookConfiguration", "MutatingWebhookConfiguration":
+		webhooks, _, err := unstructured.NestedSlice(u.Object, "webhooks")
+		if err != nil {
+			return false, errors.Wrap(err, "invalid webhooks")
+		}
+		for i := range webhooks {
+			w, ok := webhooks[i].(map[string]interface{})
+			if !ok {
+				return false, errors.New("invalid webhook")
+			}
+			if existing, _, _ := unstructured.NestedString(w, "clientConfig", "caBundle"); existing == bundle {
+				continue
+			}
+			if err := unstructured.SetNestedField(w, bundle, "clientConfig", "caBundle"); err != nil {
+				return false, errors.Wrap(err, "could not set caBundle")
+			}
+			changed = true
+		}
+		if changed {
+			if err := unstructured.SetNestedSlice(u.Object, webhooks, "webhooks"); err != nil {
+				return false, errors.Wrap(err, "could not set webhooks")
+			}
+		}
+	case "APIService":
+		if existing, _, _ := unstructured.NestedString(u.Object, "spec", "caBundle"); existing != bundle {
+			if err := unstructured.SetNestedField(u.Object, bundle, "spec", "caBundle"); err != nil {
+				return false, errors.Wrap(err, "could not set caBundle")
+			}
+			changed = true
+		}
+	case "CustomResourceDefinition":
+		strategy, _, _ := unstructured.NestedString(u.Object, "spec", "conversion", "strategy")
+		if strategy != "Webhook" {
+			return false, errors.New("CRD has no conversion webhook")
+		}
+		if existing, _, _ := unstructured.NestedString(u.Object, "spec", "conversion", "webhook", "clientConfig", "caBundle"); existing != bundle {
+			if err := unstructured.SetNestedField(u.Object, bundle, "spec", "conversion", "webhook", "clientConfig", "caBundle"); err != nil {
+				return false, errors.Wrap(err, "could not set caBundle")
+			}
+			changed = true
+		}
+	default:
+		return false, fmt.Errorf("kind '%s' has no caBundle", u.GetKind())
+	}
+
+	return changed, nil
+}
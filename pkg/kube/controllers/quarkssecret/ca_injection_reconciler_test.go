@@ -0,0 +1,221 @@
+package quarkssecret_test
+
+import (
+	"context"
+	"encoding/base64"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"go.uber.org/zap"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	crc "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	qsv1a1 "code.cloudfoundry.org/quarks-secret/pkg/kube/apis/quarkssecret/v1alpha1"
+	"code.cloudfoundry.org/quarks-secret/pkg/kube/client/clientset/versioned/scheme"
+	"code.cloudfoundry.org/quarks-secret/pkg/kube/controllers"
+	cfakes "code.cloudfoundry.org/quarks-secret/pkg/kube/controllers/fakes"
+	qscontroller "code.cloudfoundry.org/quarks-secret/pkg/kube/controllers/quarkssecret"
+	cfcfg "code.cloudfoundry.org/quarks-utils/pkg/config"
+	"code.cloudfoundry.org/quarks-utils/pkg/ctxlog"
+	helper "code.cloudfoundry.org/quarks-utils/testing/testhelper"
+)
+
+var _ = Describe("ReconcileCAInjection", func() {
+	var (
+		manager      *cfakes.FakeManager
+		reconciler   reconcile.Reconciler
+		request      reconcile.Request
+		ctx          context.Context
+		log          *zap.SugaredLogger
+		config       *cfcfg.Config
+		client       *cfakes.FakeClient
+		statusWriter *cfakes.FakeStatusWriter
+		qSecret      *qsv1a1.QuarksSecret
+		secret       *corev1.Secret
+		namespace    *corev1.Namespace
+		targets      map[string][]unstructured.Unstructured
+	)
+
+	// patched returns the patched resources
+	patched := func() []*unstructured.Unstructured {
+		result := []*unstructured.Unstructured{}
+		for i := 0; i < client.PatchCallCount(); i++ {
+			_, object, _, _ := client.PatchArgsForCall(i)
+			result = append(result, object.(*unstructured.Unstructured))
+		}
+		return result
+	}
+
+	target := func(kind string, name string, annotation string, content map[string]interface{}) unstructured.Unstructured {
+		u := unstructured.Unstructured{Object: content}
+		u.SetKind(kind)
+		u.SetName(name)
+		if annotation != "" {
+			u.SetAnnotations(map[string]string{qsv1a1.AnnotationInjectCAFrom: annotation})
+		}
+		return u
+	}
+
+	encodedCA := base64.StdEncoding.EncodeToString([]byte("the-ca"))
+
+	BeforeEach(func() {
+		err := controllers.AddToScheme(scheme.Scheme)
+		Expect(err).ToNot(HaveOccurred())
+		manager = &cfakes.FakeManager{}
+		request = reconcile.Request{NamespacedName: types.NamespacedName{Name: "webhook-cert", Namespace: "default"}}
+		config = &cfcfg.Config{CtxTimeOut: 10 * time.Second, MonitoredID: "staging"}
+		_, log = helper.NewTestLogger()
+		ctx = ctxlog.NewContextWithRecorder(ctxlog.NewParentContext(log), "TestRecorder", record.NewFakeRecorder(10))
+
+		qSecret = &qsv1a1.QuarksSecret{
+			ObjectMeta: metav1.ObjectMeta{Name: "webhook-cert", Namespace: "default"},
+			Spec: qsv1a1.QuarksSecretSpec{
+				Type:       qsv1a1.Certificate,
+				SecretName: "webhook-cert",
+			},
+		}
+		secret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "webhook-cert", Namespace: "default"},
+			Data: map[string][]byte{
+				"certificate": []byte("the-cert"),
+				"ca":          []byte("the-ca"),
+			},
+		}
+		namespace = &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+			Name:   "default",
+			Labels: map[string]string{qsv1a1.LabelNamespace: "staging"},
+		}}
+		targets = map[string][]unstructured.Unstructured{
+			"ValidatingWebhookConfigurationList": {
+				target("ValidatingWebhookConfiguration", "validate", "default/webhook-cert", map[string]interface{}{
+					"webhooks": []interface{}{
+						map[string]interface{}{"name": "a", "clientConfig": map[string]interface{}{}},
+						map[string]interface{}{"name": "b", "clientConfig": map[string]interface{}{"caBundle": "b2xk"}},
+					},
+				}),
+				target("ValidatingWebhookConfiguration", "other", "default/other", map[string]interface{}{}),
+			},
+			"APIServiceList": {
+				target("APIService", "v1.metrics", "default/webhook-cert", map[string]interface{}{
+					"spec": map[string]interface{}{"caBundle": encodedCA},
+				}),
+			},
+			"CustomResourceDefinitionList": {
+				target("CustomResourceDefinition", "foos.example.com", "default/webhook-cert", map[string]interface{}{
+					"spec": map[string]interface{}{
+						"conversion": map[string]interface{}{"strategy": "Webhook"},
+					},
+				}),
+			},
+		}
+
+		client = &cfakes.FakeClient{}
+		client.GetCalls(func(context context.Context, nn types.NamespacedName, object crc.Object) error {
+			switch object := object.(type) {
+			case *qsv1a1.QuarksSecret:
+				qSecret.DeepCopyInto(object)
+				return nil
+			case *corev1.Secret:
+				secret.DeepCopyInto(object)
+				return nil
+			case *corev1.Namespace:
+				namespace.DeepCopyInto(object)
+				return nil
+			}
+			return apierrors.NewNotFound(schema.GroupResource{}, nn.Name)
+		})
+		client.ListCalls(func(context context.Context, object crc.ObjectList, _ ...crc.ListOption) error {
+			if list, ok := object.(*unstructured.UnstructuredList); ok {
+				for _, item := range targets[list.GetKind()] {
+					list.Items = append(list.Items, *item.DeepCopy())
+				}
+			}
+			return nil
+		})
+		statusWriter = &cfakes.FakeStatusWriter{}
+		client.StatusCalls(func() crc.StatusWriter { return statusWriter })
+		manager.GetClientReturns(client)
+	})
+
+	JustBeforeEach(func() {
+		reconciler = qscontroller.NewCAInjectionReconciler(ctx, config, manager)
+	})
+
+	It("injects the CA into the annotated resources, whose caBundle differs", func() {
+		_, err := reconciler.Reconcile(context.Background(), request)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(patched()).To(HaveLen(2))
+
+		webhooks, _, _ := unstructured.NestedSlice(patched()[0].Object, "webhooks")
+		Expect(webhooks).To(HaveLen(2))
+		for _, webhook := range webhooks {
+			bundle, _, _ := unstructured.NestedString(webhook.(map[string]interface{}), "clientConfig", "caBundle")
+			Expect(bundle).To(Equal(encodedCA))
+		}
+
+		Expect(patched()[1].GetKind()).To(Equal("CustomResourceDefinition"))
+		bundle, _, _ := unstructured.NestedString(patched()[1].Object, "spec", "conversion", "webhook", "clientConfig", "caBundle")
+		Expect(bundle).To(Equal(encodedCA))
+	})
+
+	It("injects a self-signed certificate, if the secret has no CA", func() {
+		delete(secret.Data, "ca")
+		delete(targets, "ValidatingWebhookConfigurationList")
+		delete(targets, "CustomResourceDefinitionList")
+
+		_, err := reconciler.Reconcile(context.Background(), request)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(patched()).To(HaveLen(1))
+		bundle, _, _ := unstructured.NestedString(patched()[0].Object, "spec", "caBundle")
+		Expect(bundle).To(Equal(base64.StdEncoding.EncodeToString([]byte("the-cert"))))
+	})
+
+	It("skips CRDs without a conversion webhook", func() {
+		targets["CustomResourceDefinitionList"][0].Object["spec"] = map[string]interface{}{}
+
+		_, err := reconciler.Reconcile(context.Background(), request)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(patched()).To(HaveLen(1))
+		Expect(patched()[0].GetKind()).To(Equal("ValidatingWebhookConfiguration"))
+	})
+
+	It("skips quarks secrets in namespaces, which are not monitored", func() {
+		namespace.Labels = nil
+
+		_, err := reconciler.Reconcile(context.Background(), request)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(client.ListCallCount()).To(Equal(0))
+		Expect(client.PatchCallCount()).To(Equal(0))
+	})
+
+	It("fails for secret types without a CA", func() {
+		qSecret.Spec.Type = qsv1a1.Password
+
+		_, err := reconciler.Reconcile(context.Background(), request)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("have no CA"))
+		Expect(client.PatchCallCount()).To(Equal(0))
+	})
+
+	It("defers the injection, while the quarks secret is paused", func() {
+		qSecret.Spec.Paused = true
+
+		_, err := reconciler.Reconcile(context.Background(), request)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(client.PatchCallCount()).To(Equal(0))
+		Expect(statusWriter.UpdateCallCount()).To(Equal(1))
+		_, object, _ := statusWriter.UpdateArgsForCall(0)
+		Expect(object.(*qsv1a1.QuarksSecret).Status.PendingWork).To(ConsistOf(qsv1a1.WorkCAInjection))
+	})
+})
@@ -6,11 +6,12 @@ import (
 	"strconv"
 
 	"github.com/pkg/errors"
-	certv1 "k8s.io/api/certificates/v1beta1"
+	certv1 "k8s.io/api/certificates/v1"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"code.cloudfoundry.org/quarks-secret/pkg/credsgen"
 	qsv1a1 "code.cloudfoundry.org/quarks-secret/pkg/kube/apis/quarkssecret/v1alpha1"
@@ -19,31 +20,36 @@ import (
 )
 
 func (r *ReconcileQuarksSecret) createCertificateSecret(ctx context.Context, qsec *qsv1a1.QuarksSecret) error {
-	serviceIPForEKSWorkaround := ""
-
-	for _, serviceRef := range qsec.Spec.Request.CertificateRequest.ServiceRef {
-		service := &corev1.Service{}
-
-		err := r.client.Get(ctx, types.NamespacedName{Namespace: qsec.Namespace, Name: serviceRef.Name}, service)
+	values, err := providedValues(ctx, r.client, qsec)
+	if err != nil {
+		return err
+	}
 
-		if err != nil {
-			return errors.Wrapf(err, "Failed to get service reference '%s' for QuarksSecret '%s'", serviceRef.Name, qsec.GetNamespacedName())
-		}
+	certKey, privateKey := "certificate", "private_key"
+	if qsec.Spec.Type == qsv1a1.TLS {
+		certKey, privateKey = corev1.TLSCertKey, corev1.TLSPrivateKeyKey
+	}
+	cert, key, provided, err := providedPair(values, certKey, privateKey)
+	if err != nil {
+		return err
+	}
+	if provided {
+		return r.createProvidedCertificateSecret(ctx, qsec, []byte(cert), []byte(key), values)
+	}
+	// a generated certificate is signed by its own CA, another CA would not
+	// verify it
+	if _, ok := values["ca"]; ok {
+		return errors.New("'ca' can only be provided together with the certificate and its private key")
+	}
 
-		if serviceIPForEKSWorkaround == "" {
-			serviceIPForEKSWorkaround = service.Spec.ClusterIP
-		}
+	serviceNames, serviceIPForEKSWorkaround, err := serviceAlternativeNames(ctx, r.client, qsec)
+	if err != nil {
+		return err
+	}
+	qsec.Spec.Request.CertificateRequest.AlternativeNames = append(qsec.Spec.Request.CertificateRequest.AlternativeNames, serviceNames...)
 
-		qsec.Spec.Request.CertificateRequest.AlternativeNames = append(append(
-			qsec.Spec.Request.CertificateRequest.AlternativeNames,
-			service.Name,
-			service.Name+"."+service.Namespace,
-			"*."+service.Name,
-			"*."+service.Name+"."+service.Namespace,
-			service.Spec.ClusterIP,
-			service.Spec.LoadBalancerIP,
-			service.Spec.ExternalName,
-		), service.Spec.ExternalIPs...)
+	if err := applyIssuer(ctx, r.client, qsec); err != nil {
+		return errors.Wrap(err, "applying issuer")
 	}
 
 	if len(qsec.Spec.Request.CertificateRequest.SignerType) == 0 {
@@ -54,6 +60,13 @@ func (r *ReconcileQuarksSecret) createCertificateSecret(ctx context.Context, qse
 	if err != nil {
 		return errors.Wrap(err, "generating certificate generation request")
 	}
+	if len(generationRequest.CA.Certificate) > 0 {
+		if ref := qsec.Spec.Request.CertificateRequest.ClusterCARef; ref != "" {
+			setCondition(qsec, qsv1a1.ConditionCAReady, metav1.ConditionTrue, "CAFound", fmt.Sprintf("CA of ClusterQuarksSecret '%s' was found", ref))
+		} else {
+			setCondition(qsec, qsv1a1.ConditionCAReady, metav1.ConditionTrue, "CAFound", fmt.Sprintf("CA secret '%s' was found", qsec.Spec.Request.CertificateRequest.CARef.Name))
+		}
+	}
 
 	switch qsec.Spec.Request.CertificateRequest.SignerType {
 	case qsv1a1.ClusterSigner:
@@ -96,7 +109,16 @@ func (r *ReconcileQuarksSecret) createCertificateSecret(ctx context.Context, qse
 			return err
 		}
 
-		return r.createCertificateSigningRequest(ctx, qsec, csr)
+		err = r.createCertificateSigningRequest(ctx, qsec, csr)
+		if err != nil {
+			return err
+		}
+
+		// the certificate secret is created by the CSR reconciler, once the CSR was issued
+		if !isUserProvided(qsec) {
+			setCondition(qsec, qsv1a1.ConditionGenerated, metav1.ConditionFalse, "CertificateSigningRequestPending", fmt.Sprintf("Waiting for CSR '%s' to be issued", names.CSRName(qsec.Namespace, qsec.Name)))
+		}
+		return nil
 	case qsv1a1.LocalSigner:
 		// Generate certificate
 		cert, err := r.generator.GenerateCertificate(qsec.GetName(), generationRequest)
@@ -137,13 +159,67 @@ func (r *ReconcileQuarksSecret) createCertificateSecret(ctx context.Context, qse
 		if len(generationRequest.CA.Certificate) > 0 {
 			secret.StringData["ca"] = string(generationRequest.CA.Certificate)
 		}
+		mergeProvidedValues(secret, values)
+
+		err = r.createSecrets(ctx, qsec, secret)
+		if err != nil {
+			return err
+		}
 
-		return r.createSecrets(ctx, qsec, secret)
+		if !isUserProvided(qsec) {
+			qsec.Status.Certificate, err = certificateStatus(cert.Certificate)
+			if err != nil {
+				ctxlog.Errorf(ctx, "Failed to read metadata of certificate '%s/%s': %v", qsec.Namespace, qsec.Spec.SecretName, err)
+			}
+		}
+		return nil
 	default:
 		return fmt.Errorf("unrecognized signer type: %s", qsec.Spec.Request.CertificateRequest.SignerType)
 	}
 }
 
+// createProvidedCertificateSecret creates the secret from a provided
+// certificate and private key, nothing is generated
+func (r *ReconcileQuarksSecret) createProvidedCertificateSecret(ctx context.Context, qsec *qsv1a1.QuarksSecret, cert []byte, key []byte, values map[string]string) error {
+	if err := validateCertificate(cert, key); err != nil {
+		return err
+	}
+	if ca, ok := values["ca"]; ok {
+		if err := validateCertificateChain(cert, []byte(ca)); err != nil {
+			return err
+		}
+	}
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        qsec.Spec.SecretName,
+			Namespace:   qsec.GetNamespace(),
+			Labels:      qsec.Spec.SecretLabels,
+			Annotations: qsec.Spec.SecretAnnotations,
+		},
+		StringData: map[string]string{},
+	}
+	if qsec.Spec.Type == qsv1a1.TLS {
+		secret.Type = corev1.SecretTypeTLS
+	} else {
+		secret.StringData["is_ca"] = strconv.FormatBool(qsec.Spec.Request.CertificateRequest.IsCA)
+	}
+	mergeProvidedValues(secret, values)
+
+	if err := r.createSecrets(ctx, qsec, secret); err != nil {
+		return err
+	}
+
+	if !isUserProvided(qsec) {
+		var err error
+		qsec.Status.Certificate, err = certificateStatus(cert)
+		if err != nil {
+			ctxlog.Errorf(ctx, "Failed to read metadata of certificate '%s/%s': %v", qsec.Namespace, qsec.Spec.SecretName, err)
+		}
+	}
+	return nil
+}
+
 // generateCertificateGenerationRequest generates CertificateGenerationRequest for certificate
 func (r *ReconcileQuarksSecret) generateCertificateGenerationRequest(ctx context.Context, namespace string, certificateRequest qsv1a1.CertificateRequest) (credsgen.CertificateGenerationRequest, error) {
 	var request credsgen.CertificateGenerationRequest
@@ -161,15 +237,28 @@ func (r *ReconcileQuarksSecret) generateCertificateGenerationRequest(ctx context
 			CommonName:       certificateRequest.CommonName,
 			AlternativeNames: certificateRequest.AlternativeNames,
 		}
+		if certificateRequest.Duration != nil {
+			request.Duration = certificateRequest.Duration.Duration
+		}
+		for _, usage := range certificateRequest.Usages {
+			request.Usages = append(request.Usages, string(usage))
+		}
 
-		if len(certificateRequest.CARef.Name) > 0 {
-			// Get CA certificate
-			caSecret := &corev1.Secret{}
-			caNamespacedName := types.NamespacedName{
-				Namespace: namespace,
-				Name:      certificateRequest.CARef.Name,
+		if len(certificateRequest.ClusterCARef) > 0 {
+			if len(certificateRequest.CARef.Name) > 0 {
+				return request, errors.New("CARef and clusterCARef can't be used together")
+			}
+			if err := checkClusterCARequest(certificateRequest); err != nil {
+				return request, err
 			}
-			err := r.client.Get(ctx, caNamespacedName, caSecret)
+			ca, err := getClusterCA(ctx, r.client, r.config.OperatorNamespace, certificateRequest.ClusterCARef)
+			if err != nil {
+				return request, err
+			}
+			request.CA = ca
+		} else if len(certificateRequest.CARef.Name) > 0 {
+			// Get CA certificate
+			caSecret, err := getReferencedSecret(ctx, r.client, namespace, certificateRequest.CARef)
 			if err != nil {
 				if apierrors.IsNotFound(err) {
 					return request, newCaNotReadyError("CA secret not found")
@@ -179,13 +268,9 @@ func (r *ReconcileQuarksSecret) generateCertificateGenerationRequest(ctx context
 			ca := caSecret.Data[certificateRequest.CARef.Key]
 
 			// Get CA key
-			if certificateRequest.CAKeyRef.Name != certificateRequest.CARef.Name {
-				caSecret = &corev1.Secret{}
-				caNamespacedName = types.NamespacedName{
-					Namespace: namespace,
-					Name:      certificateRequest.CAKeyRef.Name,
-				}
-				err = r.client.Get(ctx, caNamespacedName, caSecret)
+			if certificateRequest.CAKeyRef.Name != certificateRequest.CARef.Name ||
+				referencedNamespace(namespace, certificateRequest.CAKeyRef) != referencedNamespace(namespace, certificateRequest.CARef) {
+				caSecret, err = getReferencedSecret(ctx, r.client, namespace, certificateRequest.CAKeyRef)
 				if err != nil {
 					if apierrors.IsNotFound(err) {
 						return request, newCaNotReadyError("CA key secret not found")
@@ -207,6 +292,93 @@ func (r *ReconcileQuarksSecret) generateCertificateGenerationRequest(ctx context
 	return request, nil
 }
 
+// checkClusterCARequest rejects CA certificates and signing usages for
+// certificates signed by a cluster CA, as they would hand out the signing
+// power of the shared CA to the namespace of the quarks secret
+func checkClusterCARequest(certificateRequest qsv1a1.CertificateRequest) error {
+	if certificateRequest.IsCA {
+		return errors.Errorf("cluster CA '%s' can't sign CA certificates", certificateRequest.ClusterCARef)
+	}
+	for _, usage := range certificateRequest.Usages {
+		if usage == certv1.UsageCertSign || usage == certv1.UsageCRLSign {
+			return errors.Errorf("usage '%s' is not allowed for certificates signed by cluster CA '%s'", usage, certificateRequest.ClusterCARef)
+		}
+	}
+	return nil
+}
+
+// serviceAlternativeNames returns the alternative names for the services,
+// which are referenced by the certificate request, and the cluster IP of
+// the first one
+func serviceAlternativeNames(ctx context.Context, c client.Client, qsec *qsv1a1.QuarksSecret) ([]string, string, error) {
+	names := []string{}
+	clusterIP := ""
+
+	for _, serviceRef := range qsec.Spec.Request.CertificateRequest.ServiceRef {
+		service := &corev1.Service{}
+
+		err := c.Get(ctx, types.NamespacedName{Namespace: qsec.Namespace, Name: serviceRef.Name}, service)
+
+		if err != nil {
+			return nil, "", errors.Wrapf(err, "Failed to get service reference '%s' for QuarksSecret '%s'", serviceRef.Name, qsec.GetNamespacedName())
+		}
+
+		if clusterIP == "" {
+			clusterIP = service.Spec.ClusterIP
+		}
+
+		names = append(append(
+			names,
+			service.Name,
+			service.Name+"."+service.Namespace,
+			"*."+service.Name,
+			"*."+service.Name+"."+service.Namespace,
+			service.Spec.ClusterIP,
+			service.Spec.LoadBalancerIP,
+			service.Spec.ExternalName,
+		), service.Spec.ExternalIPs...)
+	}
+
+	return names, clusterIP, nil
+}
+
+// getClusterCA reads the CA of a ClusterQuarksSecret from its secret in the
+// operator namespace
+func getClusterCA(ctx context.Context, c client.Client, operatorNamespace string, name string) (credsgen.Certificate, error) {
+	cqsec := &qsv1a1.ClusterQuarksSecret{}
+	err := c.Get(ctx, types.NamespacedName{Name: name}, cqsec)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return credsgen.Certificate{}, newCaNotReadyError(fmt.Sprintf("cluster quarks secret '%s' not found", name))
+		}
+		return credsgen.Certificate{}, errors.Wrapf(err, "getting cluster quarks secret '%s'", name)
+	}
+	if !cqsec.IsCA() {
+		return credsgen.Certificate{}, errors.Errorf("cluster quarks secret '%s' is not a CA certificate", name)
+	}
+
+	if operatorNamespace == "" {
+		return credsgen.Certificate{}, errors.New("operator namespace is not configured")
+	}
+	caSecret := &corev1.Secret{}
+	err = c.Get(ctx, types.NamespacedName{Namespace: operatorNamespace, Name: cqsec.Spec.SecretName}, caSecret)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return credsgen.Certificate{}, newCaNotReadyError(fmt.Sprintf("CA secret of cluster quarks secret '%s' not found", name))
+		}
+		return credsgen.Certificate{}, errors.Wrapf(err, "getting CA secret of cluster quarks secret '%s'", name)
+	}
+	if len(caSecret.Data["certificate"]) == 0 || len(caSecret.Data["private_key"]) == 0 {
+		return credsgen.Certificate{}, newCaNotReadyError(fmt.Sprintf("CA secret of cluster quarks secret '%s' is incomplete", name))
+	}
+
+	return credsgen.Certificate{
+		IsCA:        true,
+		PrivateKey:  caSecret.Data["private_key"],
+		Certificate: caSecret.Data["certificate"],
+	}, nil
+}
+
 // createCertificateSigningRequest creates CertificateSigningRequest Object
 func (r *ReconcileQuarksSecret) createCertificateSigningRequest(ctx context.Context, qsec *qsv1a1.QuarksSecret, csr []byte) error {
 	csrName := names.CSRName(qsec.Namespace, qsec.Name)
@@ -228,18 +400,19 @@ func (r *ReconcileQuarksSecret) createCertificateSigningRequest(ctx context.Cont
 			Annotations: annotations,
 		},
 		Spec: certv1.CertificateSigningRequestSpec{
-			Request: csr,
-			Usages:  qsec.Spec.Request.CertificateRequest.Usages,
+			Request:    csr,
+			SignerName: qsec.Spec.Request.CertificateRequest.SignerName,
+			Usages:     qsec.Spec.Request.CertificateRequest.Usages,
 		},
 	}
 
 	oldCsrObj := &certv1.CertificateSigningRequest{}
 
 	// CSR spec is immutable after the request is created
-	err := r.client.Get(ctx, types.NamespacedName{Name: csrObj.Name}, oldCsrObj)
+	err := getCSR(ctx, r.client, csrObj.Name, oldCsrObj)
 	if err != nil {
 		if apierrors.IsNotFound(err) {
-			err = r.client.Create(ctx, csrObj)
+			err = createCSR(ctx, r.client, csrObj, qsec.Spec.Request.CertificateRequest.Duration)
 			if err != nil {
 				return errors.Wrapf(err, "could not create certificatesigningrequest '%s'", csrObj.Name)
 			}
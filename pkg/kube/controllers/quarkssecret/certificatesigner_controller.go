@@ -0,0 +1,83 @@
+package quarkssecret
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+	certv1 "k8s.io/api/certificates/v1"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	credsgen "code.cloudfoundry.org/quarks-secret/pkg/credsgen/in_memory_generator"
+	qsv1a1 "code.cloudfoundry.org/quarks-secret/pkg/kube/apis/quarkssecret/v1alpha1"
+	"code.cloudfoundry.org/quarks-utils/pkg/config"
+	"code.cloudfoundry.org/quarks-utils/pkg/ctxlog"
+)
+
+// AddCertificateSigner creates a new certificate signer controller, which
+// signs approved CSRs for the signer names of ClusterQuarksSecret CAs.
+func AddCertificateSigner(ctx context.Context, config *config.Config, mgr manager.Manager) error {
+	ctx = ctxlog.NewContextWithRecorder(ctx, "certificate-signer-reconciler", mgr.GetEventRecorderFor("certificate-signer-recorder"))
+	if !servesCSRv1(mgr.GetRESTMapper()) {
+		ctxlog.Info(ctx, "Cluster doesn't serve certificates.k8s.io/v1, not signing CSRs")
+		return nil
+	}
+
+	log := ctxlog.ExtractLogger(ctx)
+	r := NewCertificateSignerReconciler(ctx, config, mgr, credsgen.NewInMemoryGenerator(log))
+
+	c, err := controller.New("certificate-signer-controller", mgr, controller.Options{
+		Reconciler:              r,
+		MaxConcurrentReconciles: config.MaxQuarksSecretWorkers,
+	})
+	if err != nil {
+		return errors.Wrap(err, "Adding certificate signer controller to manager failed.")
+	}
+
+	// Watch for CSRs of our signers, which are approved but not issued yet
+	p := predicate.Funcs{
+		CreateFunc: func(e event.CreateEvent) bool {
+			return needsSigning(e.Object.(*certv1.CertificateSigningRequest))
+		},
+		DeleteFunc:  func(e event.DeleteEvent) bool { return false },
+		GenericFunc: func(e event.GenericEvent) bool { return false },
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			return needsSigning(e.ObjectNew.(*certv1.CertificateSigningRequest))
+		},
+	}
+	err = c.Watch(&source.Kind{Type: &certv1.CertificateSigningRequest{}}, &handler.EnqueueRequestForObject{}, p)
+	if err != nil {
+		return errors.Wrapf(err, "Watching certificate signing requests failed in certificate signer controller.")
+	}
+
+	return nil
+}
+
+// isQuarksSigner returns true, if the signer name belongs to a cluster
+// quarks secret CA
+func isQuarksSigner(signerName string) bool {
+	return strings.HasPrefix(signerName, qsv1a1.SignerNamePrefix)
+}
+
+// needsSigning returns true, if the CSR is for one of our signers and
+// approved, but neither issued nor failed
+func needsSigning(csr *certv1.CertificateSigningRequest) bool {
+	if !isQuarksSigner(csr.Spec.SignerName) || len(csr.Status.Certificate) != 0 {
+		return false
+	}
+	approved := false
+	for _, condition := range csr.Status.Conditions {
+		switch condition.Type {
+		case certv1.CertificateApproved:
+			approved = true
+		case certv1.CertificateDenied, certv1.CertificateFailed:
+			return false
+		}
+	}
+	return approved
+}
@@ -0,0 +1,147 @@
+package quarkssecret
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	certv1 "k8s.io/api/certificates/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"code.cloudfoundry.org/quarks-secret/pkg/credsgen"
+	qsv1a1 "code.cloudfoundry.org/quarks-secret/pkg/kube/apis/quarkssecret/v1alpha1"
+	"code.cloudfoundry.org/quarks-utils/pkg/config"
+	"code.cloudfoundry.org/quarks-utils/pkg/ctxlog"
+)
+
+// NewCertificateSignerReconciler returns a new ReconcileCertificateSigner
+func NewCertificateSignerReconciler(ctx context.Context, config *config.Config, mgr manager.Manager, generator credsgen.Generator) reconcile.Reconciler {
+	return &ReconcileCertificateSigner{
+		ctx:       ctx,
+		config:    config,
+		client:    mgr.GetClient(),
+		generator: generator,
+	}
+}
+
+// ReconcileCertificateSigner signs CSRs with the CA of a ClusterQuarksSecret
+type ReconcileCertificateSigner struct {
+	ctx       context.Context
+	client    client.Client
+	config    *config.Config
+	generator credsgen.Generator
+}
+
+// Reconcile signs an approved CSR for the signer name
+// 'quarks.cloudfoundry.org/<name>' with the CA of the cluster quarks secret
+// <name> and writes the certificate to its status. The cluster quarks
+// secret has to allow it with the csr-signer annotation.
+func (r *ReconcileCertificateSigner) Reconcile(_ context.Context, request reconcile.Request) (reconcile.Result, error) {
+	// Set the ctx to be Background, as the top-level context for incoming requests.
+	ctx, cancel := context.WithTimeout(r.ctx, r.config.CtxTimeOut)
+	defer cancel()
+
+	ctxlog.Infof(ctx, "Reconciling CSR '%s' for signing", request.Name)
+
+	// read the CSR unstructured, as the API types don't know
+	// 'spec.expirationSeconds' yet
+	u := &unstructured.Unstructured{}
+	u.SetGroupVersionKind(certv1.SchemeGroupVersion.WithKind(csrKind))
+	err := r.client.Get(ctx, types.NamespacedName{Name: request.Name}, u)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			ctxlog.Infof(ctx, "Skip reconcile: CSR '%s' not found", request.Name)
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, errors.Wrapf(err, "Error reading CSR '%s'", request.Name)
+	}
+	csr := &certv1.CertificateSigningRequest{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, csr); err != nil {
+		return reconcile.Result{}, errors.Wrapf(err, "Error converting CSR '%s'", request.Name)
+	}
+
+	if !needsSigning(csr) {
+		ctxlog.Debugf(ctx, "Skip reconcile: CSR '%s' doesn't need to be signed", csr.Name)
+		return reconcile.Result{}, nil
+	}
+
+	name := strings.TrimPrefix(csr.Spec.SignerName, qsv1a1.SignerNamePrefix)
+	cqsec := &qsv1a1.ClusterQuarksSecret{}
+	err = r.client.Get(ctx, types.NamespacedName{Name: name}, cqsec)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return reconcile.Result{}, r.fail(ctx, csr, "SignerNotFound", fmt.Sprintf("cluster quarks secret '%s' not found", name))
+		}
+		return reconcile.Result{}, errors.Wrapf(err, "getting cluster quarks secret '%s'", name)
+	}
+	if !cqsec.IsCSRSigner() {
+		return reconcile.Result{}, r.fail(ctx, csr, "SignerNotEnabled", fmt.Sprintf("cluster quarks secret '%s' is not a CA, which allows signing CSRs", name))
+	}
+
+	for _, usage := range csr.Spec.Usages {
+		if usage == certv1.UsageCertSign || usage == certv1.UsageCRLSign {
+			return reconcile.Result{}, r.fail(ctx, csr, "SignerValidationFailure", fmt.Sprintf("usage '%s' is not allowed, CA certificates can't be signed", usage))
+		}
+	}
+
+	ca, err := getClusterCA(ctx, r.client, r.config.OperatorNamespace, name)
+	if err != nil {
+		if isCaNotReady(err) {
+			ctxlog.Infof(ctx, "Waiting for CA of cluster quarks secret '%s': %s", name, err)
+			return reconcile.Result{RequeueAfter: time.Second * 5}, nil
+		}
+		return reconcile.Result{}, err
+	}
+
+	generationRequest := credsgen.CertificateGenerationRequest{CA: ca}
+	for _, usage := range csr.Spec.Usages {
+		generationRequest.Usages = append(generationRequest.Usages, string(usage))
+	}
+	if seconds, ok, _ := unstructured.NestedInt64(u.Object, "spec", "expirationSeconds"); ok {
+		generationRequest.Duration = time.Duration(seconds) * time.Second
+	}
+
+	cert, err := r.generator.SignCertificateSigningRequest(csr.Spec.Request, generationRequest)
+	if err != nil {
+		return reconcile.Result{}, r.fail(ctx, csr, "SigningError", err.Error())
+	}
+
+	csr.Status.Certificate = cert
+	err = r.client.Status().Update(ctx, csr)
+	if err != nil {
+		return reconcile.Result{}, errors.Wrapf(err, "could not update status of CSR '%s'", csr.Name)
+	}
+	ctxlog.WithEvent(csr, "CertificateIssued").Infof(ctx, "Signed CSR '%s' with CA of cluster quarks secret '%s'", csr.Name, name)
+
+	return reconcile.Result{}, nil
+}
+
+// fail marks the CSR as failed, so it is not signed
+func (r *ReconcileCertificateSigner) fail(ctx context.Context, csr *certv1.CertificateSigningRequest, reason string, message string) error {
+	ctxlog.WithEvent(csr, reason).Errorf(ctx, "Failed to sign CSR '%s': %s", csr.Name, message)
+
+	now := metav1.Now()
+	csr.Status.Conditions = append(csr.Status.Conditions, certv1.CertificateSigningRequestCondition{
+		Type:               certv1.CertificateFailed,
+		Status:             corev1.ConditionTrue,
+		Reason:             reason,
+		Message:            message,
+		LastUpdateTime:     now,
+		LastTransitionTime: now,
+	})
+	err := r.client.Status().Update(ctx, csr)
+	if err != nil {
+		return errors.Wrapf(err, "could not update status of CSR '%s'", csr.Name)
+	}
+	return nil
+}
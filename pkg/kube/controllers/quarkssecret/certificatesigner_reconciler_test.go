@@ -0,0 +1,191 @@
+package quarkssecret_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"go.uber.org/zap"
+
+	certv1 "k8s.io/api/certificates/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	crc "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"code.cloudfoundry.org/quarks-secret/pkg/credsgen"
+	generatorfakes "code.cloudfoundry.org/quarks-secret/pkg/credsgen/fakes"
+	qsv1a1 "code.cloudfoundry.org/quarks-secret/pkg/kube/apis/quarkssecret/v1alpha1"
+	"code.cloudfoundry.org/quarks-secret/pkg/kube/client/clientset/versioned/scheme"
+	"code.cloudfoundry.org/quarks-secret/pkg/kube/controllers"
+	cfakes "code.cloudfoundry.org/quarks-secret/pkg/kube/controllers/fakes"
+	qscontroller "code.cloudfoundry.org/quarks-secret/pkg/kube/controllers/quarkssecret"
+	cfcfg "code.cloudfoundry.org/quarks-utils/pkg/config"
+	"code.cloudfoundry.org/quarks-utils/pkg/ctxlog"
+	helper "code.cloudfoundry.org/quarks-utils/testing/testhelper"
+)
+
+var _ = Describe("ReconcileCertificateSigner", func() {
+	var (
+		manager      *cfakes.FakeManager
+		reconciler   reconcile.Reconciler
+		request      reconcile.Request
+		ctx          context.Context
+		log          *zap.SugaredLogger
+		config       *cfcfg.Config
+		client       *cfakes.FakeClient
+		statusWriter *cfakes.FakeStatusWriter
+		generator    *generatorfakes.FakeGenerator
+		csr          *certv1.CertificateSigningRequest
+		cqsec        *qsv1a1.ClusterQuarksSecret
+		caSecret     *corev1.Secret
+	)
+
+	// updatedCSR returns the CSR of the last status update
+	updatedCSR := func() *certv1.CertificateSigningRequest {
+		Expect(statusWriter.UpdateCallCount()).To(Equal(1))
+		_, object, _ := statusWriter.UpdateArgsForCall(0)
+		return object.(*certv1.CertificateSigningRequest)
+	}
+
+	BeforeEach(func() {
+		err := controllers.AddToScheme(scheme.Scheme)
+		Expect(err).ToNot(HaveOccurred())
+		manager = &cfakes.FakeManager{}
+		request = reconcile.Request{NamespacedName: types.NamespacedName{Name: "istio-agent"}}
+		config = &cfcfg.Config{CtxTimeOut: 10 * time.Second, OperatorNamespace: "quarks"}
+		_, log = helper.NewTestLogger()
+		ctx = ctxlog.NewContextWithRecorder(ctxlog.NewParentContext(log), "TestRecorder", record.NewFakeRecorder(10))
+
+		csr = &certv1.CertificateSigningRequest{
+			ObjectMeta: metav1.ObjectMeta{Name: "istio-agent"},
+			Spec: certv1.CertificateSigningRequestSpec{
+				Request:    []byte("the_csr"),
+				SignerName: "quarks.cloudfoundry.org/mesh-ca",
+				Usages:     []certv1.KeyUsage{certv1.UsageDigitalSignature, certv1.UsageClientAuth},
+			},
+			Status: certv1.CertificateSigningRequestStatus{
+				Conditions: []certv1.CertificateSigningRequestCondition{
+					{Type: certv1.CertificateApproved, Status: corev1.ConditionTrue},
+				},
+			},
+		}
+		cqsec = &qsv1a1.ClusterQuarksSecret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "mesh-ca",
+				Annotations: map[string]string{qsv1a1.AnnotationCSRSigner: "true"},
+			},
+			Spec: qsv1a1.QuarksSecretSpec{Type: qsv1a1.Certificate, SecretName: "mesh-ca"},
+		}
+		cqsec.Spec.Request.CertificateRequest.IsCA = true
+		caSecret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "mesh-ca", Namespace: "quarks"},
+			Data: map[string][]byte{
+				"certificate": []byte("the_ca"),
+				"private_key": []byte("the_ca_key"),
+			},
+		}
+
+		client = &cfakes.FakeClient{}
+		client.GetCalls(func(context context.Context, nn types.NamespacedName, object crc.Object) error {
+			switch object := object.(type) {
+			case *unstructured.Unstructured:
+				u, err := runtime.DefaultUnstructuredConverter.ToUnstructured(csr)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(unstructured.SetNestedField(u, int64(3600), "spec", "expirationSeconds")).To(Succeed())
+				object.Object = u
+				return nil
+			case *qsv1a1.ClusterQuarksSecret:
+				if nn.Name == cqsec.Name {
+					cqsec.DeepCopyInto(object)
+					return nil
+				}
+			case *corev1.Secret:
+				if nn.Namespace == caSecret.Namespace && nn.Name == caSecret.Name {
+					caSecret.DeepCopyInto(object)
+					return nil
+				}
+			}
+			return apierrors.NewNotFound(schema.GroupResource{}, nn.Name)
+		})
+		statusWriter = &cfakes.FakeStatusWriter{}
+		client.StatusReturns(statusWriter)
+		manager.GetClientReturns(client)
+
+		generator = &generatorfakes.FakeGenerator{}
+		generator.SignCertificateSigningRequestReturns([]byte("the_cert"), nil)
+	})
+
+	JustBeforeEach(func() {
+		reconciler = qscontroller.NewCertificateSignerReconciler(ctx, config, manager, generator)
+	})
+
+	It("signs the CSR with the CA of the cluster quarks secret", func() {
+		result, err := reconciler.Reconcile(context.Background(), request)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result).To(Equal(reconcile.Result{}))
+
+		Expect(generator.SignCertificateSigningRequestCallCount()).To(Equal(1))
+		csrPEM, generationRequest := generator.SignCertificateSigningRequestArgsForCall(0)
+		Expect(csrPEM).To(Equal([]byte("the_csr")))
+		Expect(generationRequest.CA).To(Equal(credsgen.Certificate{IsCA: true, Certificate: []byte("the_ca"), PrivateKey: []byte("the_ca_key")}))
+		Expect(generationRequest.Duration).To(Equal(time.Hour))
+		Expect(generationRequest.Usages).To(Equal([]string{"digital signature", "client auth"}))
+
+		Expect(updatedCSR().Status.Certificate).To(Equal([]byte("the_cert")))
+	})
+
+	It("skips CSRs, which are not approved", func() {
+		csr.Status.Conditions = nil
+
+		_, err := reconciler.Reconcile(context.Background(), request)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(generator.SignCertificateSigningRequestCallCount()).To(Equal(0))
+		Expect(statusWriter.UpdateCallCount()).To(Equal(0))
+	})
+
+	It("skips CSRs for other signers", func() {
+		csr.Spec.SignerName = "kubernetes.io/kube-apiserver-client"
+
+		_, err := reconciler.Reconcile(context.Background(), request)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(statusWriter.UpdateCallCount()).To(Equal(0))
+	})
+
+	It("fails CSRs, if the cluster quarks secret doesn't allow signing", func() {
+		cqsec.Annotations = nil
+
+		_, err := reconciler.Reconcile(context.Background(), request)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(generator.SignCertificateSigningRequestCallCount()).To(Equal(0))
+
+		condition := updatedCSR().Status.Conditions[1]
+		Expect(condition.Type).To(Equal(certv1.CertificateFailed))
+		Expect(condition.Reason).To(Equal("SignerNotEnabled"))
+	})
+
+	It("fails CSRs for CA certificates", func() {
+		csr.Spec.Usages = append(csr.Spec.Usages, certv1.UsageCertSign)
+
+		_, err := reconciler.Reconcile(context.Background(), request)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(generator.SignCertificateSigningRequestCallCount()).To(Equal(0))
+		Expect(updatedCSR().Status.Conditions[1].Reason).To(Equal("SignerValidationFailure"))
+	})
+
+	It("waits for the CA secret", func() {
+		caSecret.Name = "other"
+
+		result, err := reconciler.Reconcile(context.Background(), request)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result).To(Equal(reconcile.Result{RequeueAfter: 5 * time.Second}))
+		Expect(statusWriter.UpdateCallCount()).To(Equal(0))
+	})
+})
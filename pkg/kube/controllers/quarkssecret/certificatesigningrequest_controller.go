@@ -4,30 +4,51 @@ import (
 	"context"
 
 	"github.com/pkg/errors"
-	certv1 "k8s.io/api/certificates/v1beta1"
-	certv1client "k8s.io/client-go/kubernetes/typed/certificates/v1beta1"
+	certv1 "k8s.io/api/certificates/v1"
+	certv1beta1 "k8s.io/api/certificates/v1beta1"
+	"k8s.io/apimachinery/pkg/types"
+	certv1client "k8s.io/client-go/kubernetes/typed/certificates/v1"
+	certv1beta1client "k8s.io/client-go/kubernetes/typed/certificates/v1beta1"
+	crc "sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	qsv1a1 "code.cloudfoundry.org/quarks-secret/pkg/kube/apis/quarkssecret/v1alpha1"
 	"code.cloudfoundry.org/quarks-utils/pkg/config"
 	"code.cloudfoundry.org/quarks-utils/pkg/ctxlog"
+	"code.cloudfoundry.org/quarks-utils/pkg/names"
 )
 
 // AddCertificateSigningRequest creates a new CertificateSigningRequest controller to watch for new and changed
 // certificate signing request. Reconciliation will approve them and create a secret.
+// Clusters, which don't serve certificates.k8s.io/v1, are watched with v1beta1.
 func AddCertificateSigningRequest(ctx context.Context, config *config.Config, mgr manager.Manager) error {
 	ctx = ctxlog.NewContextWithRecorder(ctx, "csr-reconciler", mgr.GetEventRecorderFor("csr-recorder"))
-	certClient, err := certv1client.NewForConfig(mgr.GetConfig())
-	if err != nil {
-		return errors.Wrap(err, "Could not get kube client")
+
+	var r reconcile.Reconciler
+	var csrType crc.Object
+	if servesCSRv1(mgr.GetRESTMapper()) {
+		certClient, err := certv1client.NewForConfig(mgr.GetConfig())
+		if err != nil {
+			return errors.Wrap(err, "Could not get kube client")
+		}
+		r = NewCertificateSigningRequestReconciler(ctx, config, mgr, certClient, controllerutil.SetControllerReference)
+		csrType = &certv1.CertificateSigningRequest{}
+	} else {
+		ctxlog.Info(ctx, "Cluster doesn't serve certificates.k8s.io/v1, using v1beta1 for CSRs")
+		certClient, err := certv1beta1client.NewForConfig(mgr.GetConfig())
+		if err != nil {
+			return errors.Wrap(err, "Could not get kube client")
+		}
+		r = NewLegacyCertificateSigningRequestReconciler(ctx, config, mgr, certClient, controllerutil.SetControllerReference)
+		csrType = &certv1beta1.CertificateSigningRequest{}
 	}
-	r := NewCertificateSigningRequestReconciler(ctx, config, mgr, certClient, controllerutil.SetControllerReference)
 
 	// Create a new controller
 	c, err := controller.New("certificate-signing-request-controller", mgr, controller.Options{
@@ -41,23 +62,34 @@ func AddCertificateSigningRequest(ctx context.Context, config *config.Config, mg
 	// Watch for changes to CertificateSigningRequests
 	p := predicate.Funcs{
 		CreateFunc: func(e event.CreateEvent) bool {
-			o := e.Object.(*certv1.CertificateSigningRequest)
-
-			return ownedByQuarksSecret(config.MonitoredID, o.Annotations)
+			return ownedByQuarksSecret(config.MonitoredID, e.Object.GetAnnotations())
 		},
 		DeleteFunc:  func(e event.DeleteEvent) bool { return false },
 		GenericFunc: func(e event.GenericEvent) bool { return false },
 		UpdateFunc: func(e event.UpdateEvent) bool {
-			o := e.ObjectNew.(*certv1.CertificateSigningRequest)
-
-			return ownedByQuarksSecret(config.MonitoredID, o.Annotations)
+			return ownedByQuarksSecret(config.MonitoredID, e.ObjectNew.GetAnnotations())
 		},
 	}
-	err = c.Watch(&source.Kind{Type: &certv1.CertificateSigningRequest{}}, &handler.EnqueueRequestForObject{}, p)
+	err = c.Watch(&source.Kind{Type: csrType}, &handler.EnqueueRequestForObject{}, p)
 	if err != nil {
 		return errors.Wrapf(err, "Watching certificate signing requests failed in certificate signing request controller.")
 	}
 
+	nsPred := newNSPredicate(ctx, mgr.GetClient(), config.MonitoredID)
+
+	// Watch for QuarksSecrets, which are resumed while their CSR is pending
+	err = c.Watch(&source.Kind{Type: &qsv1a1.QuarksSecret{}}, handler.EnqueueRequestsFromMapFunc(
+		func(a crc.Object) []reconcile.Request {
+			request := reconcile.Request{
+				NamespacedName: types.NamespacedName{Name: names.CSRName(a.GetNamespace(), a.GetName())},
+			}
+			ctxlog.NewMappingEvent(a).Debug(ctx, request, "CertificateSigningRequest", a.GetName(), "QuarksSecret")
+			return []reconcile.Request{request}
+		}), nsPred, resumedPredicate(qsv1a1.WorkCertificate))
+	if err != nil {
+		return errors.Wrapf(err, "Watching quarks secrets failed in certificate signing request controller.")
+	}
+
 	return nil
 }
 
@@ -3,16 +3,19 @@ package quarkssecret
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/pkg/errors"
 
-	certv1 "k8s.io/api/certificates/v1beta1"
+	certv1 "k8s.io/api/certificates/v1"
+	certv1beta1 "k8s.io/api/certificates/v1beta1"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
-	certv1client "k8s.io/client-go/kubernetes/typed/certificates/v1beta1"
+	certv1client "k8s.io/client-go/kubernetes/typed/certificates/v1"
+	certv1beta1client "k8s.io/client-go/kubernetes/typed/certificates/v1beta1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
@@ -25,7 +28,7 @@ import (
 )
 
 // NewCertificateSigningRequestReconciler returns a new Reconciler
-func NewCertificateSigningRequestReconciler(ctx context.Context, config *config.Config, mgr manager.Manager, certClient certv1client.CertificatesV1beta1Interface, srf setReferenceFunc) reconcile.Reconciler {
+func NewCertificateSigningRequestReconciler(ctx context.Context, config *config.Config, mgr manager.Manager, certClient certv1client.CertificatesV1Interface, srf setReferenceFunc) reconcile.Reconciler {
 	return &ReconcileCertificateSigningRequest{
 		ctx:          ctx,
 		config:       config,
@@ -36,14 +39,29 @@ func NewCertificateSigningRequestReconciler(ctx context.Context, config *config.
 	}
 }
 
+// NewLegacyCertificateSigningRequestReconciler returns a new Reconciler,
+// which approves CSRs with certificates.k8s.io/v1beta1 for clusters that
+// don't serve v1
+func NewLegacyCertificateSigningRequestReconciler(ctx context.Context, config *config.Config, mgr manager.Manager, certClient certv1beta1client.CertificatesV1beta1Interface, srf setReferenceFunc) reconcile.Reconciler {
+	return &ReconcileCertificateSigningRequest{
+		ctx:              ctx,
+		config:           config,
+		client:           mgr.GetClient(),
+		legacyCertClient: certClient,
+		scheme:           mgr.GetScheme(),
+		setReference:     srf,
+	}
+}
+
 // ReconcileCertificateSigningRequest reconciles an CertificateSigningRequest object
 type ReconcileCertificateSigningRequest struct {
-	ctx          context.Context
-	config       *config.Config
-	client       client.Client
-	certClient   certv1client.CertificatesV1beta1Interface
-	scheme       *runtime.Scheme
-	setReference setReferenceFunc
+	ctx              context.Context
+	config           *config.Config
+	client           client.Client
+	certClient       certv1client.CertificatesV1Interface
+	legacyCertClient certv1beta1client.CertificatesV1beta1Interface
+	scheme           *runtime.Scheme
+	setReference     setReferenceFunc
 }
 
 // Reconcile approves pending CSR and creates its certificate secret
@@ -55,7 +73,7 @@ func (r *ReconcileCertificateSigningRequest) Reconcile(_ context.Context, reques
 	defer cancel()
 
 	ctxlog.Infof(ctx, "Reconciling CSR '%s'", request.Name)
-	err := r.client.Get(ctx, request.NamespacedName, csr)
+	err := getCSR(ctx, r.client, request.Name, csr)
 	if err != nil {
 		if apierrors.IsNotFound(err) {
 			// Request object not found, could have been deleted after reconcile request.
@@ -115,10 +133,14 @@ func (r *ReconcileCertificateSigningRequest) Reconcile(_ context.Context, reques
 			return reconcile.Result{}, err
 		}
 
+		if qsec.Spec.Paused {
+			return reconcile.Result{}, deferWork(ctx, r.client, qsec, qsv1a1.WorkCertificate)
+		}
+
 		// Create the certificate secret
-		rootCA, err := getClusterRootCA(ctx, r.client, namespace)
+		rootCA, err := r.issuingCA(ctx, csr, namespace)
 		if err != nil {
-			ctxlog.Errorf(ctx, "Failed to get the cluster root CA: %v", err.Error())
+			ctxlog.Errorf(ctx, "Failed to get the CA of CSR '%s': %v", csr.Name, err.Error())
 			return reconcile.Result{}, nil
 		}
 
@@ -138,10 +160,23 @@ func (r *ReconcileCertificateSigningRequest) Reconcile(_ context.Context, reques
 			},
 		}
 
+		values, err := providedValues(ctx, r.client, qsec)
+		if err != nil {
+			ctxlog.Errorf(ctx, "Failed to get the provided values: %v", err.Error())
+			return reconcile.Result{}, err
+		}
+		for key, value := range values {
+			certSecret.Data[key] = []byte(value)
+		}
+
 		if err := r.setReference(qsec, certSecret, r.scheme); err != nil {
 			return reconcile.Result{}, errors.Wrapf(err, "error setting owner for secret '%s' to QuarksSecret '%s'", certSecret.GetName(), qsec.GetNamespacedName())
 		}
 
+		if err := setContentHash(certSecret); err != nil {
+			return reconcile.Result{}, err
+		}
+
 		ctxlog.Infof(ctx, "Creating certificate secret '%s' for CSR '%s'", certSecret.Name, csr.Name)
 		err = r.createSecret(ctx, certSecret)
 		if err != nil {
@@ -149,6 +184,21 @@ func (r *ReconcileCertificateSigningRequest) Reconcile(_ context.Context, reques
 			return reconcile.Result{}, err
 		}
 
+		if qsec.Spec.DriftPolicy == qsv1a1.DriftRestore {
+			err = storeBackup(ctx, r.client, r.scheme, r.setReference, qsec, certSecret)
+			if err != nil {
+				return reconcile.Result{}, err
+			}
+		}
+
+		status := qsec.Status.DeepCopy()
+		qsec.Status.Certificate, err = certificateStatus(csr.Status.Certificate)
+		if err != nil {
+			ctxlog.Errorf(ctx, "Failed to read metadata of certificate '%s/%s': %v", namespace, secretName, err)
+		}
+		setCondition(qsec, qsv1a1.ConditionGenerated, metav1.ConditionTrue, "CertificateIssued", fmt.Sprintf("Secret '%s' has been created from CSR '%s'", certSecret.Name, csr.Name))
+		updateConditions(ctx, r.client, qsec, status)
+
 		// Clean up CSR and private key, no longer needed
 		err = r.deleteSecret(ctx, privateKeySecret)
 		if err != nil {
@@ -163,6 +213,11 @@ func (r *ReconcileCertificateSigningRequest) Reconcile(_ context.Context, reques
 		}
 
 	} else {
+		paused, err := r.isPaused(ctx, csr)
+		if paused || err != nil {
+			return reconcile.Result{}, err
+		}
+
 		err = r.approveRequest(ctx, csr.Name)
 		if err != nil {
 			ctxlog.Errorf(ctx, "Failed to approve certificate signing request: %v", err.Error())
@@ -173,9 +228,26 @@ func (r *ReconcileCertificateSigningRequest) Reconcile(_ context.Context, reques
 	return reconcile.Result{}, nil
 }
 
+// isPaused returns true, if the quarks secret, which created the CSR, is
+// paused. The work is deferred until it is resumed.
+func (r *ReconcileCertificateSigningRequest) isPaused(ctx context.Context, csr *certv1.CertificateSigningRequest) (bool, error) {
+	annotations := csr.GetAnnotations()
+	qsec := &qsv1a1.QuarksSecret{}
+	err := r.client.Get(ctx, types.NamespacedName{Name: annotations[qsv1a1.AnnotationQSecName], Namespace: annotations[qsv1a1.AnnotationQSecNamespace]}, qsec)
+	if err != nil {
+		// errors are handled, when the certificate secret is created
+		return false, nil
+	}
+
+	if !qsec.Spec.Paused {
+		return false, nil
+	}
+	return true, deferWork(ctx, r.client, qsec, qsv1a1.WorkCertificate)
+}
+
 // approveRequest approves the CSR
 func (r *ReconcileCertificateSigningRequest) approveRequest(ctx context.Context, csrName string) error {
-	csr, err := r.certClient.CertificateSigningRequests().Get(ctx, csrName, metav1.GetOptions{})
+	csr, err := r.getApprovalCSR(ctx, csrName)
 	if err != nil {
 		return errors.Wrapf(err, "could not get CSR '%s'", csrName)
 	}
@@ -184,15 +256,38 @@ func (r *ReconcileCertificateSigningRequest) approveRequest(ctx context.Context,
 		ctxlog.Debugf(ctx, "Waiting for CSR to be issued: CSR %s has already been approved", csrName)
 		return nil
 	}
+	if isDenied(csr.Status.Conditions) {
+		ctxlog.Debugf(ctx, "Skip approval: CSR %s has been denied", csrName)
+		return nil
+	}
+
+	annotations := csr.GetAnnotations()
+	qsec := &qsv1a1.QuarksSecret{}
+	err = r.client.Get(ctx, types.NamespacedName{Name: annotations[qsv1a1.AnnotationQSecName], Namespace: annotations[qsv1a1.AnnotationQSecNamespace]}, qsec)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return r.deny(ctx, nil, csr, newCSRDenial("QuarksSecretNotFound", "quarks secret '%s/%s' not found", annotations[qsv1a1.AnnotationQSecNamespace], annotations[qsv1a1.AnnotationQSecName]))
+		}
+		return errors.Wrapf(err, "could not get quarks secret of CSR '%s'", csrName)
+	}
+
+	err = verifyCSR(ctx, r.client, qsec, csr)
+	if denial, ok := err.(*csrDenial); ok {
+		return r.deny(ctx, qsec, csr, denial)
+	}
+	if err != nil {
+		return errors.Wrapf(err, "could not verify CSR '%s'", csrName)
+	}
 
 	csr.Status.Conditions = append(csr.Status.Conditions, certv1.CertificateSigningRequestCondition{
 		Type:    certv1.CertificateApproved,
+		Status:  corev1.ConditionTrue,
 		Reason:  "AutoApproved",
 		Message: "This CSR was approved by csr-controller",
 	})
 
 	ctxlog.Infof(ctx, "Approving CSR '%s'", csrName)
-	_, err = r.certClient.CertificateSigningRequests().UpdateApproval(ctx, csr, metav1.UpdateOptions{})
+	err = r.updateApproval(ctx, csr)
 	if err != nil {
 		return errors.Wrapf(err, "could not update approval of CSR '%s'", csrName)
 	}
@@ -202,6 +297,65 @@ func (r *ReconcileCertificateSigningRequest) approveRequest(ctx context.Context,
 	return nil
 }
 
+// deny denies the CSR and marks the quarks secret as not generated
+func (r *ReconcileCertificateSigningRequest) deny(ctx context.Context, qsec *qsv1a1.QuarksSecret, csr *certv1.CertificateSigningRequest, denial *csrDenial) error {
+	csr.Status.Conditions = append(csr.Status.Conditions, certv1.CertificateSigningRequestCondition{
+		Type:    certv1.CertificateDenied,
+		Status:  corev1.ConditionTrue,
+		Reason:  denial.reason,
+		Message: denial.message,
+	})
+
+	ctxlog.WithEvent(csr, denial.reason).Errorf(ctx, "Denying CSR '%s': %s", csr.Name, denial.message)
+	err := r.updateApproval(ctx, csr)
+	if err != nil {
+		return errors.Wrapf(err, "could not update approval of CSR '%s'", csr.Name)
+	}
+
+	if qsec != nil {
+		status := qsec.Status.DeepCopy()
+		setCondition(qsec, qsv1a1.ConditionGenerated, metav1.ConditionFalse, "CertificateSigningRequestDenied", fmt.Sprintf("CSR '%s' was denied: %s", csr.Name, denial.message))
+		updateConditions(ctx, r.client, qsec, status)
+	}
+	return nil
+}
+
+// getApprovalCSR reads the CSR from the API server, not from the cache
+func (r *ReconcileCertificateSigningRequest) getApprovalCSR(ctx context.Context, csrName string) (*certv1.CertificateSigningRequest, error) {
+	if r.legacyCertClient == nil {
+		return r.certClient.CertificateSigningRequests().Get(ctx, csrName, metav1.GetOptions{})
+	}
+
+	legacy, err := r.legacyCertClient.CertificateSigningRequests().Get(ctx, csrName, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	csr := &certv1.CertificateSigningRequest{}
+	if err := convertCSR(legacy, csr); err != nil {
+		return nil, err
+	}
+	// clusters before Kubernetes 1.18 don't know signer names
+	if csr.Spec.SignerName == "" {
+		csr.Spec.SignerName = certv1beta1.LegacyUnknownSignerName
+	}
+	return csr, nil
+}
+
+// updateApproval updates the approval subresource of the CSR
+func (r *ReconcileCertificateSigningRequest) updateApproval(ctx context.Context, csr *certv1.CertificateSigningRequest) error {
+	if r.legacyCertClient == nil {
+		_, err := r.certClient.CertificateSigningRequests().UpdateApproval(ctx, csr.Name, csr, metav1.UpdateOptions{})
+		return err
+	}
+
+	legacy := &certv1beta1.CertificateSigningRequest{}
+	if err := convertCSR(csr, legacy); err != nil {
+		return err
+	}
+	_, err := r.legacyCertClient.CertificateSigningRequests().UpdateApproval(ctx, legacy, metav1.UpdateOptions{})
+	return err
+}
+
 // createSecret creates secret
 func (r *ReconcileCertificateSigningRequest) createSecret(ctx context.Context, secret *corev1.Secret) error {
 	ctxlog.Debugf(ctx, "Creating secret '%s'", secret.Name)
@@ -209,6 +363,10 @@ func (r *ReconcileCertificateSigningRequest) createSecret(ctx context.Context, s
 	obj := secret.DeepCopy()
 	op, err := controllerutil.CreateOrUpdate(ctx, r.client, obj, func() error {
 		obj.Data = secret.Data
+		if obj.Annotations == nil {
+			obj.Annotations = map[string]string{}
+		}
+		obj.Annotations[qsv1a1.AnnotationContentHash] = secret.Annotations[qsv1a1.AnnotationContentHash]
 		return nil
 	})
 	if err != nil {
@@ -234,7 +392,7 @@ func (r *ReconcileCertificateSigningRequest) deleteSecret(ctx context.Context, s
 func (r *ReconcileCertificateSigningRequest) deleteCSR(ctx context.Context, csr *certv1.CertificateSigningRequest) error {
 	ctxlog.Debugf(ctx, "Deleting csr '%s'", csr.Name)
 
-	err := r.client.Delete(ctx, csr)
+	err := deleteCSR(ctx, r.client, csr.Name)
 	if err != nil {
 		return errors.Wrapf(err, "could not delete csr '%s'", csr.Name)
 	}
@@ -242,6 +400,17 @@ func (r *ReconcileCertificateSigningRequest) deleteCSR(ctx context.Context, csr
 	return nil
 }
 
+// isDenied returns true if the CSR has already been denied
+func isDenied(conditions []certv1.CertificateSigningRequestCondition) bool {
+	for _, condition := range conditions {
+		if condition.Type == certv1.CertificateDenied {
+			return true
+		}
+	}
+
+	return false
+}
+
 // isApproved returns true if the CSR has already been approved
 func isApproved(conditions []certv1.CertificateSigningRequestCondition) bool {
 	for _, condition := range conditions {
@@ -253,6 +422,17 @@ func isApproved(conditions []certv1.CertificateSigningRequestCondition) bool {
 	return false
 }
 
+// issuingCA returns the certificate of the CA, which signed the CSR. That
+// is the CA of a cluster quarks secret for our signers, or the cluster root
+// CA otherwise.
+func (r *ReconcileCertificateSigningRequest) issuingCA(ctx context.Context, csr *certv1.CertificateSigningRequest, namespace string) ([]byte, error) {
+	if isQuarksSigner(csr.Spec.SignerName) {
+		ca, err := getClusterCA(ctx, r.client, r.config.OperatorNamespace, strings.TrimPrefix(csr.Spec.SignerName, qsv1a1.SignerNamePrefix))
+		return ca.Certificate, err
+	}
+	return getClusterRootCA(ctx, r.client, namespace)
+}
+
 func getClusterRootCA(ctx context.Context, c client.Client, namespace string) ([]byte, error) {
 	// TODO: This should work with filtering using something like
 	// err = client.List(ctx, secretList, client.InNamespace(namespace), client.MatchingFields{"type": "kubernetes.io/service-account-token"})
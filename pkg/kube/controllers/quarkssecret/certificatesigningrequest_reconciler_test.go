@@ -7,18 +7,23 @@ import (
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	"go.uber.org/zap"
-	certv1 "k8s.io/api/certificates/v1beta1"
+	certv1 "k8s.io/api/certificates/v1"
+	certv1beta1 "k8s.io/api/certificates/v1beta1"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
-	certv1clientfakes "k8s.io/client-go/kubernetes/typed/certificates/v1beta1/fake"
+	certv1clientfakes "k8s.io/client-go/kubernetes/typed/certificates/v1/fake"
+	certv1beta1clientfakes "k8s.io/client-go/kubernetes/typed/certificates/v1beta1/fake"
 	ktesting "k8s.io/client-go/testing"
 	crc "sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
+	"code.cloudfoundry.org/quarks-secret/pkg/credsgen"
+	inmemorygenerator "code.cloudfoundry.org/quarks-secret/pkg/credsgen/in_memory_generator"
 	qsv1a1 "code.cloudfoundry.org/quarks-secret/pkg/kube/apis/quarkssecret/v1alpha1"
 	"code.cloudfoundry.org/quarks-secret/pkg/kube/client/clientset/versioned/scheme"
 	"code.cloudfoundry.org/quarks-secret/pkg/kube/controllers"
@@ -39,15 +44,34 @@ var _ = Describe("ReconcileCertificateSigningRequest", func() {
 		log              *zap.SugaredLogger
 		config           *cfcfg.Config
 		client           *cfakes.FakeClient
-		certClient       *certv1clientfakes.FakeCertificatesV1beta1
+		statusWriter     *cfakes.FakeStatusWriter
+		certClient       *certv1clientfakes.FakeCertificatesV1
 		csr              *certv1.CertificateSigningRequest
 		privateKeySecret *corev1.Secret
 		qsec             *qsv1a1.QuarksSecret
+		namespace        *corev1.Namespace
+		csrRequest       []byte
+		csrKey           []byte
 		setReferenceFunc func(owner, object metav1.Object, scheme *runtime.Scheme) error = func(owner, object metav1.Object, scheme *runtime.Scheme) error { return nil }
 	)
 
+	// generateCSR returns a CSR and its private key for the names
+	generateCSR := func(commonName string, alternativeNames ...string) ([]byte, []byte) {
+		generator := inmemorygenerator.NewInMemoryGenerator(log)
+		generator.Algorithm = "ecdsa"
+		generator.Bits = 256
+		request, key, err := generator.GenerateCertificateSigningRequest(credsgen.CertificateGenerationRequest{
+			CommonName:       commonName,
+			AlternativeNames: alternativeNames,
+		})
+		Expect(err).ToNot(HaveOccurred())
+		return request, key
+	}
+
 	BeforeEach(func() {
 		config = &cfcfg.Config{CtxTimeOut: 10 * time.Second}
+		_, log = helper.NewTestLogger()
+		csrRequest, csrKey = generateCSR("foo.example.com", "foo")
 
 		csr = &certv1.CertificateSigningRequest{
 			ObjectMeta: metav1.ObjectMeta{
@@ -61,7 +85,9 @@ var _ = Describe("ReconcileCertificateSigningRequest", func() {
 				},
 			},
 			Spec: certv1.CertificateSigningRequestSpec{
-				Request: []byte("fake-certificate-signing-request"),
+				Request:    csrRequest,
+				SignerName: "kubernetes.io/legacy-unknown",
+				Usages:     []certv1.KeyUsage{certv1.UsageDigitalSignature, certv1.UsageKeyEncipherment},
 			},
 		}
 		privateKeySecret = &corev1.Secret{
@@ -70,7 +96,7 @@ var _ = Describe("ReconcileCertificateSigningRequest", func() {
 				Namespace: "fake-namespace",
 			},
 			Data: map[string][]byte{
-				"private_key": []byte("fake-private-key"),
+				"private_key": csrKey,
 				"is_ca":       []byte("false"),
 			},
 		}
@@ -80,13 +106,23 @@ var _ = Describe("ReconcileCertificateSigningRequest", func() {
 				Name:      "fake-name",
 				Namespace: "fake-namespace",
 			},
+			Spec: qsv1a1.QuarksSecretSpec{
+				Type: qsv1a1.Certificate,
+				Request: qsv1a1.Request{
+					CertificateRequest: qsv1a1.CertificateRequest{
+						CommonName:       "foo.example.com",
+						AlternativeNames: []string{"foo"},
+						SignerType:       qsv1a1.ClusterSigner,
+					},
+				},
+			},
 		}
+		namespace = &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "fake-namespace"}}
 
 		err := controllers.AddToScheme(scheme.Scheme)
 		Expect(err).ToNot(HaveOccurred())
 		manager = &cfakes.FakeManager{}
 		request = reconcile.Request{NamespacedName: types.NamespacedName{Name: "foo", Namespace: "default"}}
-		_, log = helper.NewTestLogger()
 		ctx = ctxlog.NewParentContext(log)
 
 		client = &cfakes.FakeClient{}
@@ -105,13 +141,20 @@ var _ = Describe("ReconcileCertificateSigningRequest", func() {
 					qsec.DeepCopyInto(object)
 					return nil
 				}
+			case *corev1.Namespace:
+				if nn.Name == namespace.Name {
+					namespace.DeepCopyInto(object)
+					return nil
+				}
 			}
 			return apierrors.NewNotFound(schema.GroupResource{}, "not found")
 		})
 
+		statusWriter = &cfakes.FakeStatusWriter{}
+		client.StatusReturns(statusWriter)
 		manager.GetClientReturns(client)
 
-		certClient = &certv1clientfakes.FakeCertificatesV1beta1{
+		certClient = &certv1clientfakes.FakeCertificatesV1{
 			Fake: &ktesting.Fake{},
 		}
 	})
@@ -135,6 +178,7 @@ var _ = Describe("ReconcileCertificateSigningRequest", func() {
 					case *certv1.CertificateSigningRequest:
 						Expect(object.Status.Conditions).To(ContainElement(certv1.CertificateSigningRequestCondition{
 							Type:    certv1.CertificateApproved,
+							Status:  corev1.ConditionTrue,
 							Reason:  "AutoApproved",
 							Message: "This CSR was approved by csr-controller",
 						}))
@@ -213,6 +257,136 @@ var _ = Describe("ReconcileCertificateSigningRequest", func() {
 			Expect(err).To(HaveOccurred())
 			Expect(err.Error()).To(ContainSubstring("could not update approval of CSR"))
 		})
+
+		Context("when verifying the CSR against its quarks secret", func() {
+			var updated *certv1.CertificateSigningRequest
+
+			// condition returns the approval condition of the updated CSR
+			condition := func() certv1.CertificateSigningRequestCondition {
+				Expect(updated).NotTo(BeNil())
+				Expect(updated.Status.Conditions).To(HaveLen(1))
+				return updated.Status.Conditions[0]
+			}
+
+			BeforeEach(func() {
+				updated = nil
+				certClient.PrependReactor("update", "certificatesigningrequests", func(action ktesting.Action) (handled bool, ret runtime.Object, err error) {
+					updated = action.(ktesting.UpdateActionImpl).Object.(*certv1.CertificateSigningRequest)
+					return true, updated, nil
+				})
+			})
+
+			It("denies CSRs, whose public key doesn't belong to the stored private key", func() {
+				_, privateKeySecret.Data["private_key"] = generateCSR("foo.example.com")
+
+				_, err := reconciler.Reconcile(context.Background(), request)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(condition().Type).To(Equal(certv1.CertificateDenied))
+				Expect(condition().Reason).To(Equal("PublicKeyMismatch"))
+
+				Expect(statusWriter.UpdateCallCount()).To(Equal(1))
+				_, object, _ := statusWriter.UpdateArgsForCall(0)
+				Expect(meta.IsStatusConditionFalse(object.(*qsv1a1.QuarksSecret).Status.Conditions, qsv1a1.ConditionGenerated)).To(BeTrue())
+			})
+
+			It("denies CSRs for names, which the quarks secret doesn't request", func() {
+				qsec.Spec.Request.CertificateRequest.AlternativeNames = nil
+
+				_, err := reconciler.Reconcile(context.Background(), request)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(condition().Reason).To(Equal("UnexpectedNames"))
+				Expect(condition().Message).To(ContainSubstring("'foo'"))
+			})
+
+			It("denies CSRs with usages, which the quarks secret doesn't request", func() {
+				csr.Spec.Usages = append(csr.Spec.Usages, certv1.UsageClientAuth)
+
+				_, err := reconciler.Reconcile(context.Background(), request)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(condition().Reason).To(Equal("UnexpectedUsages"))
+			})
+
+			It("denies CSRs for another signer", func() {
+				csr.Spec.SignerName = certv1.KubeAPIServerClientSignerName
+
+				_, err := reconciler.Reconcile(context.Background(), request)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(condition().Reason).To(Equal("UnexpectedSigner"))
+			})
+
+			It("denies CSRs, if the quarks secret doesn't exist", func() {
+				csr.Annotations[qsv1a1.AnnotationQSecName] = "other"
+
+				_, err := reconciler.Reconcile(context.Background(), request)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(condition().Reason).To(Equal("QuarksSecretNotFound"))
+			})
+
+			It("denies names, which the namespace doesn't allow", func() {
+				namespace.Annotations = map[string]string{qsv1a1.AnnotationAllowedCSRNames: "*.svc"}
+
+				_, err := reconciler.Reconcile(context.Background(), request)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(condition().Reason).To(Equal("NamesNotAllowed"))
+			})
+
+			It("approves names, which the namespace allows", func() {
+				namespace.Annotations = map[string]string{qsv1a1.AnnotationAllowedCSRNames: "*.example.com, foo"}
+
+				_, err := reconciler.Reconcile(context.Background(), request)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(condition().Type).To(Equal(certv1.CertificateApproved))
+			})
+
+			It("skips CSRs, which have been denied", func() {
+				csr.Status.Conditions = []certv1.CertificateSigningRequestCondition{
+					{Type: certv1.CertificateDenied, Status: corev1.ConditionTrue},
+				}
+
+				_, err := reconciler.Reconcile(context.Background(), request)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(updated).To(BeNil())
+			})
+		})
+	})
+
+	Context("when the cluster only serves certificates.k8s.io/v1beta1", func() {
+		var legacyCertClient *certv1beta1clientfakes.FakeCertificatesV1beta1
+
+		BeforeEach(func() {
+			legacyCertClient = &certv1beta1clientfakes.FakeCertificatesV1beta1{
+				Fake: &ktesting.Fake{},
+			}
+			legacyCertClient.AddReactor("get", "certificatesigningrequests", func(action ktesting.Action) (handled bool, ret runtime.Object, err error) {
+				return true, &certv1beta1.CertificateSigningRequest{
+					ObjectMeta: csr.ObjectMeta,
+					Spec:       certv1beta1.CertificateSigningRequestSpec{Request: csr.Spec.Request},
+				}, nil
+			})
+		})
+
+		JustBeforeEach(func() {
+			reconciler = escontroller.NewLegacyCertificateSigningRequestReconciler(ctx, config, manager, legacyCertClient, setReferenceFunc)
+		})
+
+		It("approves the CSR with v1beta1", func() {
+			var approved *certv1beta1.CertificateSigningRequest
+			legacyCertClient.AddReactor("update", "certificatesigningrequests", func(action ktesting.Action) (handled bool, ret runtime.Object, err error) {
+				approved = action.(ktesting.UpdateActionImpl).Object.(*certv1beta1.CertificateSigningRequest)
+				return true, approved, nil
+			})
+
+			_, err := reconciler.Reconcile(context.Background(), request)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(approved).NotTo(BeNil())
+			Expect(approved.Name).To(Equal(csr.Name))
+			Expect(approved.Status.Conditions).To(ContainElement(certv1beta1.CertificateSigningRequestCondition{
+				Type:    certv1beta1.CertificateApproved,
+				Status:  corev1.ConditionTrue,
+				Reason:  "AutoApproved",
+				Message: "This CSR was approved by csr-controller",
+			}))
+		})
 	})
 
 	Context("when reconciling approved CSR", func() {
@@ -272,6 +446,12 @@ var _ = Describe("ReconcileCertificateSigningRequest", func() {
 			Expect(client.GetCallCount()).To(Equal(4))
 			Expect(client.CreateCallCount()).To(Equal(1))
 			Expect(client.DeleteCallCount()).To(Equal(2))
+
+			Expect(statusWriter.UpdateCallCount()).To(Equal(1))
+			_, object, _ := statusWriter.UpdateArgsForCall(0)
+			conditions := object.(*qsv1a1.QuarksSecret).Status.Conditions
+			Expect(meta.IsStatusConditionTrue(conditions, qsv1a1.ConditionGenerated)).To(BeTrue())
+			Expect(meta.IsStatusConditionTrue(conditions, qsv1a1.ConditionReady)).To(BeTrue())
 		})
 
 		It("Skips reconcile when getting nil annotations", func() {
@@ -0,0 +1,64 @@
+package quarkssecret
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	qsv1a1 "code.cloudfoundry.org/quarks-secret/pkg/kube/apis/quarkssecret/v1alpha1"
+	"code.cloudfoundry.org/quarks-utils/pkg/config"
+	"code.cloudfoundry.org/quarks-utils/pkg/ctxlog"
+)
+
+// AddCleanup creates a new cleanup controller, which adds a finalizer to
+// QuarksSecrets and cleans up their copies and CSR artifacts, before they
+// are deleted.
+func AddCleanup(ctx context.Context, config *config.Config, mgr manager.Manager) error {
+	ctx = ctxlog.NewContextWithRecorder(ctx, "cleanup-reconciler", mgr.GetEventRecorderFor("cleanup-recorder"))
+	r := NewCleanupReconciler(ctx, config, mgr)
+
+	c, err := controller.New("cleanup-controller", mgr, controller.Options{
+		Reconciler:              r,
+		MaxConcurrentReconciles: config.MaxQuarksSecretWorkers,
+	})
+	if err != nil {
+		return errors.Wrap(err, "Adding cleanup controller to manager failed.")
+	}
+
+	nsPred := newNSPredicate(ctx, mgr.GetClient(), config.MonitoredID)
+
+	// Watch for QuarksSecrets, which miss the finalizer or are being deleted
+	p := predicate.Funcs{
+		CreateFunc: func(e event.CreateEvent) bool {
+			return needsCleanupReconcile(e.Object.(*qsv1a1.QuarksSecret))
+		},
+		DeleteFunc:  func(e event.DeleteEvent) bool { return false },
+		GenericFunc: func(e event.GenericEvent) bool { return false },
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			return needsCleanupReconcile(e.ObjectNew.(*qsv1a1.QuarksSecret))
+		},
+	}
+	err = c.Watch(&source.Kind{Type: &qsv1a1.QuarksSecret{}}, &handler.EnqueueRequestForObject{}, nsPred, p)
+	if err != nil {
+		return errors.Wrapf(err, "Watching quarks secrets failed in cleanup controller.")
+	}
+
+	return nil
+}
+
+// needsCleanupReconcile returns true, if the finalizer has to be added to
+// the quarks secret or the quarks secret is being deleted
+func needsCleanupReconcile(qsec *qsv1a1.QuarksSecret) bool {
+	hasFinalizer := controllerutil.ContainsFinalizer(qsec, qsv1a1.FinalizerCleanup)
+	if qsec.GetDeletionTimestamp().IsZero() {
+		return !hasFinalizer
+	}
+	return hasFinalizer
+}
@@ -0,0 +1,256 @@
+package quarkssecret
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	qsv1a1 "code.cloudfoundry.org/quarks-secret/pkg/kube/apis/quarkssecret/v1alpha1"
+	"code.cloudfoundry.org/quarks-utils/pkg/config"
+	"code.cloudfoundry.org/quarks-utils/pkg/ctxlog"
+	"code.cloudfoundry.org/quarks-utils/pkg/names"
+)
+
+// NewCleanupReconciler returns a new ReconcileCleanup
+func NewCleanupReconciler(ctx context.Context, config *config.Config, mgr manager.Manager) reconcile.Reconciler {
+	return &ReconcileCleanup{
+		ctx:    ctx,
+		config: config,
+		client: mgr.GetClient(),
+	}
+}
+
+// ReconcileCleanup cleans up the objects of a QuarksSecret, which are not
+// garbage collected, because they live in other namespaces or are cluster
+// scoped
+type ReconcileCleanup struct {
+	ctx    context.Context
+	client client.Client
+	config *config.Config
+}
+
+// Reconcile adds the cleanup finalizer to a QuarksSecret. Once the
+// QuarksSecret is deleted, its copies and public output config maps are
+// deleted or orphaned, depending on the deletion policy, and its CSR and CSR
+// private key secret are deleted.
+// The finalizer is removed afterwards.
+func (r *ReconcileCleanup) Reconcile(_ context.Context, request reconcile.Request) (reconcile.Result, error) {
+	qsec := &qsv1a1.QuarksSecret{}
+
+	// Set the ctx to be Background, as the top-level context for incoming requests.
+	ctx, cancel := context.WithTimeout(r.ctx, r.config.CtxTimeOut)
+	defer cancel()
+
+	ctxlog.Infof(ctx, "Reconciling cleanup of QuarksSecret %s", request.NamespacedName)
+	err := r.client.Get(ctx, request.NamespacedName, qsec)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			ctxlog.Info(ctx, "Skip reconcile: quarks secret not found")
+			return reconcile.Result{}, nil
+		}
+		ctxlog.Info(ctx, "Error reading the object")
+		return reconcile.Result{}, errors.Wrap(err, "Error reading quarksSecret")
+	}
+
+	if qsec.GetDeletionTimestamp().IsZero() {
+		if controllerutil.ContainsFinalizer(qsec, qsv1a1.FinalizerCleanup) {
+			return reconcile.Result{}, nil
+		}
+
+		patch := client.MergeFrom(qsec.DeepCopy())
+		controllerutil.AddFinalizer(qsec, qsv1a1.FinalizerCleanup)
+		if err := r.client.Patch(ctx, qsec, patch); err != nil {
+			return reconcile.Result{}, errors.Wrapf(err, "could not add finalizer to QuarksSecret '%s'", qsec.GetNamespacedName())
+		}
+		return reconcile.Result{}, nil
+	}
+
+	if !controllerutil.ContainsFinalizer(qsec, qsv1a1.FinalizerCleanup) {
+		return reconcile.Result{}, nil
+	}
+
+	// The cleanup isn't deferred for paused quarks secrets, the finalizer
+	// would block the deletion of the namespace, too.
+	if err := r.cleanup(ctx, qsec); err != nil {
+		return reconcile.Result{}, ctxlog.WithEvent(qsec, "CleanupFailed").Errorf(ctx, "Failed to clean up QuarksSecret '%s': %s", qsec.GetNamespacedName(), err)
+	}
+
+	patch := client.MergeFrom(qsec.DeepCopy())
+	controllerutil.RemoveFinalizer(qsec, qsv1a1.FinalizerCleanup)
+	if err := r.client.Patch(ctx, qsec, patch); err != nil {
+		return reconcile.Result{}, errors.Wrapf(err, "could not remove finalizer from QuarksSecret '%s'", qsec.GetNamespacedName())
+	}
+
+	ctxlog.Infof(ctx, "Cleaned up QuarksSecret '%s'", qsec.GetNamespacedName())
+	return reconcile.Result{}, nil
+}
+
+// cleanup deletes or orphans the copies and the generated secret, and
+// deletes the CSR artifacts
+func (r *ReconcileCleanup) cleanup(ctx context.Context, qsec *qsv1a1.QuarksSecret) error {
+	orphan := qsec.Spec.DeletionPolicy == qsv1a1.DeletionOrphan
+
+	copies := append([]qsv1a1.Copy{}, qsec.Spec.Copies...)
+	for _, copy := range qsec.Status.Copies {
+		if !containsCopy(copies, copy) {
+			copies = append(copies, copy)
+		}
+	}
+
+	for _, copy := range copies {
+		if copy.NamespaceSelector != nil {
+			continue
+		}
+		if err := r.cleanupCopy(ctx, qsec, copy, orphan); err != nil {
+			return err
+		}
+	}
+
+	if orphan {
+		if err := r.orphanSecret(ctx, qsec); err != nil {
+			return err
+		}
+	}
+
+	if err := r.cleanupPublicOutput(ctx, qsec, orphan); err != nil {
+		return err
+	}
+
+	return r.cleanupCSR(ctx, qsec)
+}
+
+// cleanupCopy deletes a copy of the generated secret or removes the copy-of
+// annotation from it. Secrets, which are not a copy of the quarks secret,
+// are left alone.
+func (r *ReconcileCleanup) cleanupCopy(ctx context.Context, qsec *qsv1a1.QuarksSecret, copy qsv1a1.Copy, orphan bool) error {
+	secret := &corev1.Secret{}
+	err := r.client.Get(ctx, types.NamespacedName{Name: copy.Name, Namespace: copy.Namespace}, secret)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return errors.Wrapf(err, "could not get copy '%s'", copy.String())
+	}
+
+	if secret.GetAnnotations()[qsv1a1.AnnotationCopyOf] != qsec.GetNamespacedName() {
+		return nil
+	}
+
+	if orphan {
+		delete(secret.Annotations, qsv1a1.AnnotationCopyOf)
+		if err := r.client.Update(ctx, secret); err != nil {
+			return errors.Wrapf(err, "could not orphan copy '%s'", copy.String())
+		}
+		ctxlog.Debugf(ctx, "Orphaned copy '%s'", copy.String())
+		return nil
+	}
+
+	if err := r.client.Delete(ctx, secret); err != nil && !apierrors.IsNotFound(err) {
+		return errors.Wrapf(err, "could not delete copy '%s'", copy.String())
+	}
+	ctxlog.Debugf(ctx, "Deleted copy '%s'", copy.String())
+	return nil
+}
+
+// orphanSecret removes the owner reference and the generated label from the
+// generated secret, so it is neither garbage collected nor managed anymore
+func (r *ReconcileCleanup) orphanSecret(ctx context.Context, qsec *qsv1a1.QuarksSecret) error {
+	secret := &corev1.Secret{}
+	err := r.client.Get(ctx, types.NamespacedName{Name: qsec.Spec.SecretName, Namespace: qsec.Namespace}, secret)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return errors.Wrapf(err, "could not get secret '%s/%s'", qsec.Namespace, qsec.Spec.SecretName)
+	}
+
+	refs := []metav1.OwnerReference{}
+	for _, ref := range secret.GetOwnerReferences() {
+		if ref.UID != qsec.GetUID() {
+			refs = append(refs, ref)
+		}
+	}
+	secret.SetOwnerReferences(refs)
+	delete(secret.Labels, qsv1a1.LabelKind)
+
+	if err := r.client.Update(ctx, secret); err != nil {
+		return errors.Wrapf(err, "could not orphan secret '%s/%s'", secret.Namespace, secret.Name)
+	}
+	ctxlog.Debugf(ctx, "Orphaned secret '%s/%s'", secret.Namespace, secret.Name)
+	return nil
+}
+
+// cleanupPublicOutput deletes the public output config maps or removes the
+// public-output-of annotation from them
+func (r *ReconcileCleanup) cleanupPublicOutput(ctx context.Context, qsec *qsv1a1.QuarksSecret, orphan bool) error {
+	configMaps, err := listPublicConfigMaps(ctx, r.client, qsec)
+	if err != nil {
+		return err
+	}
+
+	for i := range configMaps {
+		configMap := &configMaps[i]
+		if orphan {
+			refs := []metav1.OwnerReference{}
+			for _, ref := range configMap.GetOwnerReferences() {
+				if ref.UID != qsec.GetUID() {
+					refs = append(refs, ref)
+				}
+			}
+			configMap.SetOwnerReferences(refs)
+			delete(configMap.Labels, qsv1a1.LabelKind)
+			delete(configMap.Annotations, qsv1a1.AnnotationPublicOutputOf)
+			if err := r.client.Update(ctx, configMap); err != nil {
+				return errors.Wrapf(err, "could not orphan config map '%s/%s'", configMap.Namespace, configMap.Name)
+			}
+			ctxlog.Debugf(ctx, "Orphaned config map '%s/%s'", configMap.Namespace, configMap.Name)
+			continue
+		}
+
+		if err := r.client.Delete(ctx, configMap); err != nil && !apierrors.IsNotFound(err) {
+			return errors.Wrapf(err, "could not delete config map '%s/%s'", configMap.Namespace, configMap.Name)
+		}
+		ctxlog.Debugf(ctx, "Deleted config map '%s/%s'", configMap.Namespace, configMap.Name)
+	}
+	return nil
+}
+
+// cleanupCSR deletes the CSR and its private key secret, which are left
+// behind, if the quarks secret is deleted before the certificate was issued.
+// The cluster signer can also be set by the referenced issuer.
+func (r *ReconcileCleanup) cleanupCSR(ctx context.Context, qsec *qsv1a1.QuarksSecret) error {
+	if qsec.Spec.Type != qsv1a1.Certificate {
+		return nil
+	}
+
+	issued := qsec.DeepCopy()
+	if err := applyIssuer(ctx, r.client, issued); err != nil {
+		// the issuer might be deleted already, deleting a missing CSR is fine
+		ctxlog.Debugf(ctx, "Deleting CSR of QuarksSecret '%s', its issuer can't be read: %s", qsec.GetNamespacedName(), err)
+	} else if issued.Spec.Request.CertificateRequest.SignerType != qsv1a1.ClusterSigner {
+		return nil
+	}
+
+	csrName := names.CSRName(qsec.Namespace, qsec.Name)
+	if err := deleteCSR(ctx, r.client, csrName); err != nil && !apierrors.IsNotFound(err) {
+		return errors.Wrapf(err, "could not delete csr '%s'", csrName)
+	}
+
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+		Name:      names.CsrPrivateKeySecretName(csrName),
+		Namespace: qsec.Namespace,
+	}}
+	if err := r.client.Delete(ctx, secret); err != nil && !apierrors.IsNotFound(err) {
+		return errors.Wrapf(err, "could not delete secret '%s/%s'", secret.Namespace, secret.Name)
+	}
+
+	return nil
+}
@@ -0,0 +1,259 @@
+package quarkssecret_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"go.uber.org/zap"
+
+	certv1 "k8s.io/api/certificates/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	crc "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	qsv1a1 "code.cloudfoundry.org/quarks-secret/pkg/kube/apis/quarkssecret/v1alpha1"
+	"code.cloudfoundry.org/quarks-secret/pkg/kube/client/clientset/versioned/scheme"
+	"code.cloudfoundry.org/quarks-secret/pkg/kube/controllers"
+	cfakes "code.cloudfoundry.org/quarks-secret/pkg/kube/controllers/fakes"
+	qscontroller "code.cloudfoundry.org/quarks-secret/pkg/kube/controllers/quarkssecret"
+	cfcfg "code.cloudfoundry.org/quarks-utils/pkg/config"
+	"code.cloudfoundry.org/quarks-utils/pkg/ctxlog"
+	helper "code.cloudfoundry.org/quarks-utils/testing/testhelper"
+)
+
+var _ = Describe("ReconcileCleanup", func() {
+	var (
+		manager      *cfakes.FakeManager
+		reconciler   reconcile.Reconciler
+		request      reconcile.Request
+		ctx          context.Context
+		log          *zap.SugaredLogger
+		config       *cfcfg.Config
+		client       *cfakes.FakeClient
+		statusWriter *cfakes.FakeStatusWriter
+		qSecret      *qsv1a1.QuarksSecret
+		secret       *corev1.Secret
+		copySecret   *corev1.Secret
+	)
+
+	// patchedFinalizers returns the finalizers of the last patch
+	patchedFinalizers := func() []string {
+		Expect(client.PatchCallCount()).To(Equal(1))
+		_, object, _, _ := client.PatchArgsForCall(0)
+		return object.GetFinalizers()
+	}
+
+	// deleted returns the names of the deleted objects
+	deleted := func() []string {
+		result := []string{}
+		for i := 0; i < client.DeleteCallCount(); i++ {
+			_, object, _ := client.DeleteArgsForCall(i)
+			result = append(result, object.GetNamespace()+"/"+object.GetName())
+		}
+		return result
+	}
+
+	BeforeEach(func() {
+		err := controllers.AddToScheme(scheme.Scheme)
+		Expect(err).ToNot(HaveOccurred())
+		manager = &cfakes.FakeManager{}
+		request = reconcile.Request{NamespacedName: types.NamespacedName{Name: "foo", Namespace: "default"}}
+		config = &cfcfg.Config{CtxTimeOut: 10 * time.Second}
+		_, log = helper.NewTestLogger()
+		ctx = ctxlog.NewContextWithRecorder(ctxlog.NewParentContext(log), "TestRecorder", record.NewFakeRecorder(10))
+
+		now := metav1.Now()
+		qSecret = &qsv1a1.QuarksSecret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              "foo",
+				Namespace:         "default",
+				UID:               "qsec-uid",
+				DeletionTimestamp: &now,
+				Finalizers:        []string{qsv1a1.FinalizerCleanup},
+			},
+			Spec: qsv1a1.QuarksSecretSpec{
+				Type:       "password",
+				SecretName: "generated-secret",
+				Copies:     []qsv1a1.Copy{{Name: "copied-secret", Namespace: "other"}},
+			},
+		}
+		secret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            "generated-secret",
+				Namespace:       "default",
+				Labels:          map[string]string{qsv1a1.LabelKind: qsv1a1.GeneratedSecretKind},
+				OwnerReferences: []metav1.OwnerReference{{Name: "foo", UID: "qsec-uid"}},
+			},
+		}
+		copySecret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "copied-secret",
+				Namespace:   "other",
+				Annotations: map[string]string{qsv1a1.AnnotationCopyOf: "default/foo"},
+			},
+		}
+
+		client = &cfakes.FakeClient{}
+		client.GetCalls(func(context context.Context, nn types.NamespacedName, object crc.Object) error {
+			switch object := object.(type) {
+			case *qsv1a1.QuarksSecret:
+				qSecret.DeepCopyInto(object)
+				return nil
+			case *corev1.Secret:
+				if nn.Name == secret.Name {
+					secret.DeepCopyInto(object)
+					return nil
+				}
+				if nn.Name == copySecret.Name {
+					copySecret.DeepCopyInto(object)
+					return nil
+				}
+			}
+			return apierrors.NewNotFound(schema.GroupResource{}, nn.Name)
+		})
+		statusWriter = &cfakes.FakeStatusWriter{}
+		client.StatusCalls(func() crc.StatusWriter { return statusWriter })
+		manager.GetClientReturns(client)
+	})
+
+	JustBeforeEach(func() {
+		reconciler = qscontroller.NewCleanupReconciler(ctx, config, manager)
+	})
+
+	It("adds the finalizer to a quarks secret", func() {
+		qSecret.DeletionTimestamp = nil
+		qSecret.Finalizers = nil
+
+		_, err := reconciler.Reconcile(context.Background(), request)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(patchedFinalizers()).To(ConsistOf(qsv1a1.FinalizerCleanup))
+		Expect(client.DeleteCallCount()).To(Equal(0))
+	})
+
+	It("deletes the copies and removes the finalizer", func() {
+		_, err := reconciler.Reconcile(context.Background(), request)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(deleted()).To(ConsistOf("other/copied-secret"))
+		Expect(client.UpdateCallCount()).To(Equal(0))
+		Expect(patchedFinalizers()).To(BeEmpty())
+	})
+
+	It("leaves secrets alone, which are not a copy of the quarks secret", func() {
+		copySecret.Annotations[qsv1a1.AnnotationCopyOf] = "default/bar"
+
+		_, err := reconciler.Reconcile(context.Background(), request)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(client.DeleteCallCount()).To(Equal(0))
+		Expect(patchedFinalizers()).To(BeEmpty())
+	})
+
+	It("orphans the copies and the generated secret, if the deletion policy is orphan", func() {
+		qSecret.Spec.DeletionPolicy = qsv1a1.DeletionOrphan
+
+		_, err := reconciler.Reconcile(context.Background(), request)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(client.DeleteCallCount()).To(Equal(0))
+		Expect(client.UpdateCallCount()).To(Equal(2))
+
+		_, object, _ := client.UpdateArgsForCall(0)
+		Expect(object.GetName()).To(Equal("copied-secret"))
+		Expect(object.GetAnnotations()).ToNot(HaveKey(qsv1a1.AnnotationCopyOf))
+
+		_, object, _ = client.UpdateArgsForCall(1)
+		Expect(object.GetName()).To(Equal("generated-secret"))
+		Expect(object.GetOwnerReferences()).To(BeEmpty())
+		Expect(object.GetLabels()).ToNot(HaveKey(qsv1a1.LabelKind))
+
+		Expect(patchedFinalizers()).To(BeEmpty())
+	})
+
+	It("deletes the public output config maps", func() {
+		client.ListCalls(func(context context.Context, object crc.ObjectList, _ ...crc.ListOption) error {
+			if list, ok := object.(*corev1.ConfigMapList); ok {
+				list.Items = []corev1.ConfigMap{
+					{ObjectMeta: metav1.ObjectMeta{
+						Name:        "foo-public",
+						Namespace:   "app",
+						Annotations: map[string]string{qsv1a1.AnnotationPublicOutputOf: "default/foo"},
+					}},
+					{ObjectMeta: metav1.ObjectMeta{
+						Name:        "bar-public",
+						Namespace:   "app",
+						Annotations: map[string]string{qsv1a1.AnnotationPublicOutputOf: "default/bar"},
+					}},
+				}
+			}
+			return nil
+		})
+
+		_, err := reconciler.Reconcile(context.Background(), request)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(deleted()).To(ConsistOf("other/copied-secret", "app/foo-public"))
+		Expect(patchedFinalizers()).To(BeEmpty())
+	})
+
+	It("deletes the CSR and its private key secret", func() {
+		qSecret.Spec.Type = qsv1a1.Certificate
+		qSecret.Spec.Copies = nil
+		qSecret.Spec.Request.CertificateRequest.SignerType = qsv1a1.ClusterSigner
+
+		_, err := reconciler.Reconcile(context.Background(), request)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(client.DeleteCallCount()).To(Equal(2))
+		_, object, _ := client.DeleteArgsForCall(0)
+		Expect(object).To(BeAssignableToTypeOf(&certv1.CertificateSigningRequest{}))
+		_, object, _ = client.DeleteArgsForCall(1)
+		Expect(object.GetNamespace()).To(Equal("default"))
+		Expect(object.GetName()).To(HaveSuffix("-csr-private-key"))
+		Expect(patchedFinalizers()).To(BeEmpty())
+	})
+
+	It("deletes the CSR of a certificate, whose issuer uses the cluster signer", func() {
+		qSecret.Spec.Type = qsv1a1.Certificate
+		qSecret.Spec.Copies = nil
+		qSecret.Spec.Request.CertificateRequest.IssuerRef = &qsv1a1.IssuerReference{Name: "cluster"}
+		client.GetCalls(func(context context.Context, nn types.NamespacedName, object crc.Object) error {
+			switch object := object.(type) {
+			case *qsv1a1.QuarksSecret:
+				qSecret.DeepCopyInto(object)
+				return nil
+			case *qsv1a1.QuarksIssuer:
+				object.Spec.SignerType = qsv1a1.ClusterSigner
+				return nil
+			}
+			return apierrors.NewNotFound(schema.GroupResource{}, nn.Name)
+		})
+
+		_, err := reconciler.Reconcile(context.Background(), request)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(client.DeleteCallCount()).To(Equal(2))
+		_, object, _ := client.DeleteArgsForCall(0)
+		Expect(object).To(BeAssignableToTypeOf(&certv1.CertificateSigningRequest{}))
+		Expect(patchedFinalizers()).To(BeEmpty())
+	})
+
+	It("keeps the finalizer, if the cleanup fails", func() {
+		client.DeleteReturns(apierrors.NewForbidden(schema.GroupResource{}, "copied-secret", nil))
+
+		_, err := reconciler.Reconcile(context.Background(), request)
+		Expect(err).To(HaveOccurred())
+		Expect(client.PatchCallCount()).To(Equal(0))
+	})
+
+	It("cleans up and removes the finalizer, while the quarks secret is paused", func() {
+		qSecret.Spec.Paused = true
+
+		_, err := reconciler.Reconcile(context.Background(), request)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(deleted()).To(ConsistOf("other/copied-secret"))
+		Expect(patchedFinalizers()).To(BeEmpty())
+		Expect(statusWriter.UpdateCallCount()).To(Equal(0))
+	})
+})
@@ -0,0 +1,75 @@
+package quarkssecret
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	qsv1a1 "code.cloudfoundry.org/quarks-secret/pkg/kube/apis/quarkssecret/v1alpha1"
+	"code.cloudfoundry.org/quarks-utils/pkg/config"
+	"code.cloudfoundry.org/quarks-utils/pkg/ctxlog"
+)
+
+// AddClusterQuarksSecret creates a new cluster quarks secret controller,
+// which generates the secrets of ClusterQuarksSecrets via QuarksSecrets in
+// the operator namespace.
+func AddClusterQuarksSecret(ctx context.Context, config *config.Config, mgr manager.Manager) error {
+	ctx = ctxlog.NewContextWithRecorder(ctx, "cluster-quarks-secret-reconciler", mgr.GetEventRecorderFor("cluster-quarks-secret-recorder"))
+	r := NewClusterQuarksSecretReconciler(ctx, config, mgr, controllerutil.SetControllerReference)
+
+	c, err := controller.New("cluster-quarks-secret-controller", mgr, controller.Options{
+		Reconciler:              r,
+		MaxConcurrentReconciles: config.MaxQuarksSecretWorkers,
+	})
+	if err != nil {
+		return errors.Wrap(err, "Adding cluster quarks secret controller to manager failed.")
+	}
+
+	// Watch for cluster quarks secrets, which are created or whose spec changes
+	p := predicate.Funcs{
+		CreateFunc:  func(e event.CreateEvent) bool { return true },
+		DeleteFunc:  func(e event.DeleteEvent) bool { return false },
+		GenericFunc: func(e event.GenericEvent) bool { return false },
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			n := e.ObjectNew.(*qsv1a1.ClusterQuarksSecret)
+			o := e.ObjectOld.(*qsv1a1.ClusterQuarksSecret)
+
+			return !reflect.DeepEqual(n.Spec, o.Spec)
+		},
+	}
+	err = c.Watch(&source.Kind{Type: &qsv1a1.ClusterQuarksSecret{}}, &handler.EnqueueRequestForObject{}, p)
+	if err != nil {
+		return errors.Wrapf(err, "Watching cluster quarks secrets failed in cluster quarks secret controller.")
+	}
+
+	// Watch for the quarks secrets of cluster quarks secrets, whose spec
+	// drifts or whose status changes
+	p = predicate.Funcs{
+		CreateFunc:  func(e event.CreateEvent) bool { return false },
+		DeleteFunc:  func(e event.DeleteEvent) bool { return true },
+		GenericFunc: func(e event.GenericEvent) bool { return false },
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			n := e.ObjectNew.(*qsv1a1.QuarksSecret)
+			o := e.ObjectOld.(*qsv1a1.QuarksSecret)
+
+			return !reflect.DeepEqual(n.Spec, o.Spec) || !reflect.DeepEqual(n.Status, o.Status)
+		},
+	}
+	err = c.Watch(&source.Kind{Type: &qsv1a1.QuarksSecret{}}, &handler.EnqueueRequestForOwner{
+		IsController: true,
+		OwnerType:    &qsv1a1.ClusterQuarksSecret{},
+	}, p)
+	if err != nil {
+		return errors.Wrapf(err, "Watching quarks secrets failed in cluster quarks secret controller.")
+	}
+
+	return nil
+}
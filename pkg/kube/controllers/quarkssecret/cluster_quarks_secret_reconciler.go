@@ -0,0 +1,108 @@
+package quarkssecret
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	qsv1a1 "code.cloudfoundry.org/quarks-secret/pkg/kube/apis/quarkssecret/v1alpha1"
+	"code.cloudfoundry.org/quarks-utils/pkg/config"
+	"code.cloudfoundry.org/quarks-utils/pkg/ctxlog"
+)
+
+// NewClusterQuarksSecretReconciler returns a new ReconcileClusterQuarksSecret
+func NewClusterQuarksSecretReconciler(ctx context.Context, config *config.Config, mgr manager.Manager, srf setReferenceFunc) reconcile.Reconciler {
+	return &ReconcileClusterQuarksSecret{
+		ctx:          ctx,
+		config:       config,
+		client:       mgr.GetClient(),
+		scheme:       mgr.GetScheme(),
+		setReference: srf,
+	}
+}
+
+// ReconcileClusterQuarksSecret maintains the QuarksSecret in the operator
+// namespace, which generates the secret of a ClusterQuarksSecret
+type ReconcileClusterQuarksSecret struct {
+	ctx          context.Context
+	client       client.Client
+	scheme       *runtime.Scheme
+	config       *config.Config
+	setReference setReferenceFunc
+}
+
+// Reconcile creates or updates a QuarksSecret with the spec of the
+// ClusterQuarksSecret in the operator namespace and mirrors its status. The
+// generated secret stays in the operator namespace, certificates in other
+// namespaces reference it by the name of the ClusterQuarksSecret.
+func (r *ReconcileClusterQuarksSecret) Reconcile(_ context.Context, request reconcile.Request) (reconcile.Result, error) {
+	cqsec := &qsv1a1.ClusterQuarksSecret{}
+
+	// Set the ctx to be Background, as the top-level context for incoming requests.
+	ctx, cancel := context.WithTimeout(r.ctx, r.config.CtxTimeOut)
+	defer cancel()
+
+	ctxlog.Infof(ctx, "Reconciling ClusterQuarksSecret %s", request.Name)
+	err := r.client.Get(ctx, types.NamespacedName{Name: request.Name}, cqsec)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			ctxlog.Info(ctx, "Skip reconcile: cluster quarks secret not found")
+			return reconcile.Result{}, nil
+		}
+		ctxlog.Info(ctx, "Error reading the object")
+		return reconcile.Result{}, errors.Wrap(err, "Error reading clusterQuarksSecret")
+	}
+
+	if !cqsec.GetDeletionTimestamp().IsZero() {
+		ctxlog.Debugf(ctx, "Skip reconcile: ClusterQuarksSecret '%s' is being deleted", cqsec.Name)
+		return reconcile.Result{}, nil
+	}
+
+	if r.config.OperatorNamespace == "" {
+		return reconcile.Result{}, ctxlog.WithEvent(cqsec, "ClusterQuarksSecretFailed").Errorf(ctx, "Failed to reconcile ClusterQuarksSecret '%s': operator namespace is not configured", cqsec.Name)
+	}
+
+	qsec := &qsv1a1.QuarksSecret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      cqsec.Name,
+			Namespace: r.config.OperatorNamespace,
+		},
+	}
+	op, err := controllerutil.CreateOrUpdate(ctx, r.client, qsec, func() error {
+		if !qsec.CreationTimestamp.IsZero() && qsec.Labels[qsv1a1.LabelClusterQuarksSecret] != cqsec.Name {
+			return fmt.Errorf("quarks secret '%s' already exists and doesn't belong to the cluster quarks secret", qsec.GetNamespacedName())
+		}
+		if qsec.Labels == nil {
+			qsec.Labels = map[string]string{}
+		}
+		qsec.Labels[qsv1a1.LabelClusterQuarksSecret] = cqsec.Name
+		qsec.Spec = *cqsec.Spec.DeepCopy()
+
+		return r.setReference(cqsec, qsec, r.scheme)
+	})
+	if err != nil {
+		return reconcile.Result{}, ctxlog.WithEvent(cqsec, "ClusterQuarksSecretFailed").Errorf(ctx, "Failed to write quarks secret of ClusterQuarksSecret '%s': %s", cqsec.Name, err)
+	}
+	if op != controllerutil.OperationResultNone {
+		ctxlog.Debugf(ctx, "Quarks secret '%s' of ClusterQuarksSecret '%s' has been %s", qsec.GetNamespacedName(), cqsec.Name, op)
+	}
+
+	if !reflect.DeepEqual(cqsec.Status, qsec.Status) {
+		cqsec.Status = *qsec.Status.DeepCopy()
+		if err := r.client.Status().Update(ctx, cqsec); err != nil {
+			return reconcile.Result{}, errors.Wrapf(err, "could not update status of ClusterQuarksSecret '%s'", cqsec.Name)
+		}
+	}
+
+	return reconcile.Result{}, nil
+}
@@ -0,0 +1,153 @@
+package quarkssecret_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"go.uber.org/zap"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	crc "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	qsv1a1 "code.cloudfoundry.org/quarks-secret/pkg/kube/apis/quarkssecret/v1alpha1"
+	"code.cloudfoundry.org/quarks-secret/pkg/kube/client/clientset/versioned/scheme"
+	"code.cloudfoundry.org/quarks-secret/pkg/kube/controllers"
+	cfakes "code.cloudfoundry.org/quarks-secret/pkg/kube/controllers/fakes"
+	qscontroller "code.cloudfoundry.org/quarks-secret/pkg/kube/controllers/quarkssecret"
+	cfcfg "code.cloudfoundry.org/quarks-utils/pkg/config"
+	"code.cloudfoundry.org/quarks-utils/pkg/ctxlog"
+	helper "code.cloudfoundry.org/quarks-utils/testing/testhelper"
+)
+
+var _ = Describe("ReconcileClusterQuarksSecret", func() {
+	var (
+		manager          *cfakes.FakeManager
+		reconciler       reconcile.Reconciler
+		request          reconcile.Request
+		ctx              context.Context
+		log              *zap.SugaredLogger
+		config           *cfcfg.Config
+		client           *cfakes.FakeClient
+		statusWriter     *cfakes.FakeStatusWriter
+		cqsec            *qsv1a1.ClusterQuarksSecret
+		existing         *qsv1a1.QuarksSecret
+		owners           []string
+		setReferenceFunc func(owner, object metav1.Object, scheme *runtime.Scheme) error
+	)
+
+	BeforeEach(func() {
+		err := controllers.AddToScheme(scheme.Scheme)
+		Expect(err).ToNot(HaveOccurred())
+		manager = &cfakes.FakeManager{}
+		request = reconcile.Request{NamespacedName: types.NamespacedName{Name: "platform-ca"}}
+		config = &cfcfg.Config{CtxTimeOut: 10 * time.Second, OperatorNamespace: "quarks"}
+		_, log = helper.NewTestLogger()
+		ctx = ctxlog.NewContextWithRecorder(ctxlog.NewParentContext(log), "TestRecorder", record.NewFakeRecorder(10))
+		owners = []string{}
+		setReferenceFunc = func(owner, object metav1.Object, scheme *runtime.Scheme) error {
+			owners = append(owners, owner.GetName())
+			return nil
+		}
+
+		cqsec = &qsv1a1.ClusterQuarksSecret{
+			ObjectMeta: metav1.ObjectMeta{Name: "platform-ca"},
+			Spec: qsv1a1.QuarksSecretSpec{
+				Type:       qsv1a1.Certificate,
+				SecretName: "platform-ca",
+				Request: qsv1a1.Request{
+					CertificateRequest: qsv1a1.CertificateRequest{IsCA: true, CommonName: "platform"},
+				},
+			},
+		}
+		existing = nil
+
+		client = &cfakes.FakeClient{}
+		client.GetCalls(func(context context.Context, nn types.NamespacedName, object crc.Object) error {
+			switch object := object.(type) {
+			case *qsv1a1.ClusterQuarksSecret:
+				cqsec.DeepCopyInto(object)
+				return nil
+			case *qsv1a1.QuarksSecret:
+				if existing != nil {
+					existing.DeepCopyInto(object)
+					return nil
+				}
+			}
+			return apierrors.NewNotFound(schema.GroupResource{}, nn.Name)
+		})
+		statusWriter = &cfakes.FakeStatusWriter{}
+		client.StatusCalls(func() crc.StatusWriter { return statusWriter })
+		manager.GetClientReturns(client)
+		manager.GetSchemeReturns(scheme.Scheme)
+	})
+
+	JustBeforeEach(func() {
+		reconciler = qscontroller.NewClusterQuarksSecretReconciler(ctx, config, manager, setReferenceFunc)
+	})
+
+	It("creates a quarks secret with the same spec in the operator namespace", func() {
+		_, err := reconciler.Reconcile(context.Background(), request)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(client.CreateCallCount()).To(Equal(1))
+		_, object, _ := client.CreateArgsForCall(0)
+		qsec := object.(*qsv1a1.QuarksSecret)
+		Expect(qsec.Name).To(Equal("platform-ca"))
+		Expect(qsec.Namespace).To(Equal("quarks"))
+		Expect(qsec.Labels).To(HaveKeyWithValue(qsv1a1.LabelClusterQuarksSecret, "platform-ca"))
+		Expect(qsec.Spec).To(Equal(cqsec.Spec))
+		Expect(owners).To(Equal([]string{"platform-ca"}))
+	})
+
+	It("updates the quarks secret and mirrors its status", func() {
+		generated := true
+		existing = &qsv1a1.QuarksSecret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              "platform-ca",
+				Namespace:         "quarks",
+				CreationTimestamp: metav1.Now(),
+				Labels:            map[string]string{qsv1a1.LabelClusterQuarksSecret: "platform-ca"},
+			},
+			Spec:   qsv1a1.QuarksSecretSpec{Type: qsv1a1.Certificate, SecretName: "old"},
+			Status: qsv1a1.QuarksSecretStatus{Generated: &generated},
+		}
+
+		_, err := reconciler.Reconcile(context.Background(), request)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(client.UpdateCallCount()).To(Equal(1))
+		_, object, _ := client.UpdateArgsForCall(0)
+		Expect(object.(*qsv1a1.QuarksSecret).Spec.SecretName).To(Equal("platform-ca"))
+
+		Expect(statusWriter.UpdateCallCount()).To(Equal(1))
+		_, object, _ = statusWriter.UpdateArgsForCall(0)
+		Expect(object.(*qsv1a1.ClusterQuarksSecret).Status.Generated).To(Equal(&generated))
+	})
+
+	It("does not take over quarks secrets, which don't belong to it", func() {
+		existing = &qsv1a1.QuarksSecret{
+			ObjectMeta: metav1.ObjectMeta{Name: "platform-ca", Namespace: "quarks", CreationTimestamp: metav1.Now()},
+		}
+
+		_, err := reconciler.Reconcile(context.Background(), request)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("already exists"))
+		Expect(client.UpdateCallCount()).To(Equal(0))
+	})
+
+	It("fails without an operator namespace", func() {
+		config.OperatorNamespace = ""
+
+		_, err := reconciler.Reconcile(context.Background(), request)
+		Expect(err).To(HaveOccurred())
+		Expect(client.CreateCallCount()).To(Equal(0))
+	})
+})
@@ -0,0 +1,89 @@
+package quarkssecret
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	qsv1a1 "code.cloudfoundry.org/quarks-secret/pkg/kube/apis/quarkssecret/v1alpha1"
+	"code.cloudfoundry.org/quarks-utils/pkg/ctxlog"
+)
+
+// reasonUserProvided is the reason of the Generated condition, if the secret
+// was created by the user
+const reasonUserProvided = "UserProvided"
+
+// setCondition sets a condition and the observed generation on the status of
+// the quarks secret. The Ready condition is derived from the other conditions.
+func setCondition(qsec *qsv1a1.QuarksSecret, conditionType string, status metav1.ConditionStatus, reason, message string) {
+	meta.SetStatusCondition(&qsec.Status.Conditions, metav1.Condition{
+		Type:               conditionType,
+		Status:             status,
+		ObservedGeneration: qsec.Generation,
+		Reason:             reason,
+		Message:            message,
+	})
+	qsec.Status.ObservedGeneration = qsec.Generation
+	setReadyCondition(qsec)
+}
+
+// removeCondition removes a condition, which no longer applies to the quarks secret
+func removeCondition(qsec *qsv1a1.QuarksSecret, conditionType string) {
+	meta.RemoveStatusCondition(&qsec.Status.Conditions, conditionType)
+	qsec.Status.ObservedGeneration = qsec.Generation
+	setReadyCondition(qsec)
+}
+
+// isUserProvided returns true, if the secret was created by the user instead
+// of being generated
+func isUserProvided(qsec *qsv1a1.QuarksSecret) bool {
+	c := meta.FindStatusCondition(qsec.Status.Conditions, qsv1a1.ConditionGenerated)
+	return c != nil && c.Reason == reasonUserProvided
+}
+
+// setReadyCondition sets Ready to true, if the secret was generated and no
+// other condition is false. Otherwise it copies the reason of the failing
+// condition.
+func setReadyCondition(qsec *qsv1a1.QuarksSecret) {
+	ready := metav1.Condition{
+		Type:               qsv1a1.ConditionReady,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: qsec.Generation,
+		Reason:             "Ready",
+		Message:            fmt.Sprintf("Secret '%s' is ready", qsec.Spec.SecretName),
+	}
+
+	for _, c := range qsec.Status.Conditions {
+		if c.Type != qsv1a1.ConditionReady && c.Status == metav1.ConditionFalse {
+			ready.Status = metav1.ConditionFalse
+			ready.Reason = c.Reason
+			ready.Message = c.Message
+			break
+		}
+	}
+
+	if ready.Status == metav1.ConditionTrue && !meta.IsStatusConditionTrue(qsec.Status.Conditions, qsv1a1.ConditionGenerated) {
+		ready.Status = metav1.ConditionFalse
+		ready.Reason = "NotGenerated"
+		ready.Message = fmt.Sprintf("Secret '%s' has not been generated yet", qsec.Spec.SecretName)
+	}
+
+	meta.SetStatusCondition(&qsec.Status.Conditions, ready)
+}
+
+// updateConditions stores the status of the quarks secret, if the conditions
+// changed. Errors are only logged, as conditions are informational.
+func updateConditions(ctx context.Context, c client.Client, qsec *qsv1a1.QuarksSecret, old *qsv1a1.QuarksSecretStatus) {
+	if reflect.DeepEqual(*old, qsec.Status) {
+		return
+	}
+
+	err := c.Status().Update(ctx, qsec)
+	if err != nil {
+		ctxlog.Errorf(ctx, "could not update conditions of QuarksSecret '%s': %v", qsec.GetNamespacedName(), err)
+	}
+}
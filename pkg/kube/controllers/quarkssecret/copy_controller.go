@@ -26,10 +26,10 @@ import (
 
 // AddCopy creates a new QuarksSecrets controller to watch for the
 // user defined secrets.
-func AddCopy(ctx context.Context, config *config.Config, mgr manager.Manager) error {
+func AddCopy(ctx context.Context, config *config.Config, mgr manager.Manager, allowUngrantedCopies bool) error {
 	ctx = ctxlog.NewContextWithRecorder(ctx, "copy-reconciler", mgr.GetEventRecorderFor("copy-recorder"))
 	log := ctxlog.ExtractLogger(ctx)
-	r := NewCopyReconciler(ctx, config, mgr, credsgen.NewInMemoryGenerator(log), controllerutil.SetControllerReference)
+	r := NewCopyReconciler(ctx, config, mgr, credsgen.NewInMemoryGenerator(log), controllerutil.SetControllerReference, allowUngrantedCopies)
 
 	c, err := controller.New("copy-controller", mgr, controller.Options{
 		Reconciler:              r,
@@ -49,6 +49,17 @@ func AddCopy(ctx context.Context, config *config.Config, mgr manager.Manager) er
 		UpdateFunc: func(e event.UpdateEvent) bool {
 			n := e.ObjectNew.(*qsv1a1.QuarksSecret)
 
+			o := e.ObjectOld.(*qsv1a1.QuarksSecret)
+
+			if isResumed(o, n, qsv1a1.WorkCopy) {
+				return true
+			}
+
+			// Prune copies, which were removed from the spec
+			if !reflect.DeepEqual(n.Spec.Copies, o.Spec.Copies) {
+				return true
+			}
+
 			if n.Status.Copied != nil {
 				ctxlog.Debugf(ctx, "Skipping QuarksSecret '%s', if copy status '%v' is true", n.Name, *n.Status.Copied)
 				return !(*n.Status.Copied)
@@ -102,6 +113,55 @@ func AddCopy(ctx context.Context, config *config.Config, mgr manager.Manager) er
 		return errors.Wrapf(err, "Watching user defined secrets failed in copy controller.")
 	}
 
+	// Watch for namespaces, which appear, disappear or change their labels
+	// or annotations, to keep the copies to other namespaces in sync
+	p = predicate.Funcs{
+		CreateFunc:  func(e event.CreateEvent) bool { return true },
+		DeleteFunc:  func(e event.DeleteEvent) bool { return true },
+		GenericFunc: func(e event.GenericEvent) bool { return false },
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			return !reflect.DeepEqual(e.ObjectNew.GetLabels(), e.ObjectOld.GetLabels()) ||
+				!reflect.DeepEqual(e.ObjectNew.GetAnnotations(), e.ObjectOld.GetAnnotations())
+		},
+	}
+	err = c.Watch(&source.Kind{Type: &corev1.Namespace{}}, handler.EnqueueRequestsFromMapFunc(
+		func(a crc.Object) []reconcile.Request {
+			reconciles, err := namespaceSelectorReconciles(ctx, mgr.GetClient(), config.MonitoredID, hasCopiesToOtherNamespaces)
+			if err != nil {
+				ctxlog.Errorf(ctx, "Failed to calculate reconciles for namespace '%s': %v", a.GetName(), err)
+			}
+
+			return reconciles
+		}), p)
+	if err != nil {
+		return errors.Wrapf(err, "Watching namespaces failed in copy controller.")
+	}
+
+	// Watch for copy grants, to copy or prune the copies they grant
+	p = predicate.Funcs{
+		CreateFunc:  func(e event.CreateEvent) bool { return true },
+		DeleteFunc:  func(e event.DeleteEvent) bool { return true },
+		GenericFunc: func(e event.GenericEvent) bool { return false },
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			n := e.ObjectNew.(*qsv1a1.QuarksSecretCopyGrant)
+			o := e.ObjectOld.(*qsv1a1.QuarksSecretCopyGrant)
+
+			return !reflect.DeepEqual(n.Spec, o.Spec)
+		},
+	}
+	err = c.Watch(&source.Kind{Type: &qsv1a1.QuarksSecretCopyGrant{}}, handler.EnqueueRequestsFromMapFunc(
+		func(a crc.Object) []reconcile.Request {
+			reconciles, err := grantReconciles(ctx, mgr.GetClient(), a, config.MonitoredID)
+			if err != nil {
+				ctxlog.Errorf(ctx, "Failed to calculate reconciles for copy grant '%s/%s': %v", a.GetNamespace(), a.GetName(), err)
+			}
+
+			return reconciles
+		}), p)
+	if err != nil {
+		return errors.Wrapf(err, "Watching copy grants failed in copy controller.")
+	}
+
 	return nil
 }
 
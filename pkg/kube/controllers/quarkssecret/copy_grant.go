@@ -0,0 +1,125 @@
+package quarkssecret
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	crc "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	qsv1a1 "code.cloudfoundry.org/quarks-secret/pkg/kube/apis/quarkssecret/v1alpha1"
+	"code.cloudfoundry.org/quarks-utils/pkg/ctxlog"
+)
+
+// deniedCopies returns the copies to other namespaces, which are not granted
+// by a QuarksSecretCopyGrant in the namespace of the quarks secret. Explicit
+// copies are also denied, unless the destination namespace allows copies
+// from the namespace of the quarks secret, like it does for selected copies.
+func (r *ReconcileCopy) deniedCopies(ctx context.Context, qsec *qsv1a1.QuarksSecret, selected []qsv1a1.Copy) ([]qsv1a1.Copy, error) {
+	denied := []qsv1a1.Copy{}
+
+	grants := &qsv1a1.QuarksSecretCopyGrantList{}
+	err := r.client.List(ctx, grants, crc.InNamespace(qsec.Namespace))
+	if err != nil {
+		return denied, errors.Wrapf(err, "could not list copy grants in namespace '%s'", qsec.Namespace)
+	}
+	if len(grants.Items) == 0 && r.allowUngrantedCopies {
+		return denied, nil
+	}
+
+	for _, copy := range selected {
+		if !isCopyGranted(grants.Items, qsec.Name, copy.Namespace) {
+			denied = append(denied, qsv1a1.Copy{Name: copy.Name, Namespace: copy.Namespace})
+		}
+	}
+
+	for _, copy := range qsec.Spec.Copies {
+		if copy.NamespaceSelector != nil || copy.Namespace == qsec.Namespace {
+			continue
+		}
+		if !isCopyGranted(grants.Items, qsec.Name, copy.Namespace) {
+			denied = append(denied, qsv1a1.Copy{Name: copy.Name, Namespace: copy.Namespace})
+			continue
+		}
+		if r.allowUngrantedCopies {
+			continue
+		}
+
+		ns := &corev1.Namespace{}
+		err := r.client.Get(ctx, types.NamespacedName{Name: copy.Namespace}, ns)
+		if err != nil && !apierrors.IsNotFound(err) {
+			return denied, errors.Wrapf(err, "could not get namespace '%s'", copy.Namespace)
+		}
+		if err != nil || !allowsCopiesFrom(ns, qsec.Namespace) {
+			ctxlog.Debugf(ctx, "Namespace '%s' does not allow copies from '%s'", copy.Namespace, qsec.Namespace)
+			denied = append(denied, qsv1a1.Copy{Name: copy.Name, Namespace: copy.Namespace})
+		}
+	}
+
+	return denied, nil
+}
+
+// isCopyGranted returns true, if one of the grants allows copying the quarks
+// secret to the namespace
+func isCopyGranted(grants []qsv1a1.QuarksSecretCopyGrant, qsecName string, namespace string) bool {
+	for i := range grants {
+		if grants[i].Grants(qsecName, namespace) {
+			return true
+		}
+	}
+	return false
+}
+
+// setCopyDenied reports the denied copies in the status of the quarks secret
+func setCopyDenied(ctx context.Context, qsec *qsv1a1.QuarksSecret, denied []qsv1a1.Copy) {
+	qsec.Status.DeniedCopies = nil
+	if len(denied) == 0 {
+		return
+	}
+	qsec.Status.DeniedCopies = denied
+
+	names := make([]string, len(denied))
+	for i := range denied {
+		names[i] = denied[i].String()
+	}
+	msg := fmt.Sprintf("Copies to '%s' are not granted or not allowed by the destination namespace", strings.Join(names, "', '"))
+	ctxlog.WithEvent(qsec, "CopyDenied").Infof(ctx, "Skip copy creation for QuarksSecret '%s': %s", qsec.GetNamespacedName(), msg)
+	setCondition(qsec, qsv1a1.ConditionCopied, metav1.ConditionFalse, "CopyDenied", msg)
+}
+
+// grantReconciles lists the quarks secrets with copies in the namespace of
+// the grant, if the namespace is monitored
+func grantReconciles(ctx context.Context, client crc.Client, grant crc.Object, id string) ([]reconcile.Request, error) {
+	ns := &corev1.Namespace{}
+	if err := client.Get(ctx, types.NamespacedName{Name: grant.GetNamespace()}, ns); err != nil {
+		return nil, errors.Wrapf(err, "failed to get namespace '%s'", grant.GetNamespace())
+	}
+	if !qsv1a1.IsMonitoredNamespace(ns, id) {
+		return []reconcile.Request{}, nil
+	}
+
+	quarksSecretList := &qsv1a1.QuarksSecretList{}
+	err := client.List(ctx, quarksSecretList, crc.InNamespace(grant.GetNamespace()))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list QuarksSecrets")
+	}
+
+	result := []reconcile.Request{}
+	for _, qsec := range quarksSecretList.Items {
+		if len(qsec.Spec.Copies) == 0 && len(qsec.Status.Copies) == 0 && len(qsec.Status.DeniedCopies) == 0 {
+			continue
+		}
+		result = append(result, reconcile.Request{
+			NamespacedName: types.NamespacedName{
+				Name:      qsec.Name,
+				Namespace: qsec.Namespace,
+			}})
+	}
+	return result, nil
+}
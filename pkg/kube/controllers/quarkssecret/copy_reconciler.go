@@ -2,10 +2,13 @@ package quarkssecret
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -24,26 +27,31 @@ import (
 	"code.cloudfoundry.org/quarks-utils/pkg/pointers"
 )
 
-// NewCopyReconciler returns a new ReconcileCopy
-func NewCopyReconciler(ctx context.Context, config *config.Config, mgr manager.Manager, generator credsgen.Generator, srf setReferenceFunc) reconcile.Reconciler {
+// NewCopyReconciler returns a new ReconcileCopy. allowUngrantedCopies
+// restores the behaviour before copy grants were required: copies are only
+// checked once a grant exists in the namespace of the quarks secret and the
+// destination namespace doesn't have to allow copies.
+func NewCopyReconciler(ctx context.Context, config *config.Config, mgr manager.Manager, generator credsgen.Generator, srf setReferenceFunc, allowUngrantedCopies bool) reconcile.Reconciler {
 	return &ReconcileCopy{
-		ctx:          ctx,
-		config:       config,
-		client:       mgr.GetClient(),
-		scheme:       mgr.GetScheme(),
-		generator:    generator,
-		setReference: srf,
+		ctx:                  ctx,
+		config:               config,
+		client:               mgr.GetClient(),
+		scheme:               mgr.GetScheme(),
+		generator:            generator,
+		setReference:         srf,
+		allowUngrantedCopies: allowUngrantedCopies,
 	}
 }
 
 // ReconcileCopy reconciles an QuarksSecret object
 type ReconcileCopy struct {
-	ctx          context.Context
-	client       client.Client
-	generator    credsgen.Generator
-	scheme       *runtime.Scheme
-	setReference setReferenceFunc
-	config       *config.Config
+	ctx                  context.Context
+	client               client.Client
+	generator            credsgen.Generator
+	scheme               *runtime.Scheme
+	setReference         setReferenceFunc
+	config               *config.Config
+	allowUngrantedCopies bool
 }
 
 // Reconcile reads sets the copied field in status spec to false and copies the secrets from source namespace
@@ -70,14 +78,45 @@ func (r *ReconcileCopy) Reconcile(_ context.Context, request reconcile.Request)
 		return reconcile.Result{}, errors.Wrap(err, "Error reading quarksSecret")
 	}
 
+	if !qsec.GetDeletionTimestamp().IsZero() {
+		ctxlog.Infof(ctx, "Skip reconcile: quarks secret '%s' is being deleted", qsec.GetNamespacedName())
+		return reconcile.Result{}, nil
+	}
+
+	if qsec.Spec.Paused {
+		return reconcile.Result{}, deferWork(ctx, r.client, qsec, qsv1a1.WorkCopy)
+	}
+
 	r.updateCopyStatus(ctx, qsec, false)
 
-	err = r.handleQuarksSecretCopies(ctx, qsec)
+	denied := []qsv1a1.Copy{}
+	selected, err := r.selectedCopies(ctx, qsec)
+	if err == nil {
+		denied, err = r.deniedCopies(ctx, qsec, selected)
+	}
+	if err == nil {
+		err = r.pruneCopies(ctx, qsec, selected, denied)
+	}
+	if err == nil {
+		err = r.handleQuarksSecretCopies(ctx, qsec, denied)
+	}
+	if err == nil {
+		err = r.handleSelectedCopies(ctx, qsec, selected, denied)
+	}
 	if err != nil {
 		ctxlog.Errorf(ctx, "Error handling quarks secret copies '%s'", qsec.Name, err.Error())
+		status := qsec.Status.DeepCopy()
+		setCondition(qsec, qsv1a1.ConditionCopied, metav1.ConditionFalse, "CopyFailed", err.Error())
+		updateConditions(ctx, r.client, qsec, status)
 		return reconcile.Result{}, errors.Wrap(err, "Error handling quarksSecret copies")
 	}
 
+	if len(qsec.Spec.Copies) > 0 {
+		setCondition(qsec, qsv1a1.ConditionCopied, metav1.ConditionTrue, "Copied", fmt.Sprintf("Secret '%s' has been copied", qsec.Spec.SecretName))
+	} else if meta.FindStatusCondition(qsec.Status.Conditions, qsv1a1.ConditionCopied) != nil {
+		removeCondition(qsec, qsv1a1.ConditionCopied)
+	}
+	setCopyDenied(ctx, qsec, denied)
 	r.updateCopyStatus(ctx, qsec, true)
 	return reconcile.Result{}, nil
 }
@@ -91,8 +130,12 @@ func (r *ReconcileCopy) updateCopyStatus(ctx context.Context, qsec *qsv1a1.Quark
 	}
 }
 
-func (r *ReconcileCopy) handleQuarksSecretCopies(ctx context.Context, sourceQuarksSecret *qsv1a1.QuarksSecret) error {
+func (r *ReconcileCopy) handleQuarksSecretCopies(ctx context.Context, sourceQuarksSecret *qsv1a1.QuarksSecret, denied []qsv1a1.Copy) error {
 	for _, copy := range sourceQuarksSecret.Spec.Copies {
+		if copy.NamespaceSelector != nil || containsCopy(denied, copy) {
+			continue
+		}
+
 		sourceSecret, err := GetSourceSecret(ctx, r.client, sourceQuarksSecret)
 		if err != nil {
 			return err
@@ -102,11 +145,16 @@ func (r *ReconcileCopy) handleQuarksSecretCopies(ctx context.Context, sourceQuar
 			return errors.Wrapf(err, "could not validate")
 		}
 
+		data, err := copyData(sourceSecret.Data, copy.Keys)
+		if err != nil {
+			return errors.Wrapf(err, "could not copy to '%s'", copy.String())
+		}
+
 		targetSecret := &corev1.Secret{}
 		if ok {
 			targetSecret.Name = copy.Name
 			targetSecret.Namespace = copy.Namespace
-			targetSecret.Data = sourceSecret.Data
+			targetSecret.Data = data
 			targetSecret.Annotations = sourceSecret.Annotations
 			targetSecret.Labels = sourceSecret.Labels
 
@@ -127,6 +175,9 @@ func (r *ReconcileCopy) handleQuarksSecretCopies(ctx context.Context, sourceQuar
 					return err
 				}
 			}
+			if !containsCopy(sourceQuarksSecret.Status.Copies, copy) {
+				sourceQuarksSecret.Status.Copies = append(sourceQuarksSecret.Status.Copies, qsv1a1.Copy{Name: copy.Name, Namespace: copy.Namespace})
+			}
 		} else {
 			ctxlog.WithEvent(sourceQuarksSecret, "CopyReconcile").Infof(ctx, "Skip copy creation: Secret/QSecret '%s' must exist and have the appropriate annotation to receive a copy", copy.String())
 		}
@@ -135,6 +186,109 @@ func (r *ReconcileCopy) handleQuarksSecretCopies(ctx context.Context, sourceQuar
 	return nil
 }
 
+// pruneCopies removes the credentials from the copies, which are tracked in
+// the status, but were removed from the spec, are no longer selected or are
+// no longer granted
+func (r *ReconcileCopy) pruneCopies(ctx context.Context, qsec *qsv1a1.QuarksSecret, selected []qsv1a1.Copy, denied []qsv1a1.Copy) error {
+	copies := []qsv1a1.Copy{}
+	for _, copy := range qsec.Status.Copies {
+		if (containsCopy(qsec.Spec.Copies, copy) || containsCopy(selected, copy)) && !containsCopy(denied, copy) {
+			copies = append(copies, copy)
+			continue
+		}
+
+		if err := r.pruneCopy(ctx, qsec, copy); err != nil {
+			return errors.Wrapf(err, "could not prune copy '%s'", copy.String())
+		}
+		ctxlog.WithEvent(qsec, "CopyReconcile").Infof(ctx, "Copy secret '%s' has been pruned from namespace '%s'", copy.Name, copy.Namespace)
+	}
+	qsec.Status.Copies = copies
+
+	return nil
+}
+
+// pruneCopy deletes the secret of a copy-type quarks secret target or a
+// copy created in a selected namespace. A pre-annotated secret is not
+// deleted, since it was created by the user, but its data is cleared.
+func (r *ReconcileCopy) pruneCopy(ctx context.Context, qsec *qsv1a1.QuarksSecret, copy qsv1a1.Copy) error {
+	copyOf := qsec.GetNamespacedName()
+
+	targetQuarksSecret := &qsv1a1.QuarksSecret{}
+	err := r.client.Get(ctx, types.NamespacedName{Name: qsec.Name, Namespace: copy.Namespace}, targetQuarksSecret)
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			return errors.Wrapf(err, "could not get target quarks secret")
+		}
+		targetQuarksSecret = nil
+	} else if targetQuarksSecret.Spec.Type != qsv1a1.SecretCopy || targetQuarksSecret.GetAnnotations()[qsv1a1.AnnotationCopyOf] != copyOf {
+		targetQuarksSecret = nil
+	}
+
+	targetSecret := &corev1.Secret{}
+	err = r.client.Get(ctx, types.NamespacedName{Name: copy.Name, Namespace: copy.Namespace}, targetSecret)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return errors.Wrapf(err, "could not get target secret")
+	}
+
+	if targetSecret.GetAnnotations()[qsv1a1.AnnotationCopyOf] != copyOf {
+		return nil
+	}
+
+	if targetQuarksSecret == nil && targetSecret.GetLabels()[qsv1a1.LabelKind] != qsv1a1.CopySecretKind {
+		targetSecret.Data = map[string][]byte{}
+		return r.updateCopySecret(ctx, targetSecret)
+	}
+
+	if err := r.client.Delete(ctx, targetSecret); err != nil && !apierrors.IsNotFound(err) {
+		return errors.Wrapf(err, "could not delete target secret '%s/%s'", targetSecret.Namespace, targetSecret.Name)
+	}
+
+	if targetQuarksSecret == nil {
+		return nil
+	}
+
+	status := targetQuarksSecret.Status.DeepCopy()
+	setCondition(targetQuarksSecret, qsv1a1.ConditionGenerated, metav1.ConditionFalse, "CopyRemoved", fmt.Sprintf("Secret is no longer copied from '%s'", copyOf))
+	updateConditions(ctx, r.client, targetQuarksSecret, status)
+
+	return nil
+}
+
+// containsCopy returns true, if the copies contain a copy with the same
+// name and namespace
+func containsCopy(copies []qsv1a1.Copy, copy qsv1a1.Copy) bool {
+	for _, c := range copies {
+		if c.Name == copy.Name && c.Namespace == copy.Namespace {
+			return true
+		}
+	}
+	return false
+}
+
+// copyData returns the data of the generated secret, which is copied. If
+// keys are listed, only these keys are copied and renamed.
+func copyData(data map[string][]byte, keys map[string]string) (map[string][]byte, error) {
+	if len(keys) == 0 {
+		return data, nil
+	}
+
+	result := make(map[string][]byte, len(keys))
+	for key, as := range keys {
+		value, ok := data[key]
+		if !ok {
+			return nil, errors.Errorf("key '%s' not found in generated secret", key)
+		}
+		if as == "" {
+			as = key
+		}
+		result[as] = value
+	}
+	return result, nil
+}
+
 // GetSourceSecret fetches the secret generated by QuarkSecret
 func GetSourceSecret(ctx context.Context, client client.Client, qsec *qsv1a1.QuarksSecret) (*corev1.Secret, error) {
 	secretName := qsec.Spec.SecretName
@@ -229,7 +383,14 @@ func (r *ReconcileCopy) createUpdateCopySecret(ctx context.Context, targetSecret
 		}
 	}
 
-	op, err := controllerutil.CreateOrUpdate(ctx, r.client, targetSecret, mutate.SecretMutateFn(targetSecret))
+	// The data is set explicitly, so keys which are no longer copied are
+	// removed from an existing copy
+	data := targetSecret.Data
+	mutateFn := mutate.SecretMutateFn(targetSecret)
+	op, err := controllerutil.CreateOrUpdate(ctx, r.client, targetSecret, func() error {
+		targetSecret.Data = data
+		return mutateFn()
+	})
 	if err != nil {
 		return errors.Wrapf(err, "could not create or update target secret '%s/%s'", targetSecret.Namespace, targetSecret.GetName())
 	}
@@ -237,6 +398,12 @@ func (r *ReconcileCopy) createUpdateCopySecret(ctx context.Context, targetSecret
 		ctxlog.Debugf(ctx, "Target Secret '%s' has been %s", targetSecret.Name, op)
 	}
 
+	if targetQuarksSecret != nil {
+		status := targetQuarksSecret.Status.DeepCopy()
+		setCondition(targetQuarksSecret, qsv1a1.ConditionGenerated, metav1.ConditionTrue, "Copied", fmt.Sprintf("Secret has been copied from '%s'", targetSecret.GetAnnotations()[qsv1a1.AnnotationCopyOf]))
+		updateConditions(ctx, r.client, targetQuarksSecret, status)
+	}
+
 	return nil
 }
 
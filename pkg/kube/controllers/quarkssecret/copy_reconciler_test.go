@@ -11,7 +11,9 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
@@ -42,6 +44,9 @@ var _ = Describe("ReconcileCopy", func() {
 		generator                      *generatorfakes.FakeGenerator
 		quarksSecret, quarksCopySecret *qsv1a1.QuarksSecret
 		passwordSecret                 *corev1.Secret
+		grants                         []qsv1a1.QuarksSecretCopyGrant
+		namespaces                     map[string]*corev1.Namespace
+		allowUngrantedCopies           bool
 		setReferenceFunc               func(owner, object metav1.Object, scheme *runtime.Scheme) error = func(owner, object metav1.Object, scheme *runtime.Scheme) error { return nil }
 	)
 
@@ -52,6 +57,20 @@ var _ = Describe("ReconcileCopy", func() {
 		secretName       = "generated-secret"
 	)
 
+	// withNamespaces serves the namespaces, before calling the get func
+	withNamespaces := func(get func(context.Context, types.NamespacedName, crc.Object) error) func(context.Context, types.NamespacedName, crc.Object) error {
+		return func(ctx context.Context, nn types.NamespacedName, object crc.Object) error {
+			if ns, ok := object.(*corev1.Namespace); ok {
+				if namespaces[nn.Name] == nil {
+					return errors.NewNotFound(schema.GroupResource{}, nn.Name)
+				}
+				namespaces[nn.Name].DeepCopyInto(ns)
+				return nil
+			}
+			return get(ctx, nn, object)
+		}
+	}
+
 	BeforeEach(func() {
 		err := controllers.AddToScheme(scheme.Scheme)
 		Expect(err).ToNot(HaveOccurred())
@@ -62,6 +81,7 @@ var _ = Describe("ReconcileCopy", func() {
 		ctx = ctxlog.NewParentContext(log)
 		generator = &generatorfakes.FakeGenerator{}
 		client = &cfakes.FakeClient{}
+		allowUngrantedCopies = false
 
 		quarksSecret = &qsv1a1.QuarksSecret{
 			ObjectMeta: metav1.ObjectMeta{
@@ -104,7 +124,7 @@ var _ = Describe("ReconcileCopy", func() {
 			},
 		}
 
-		client.GetCalls(func(context context.Context, nn types.NamespacedName, object crc.Object) error {
+		client.GetCalls(withNamespaces(func(context context.Context, nn types.NamespacedName, object crc.Object) error {
 			switch object := object.(type) {
 			case *qsv1a1.QuarksSecret:
 				if nn.Namespace == defaultNamespace {
@@ -121,18 +141,31 @@ var _ = Describe("ReconcileCopy", func() {
 				}
 			}
 			return nil
+		}))
+		grants = []qsv1a1.QuarksSecretCopyGrant{{
+			ObjectMeta: metav1.ObjectMeta{Name: "grant", Namespace: defaultNamespace},
+			Spec:       qsv1a1.QuarksSecretCopyGrantSpec{Namespaces: []string{"*"}},
+		}}
+		namespaces = map[string]*corev1.Namespace{
+			copyNamespace: {ObjectMeta: metav1.ObjectMeta{Name: copyNamespace, Annotations: map[string]string{qsv1a1.AnnotationAllowCopiesFrom: defaultNamespace}}},
+		}
+		client.ListCalls(func(context context.Context, object crc.ObjectList, _ ...crc.ListOption) error {
+			if list, ok := object.(*qsv1a1.QuarksSecretCopyGrantList); ok {
+				list.Items = grants
+			}
+			return nil
 		})
 		client.StatusCalls(func() crc.StatusWriter { return &cfakes.FakeStatusWriter{} })
 		manager.GetClientReturns(client)
 	})
 
 	JustBeforeEach(func() {
-		reconciler = qscontroller.NewCopyReconciler(ctx, config, manager, generator, setReferenceFunc)
+		reconciler = qscontroller.NewCopyReconciler(ctx, config, manager, generator, setReferenceFunc, allowUngrantedCopies)
 	})
 
 	When("the source secret is not found", func() {
 		It("should return an error", func() {
-			client.GetCalls(func(context context.Context, nn types.NamespacedName, object crc.Object) error {
+			client.GetCalls(withNamespaces(func(context context.Context, nn types.NamespacedName, object crc.Object) error {
 				switch object := object.(type) {
 				case *qsv1a1.QuarksSecret:
 					if nn.Namespace == defaultNamespace {
@@ -146,7 +179,7 @@ var _ = Describe("ReconcileCopy", func() {
 					return errors.NewNotFound(schema.GroupResource{}, "not found")
 				}
 				return nil
-			})
+			}))
 
 			result, err := reconciler.Reconcile(context.Background(), request)
 			Expect(err).To(HaveOccurred())
@@ -175,7 +208,7 @@ var _ = Describe("ReconcileCopy", func() {
 
 	When("everything is set properly", func() {
 		It("copying should be done", func() {
-			client.GetCalls(func(context context.Context, nn types.NamespacedName, object crc.Object) error {
+			client.GetCalls(withNamespaces(func(context context.Context, nn types.NamespacedName, object crc.Object) error {
 				switch object := object.(type) {
 				case *qsv1a1.QuarksSecret:
 					if nn.Namespace == defaultNamespace {
@@ -193,13 +226,394 @@ var _ = Describe("ReconcileCopy", func() {
 					}
 				}
 				return nil
-			})
+			}))
 
 			result, err := reconciler.Reconcile(context.Background(), request)
 			Expect(err).ToNot(HaveOccurred())
-			Expect(client.GetCallCount()).To(Equal(5))
+			Expect(client.GetCallCount()).To(Equal(6))
 			Expect(client.CreateCallCount()).To(Equal(1))
 			Expect(reconcile.Result{}).To(Equal(result))
 		})
 	})
+	When("copies were removed from the spec", func() {
+		var (
+			copySecret   *corev1.Secret
+			statusWriter *cfakes.FakeStatusWriter
+		)
+
+		BeforeEach(func() {
+			quarksSecret.Status.Copies = []qsv1a1.Copy{{Name: "generated-secret-copy", Namespace: copyNamespace}}
+			quarksSecret.Spec.Copies = nil
+
+			copySecret = &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "generated-secret-copy",
+					Namespace: copyNamespace,
+					Annotations: map[string]string{
+						"quarks.cloudfoundry.org/secret-copy-of": defaultNamespace + "/" + quarksSecretName,
+					},
+				},
+				Data: map[string][]byte{"password": []byte("securepassword")},
+			}
+
+			client.GetCalls(withNamespaces(func(context context.Context, nn types.NamespacedName, object crc.Object) error {
+				switch object := object.(type) {
+				case *qsv1a1.QuarksSecret:
+					if nn.Namespace == defaultNamespace {
+						quarksSecret.DeepCopyInto(object)
+						return nil
+					}
+					if quarksCopySecret != nil {
+						quarksCopySecret.DeepCopyInto(object)
+						return nil
+					}
+				case *corev1.Secret:
+					if nn.Name == secretName {
+						passwordSecret.DeepCopyInto(object)
+						return nil
+					}
+					copySecret.DeepCopyInto(object)
+					return nil
+				}
+				return errors.NewNotFound(schema.GroupResource{}, "not found")
+			}))
+			statusWriter = &cfakes.FakeStatusWriter{}
+			client.StatusCalls(func() crc.StatusWriter { return statusWriter })
+		})
+
+		It("clears the data of a pre-annotated secret", func() {
+			quarksCopySecret = nil
+
+			_, err := reconciler.Reconcile(context.Background(), request)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(client.DeleteCallCount()).To(Equal(0))
+			Expect(client.UpdateCallCount()).To(Equal(1))
+			_, object, _ := client.UpdateArgsForCall(0)
+			Expect(object.GetName()).To(Equal("generated-secret-copy"))
+			Expect(object.(*unstructured.Unstructured).Object["data"]).To(BeEmpty())
+
+			_, object, _ = statusWriter.UpdateArgsForCall(statusWriter.UpdateCallCount() - 1)
+			Expect(object.(*qsv1a1.QuarksSecret).Status.Copies).To(BeEmpty())
+		})
+
+		It("deletes the secret of a copy quarks secret", func() {
+			_, err := reconciler.Reconcile(context.Background(), request)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(client.DeleteCallCount()).To(Equal(1))
+			_, object, _ := client.DeleteArgsForCall(0)
+			Expect(object.GetNamespace()).To(Equal(copyNamespace))
+			Expect(object.GetName()).To(Equal("generated-secret-copy"))
+		})
+
+		It("leaves secrets alone, which are no longer a copy of the quarks secret", func() {
+			quarksCopySecret = nil
+			copySecret.Annotations = map[string]string{}
+
+			_, err := reconciler.Reconcile(context.Background(), request)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(client.DeleteCallCount()).To(Equal(0))
+			Expect(client.UpdateCallCount()).To(Equal(0))
+		})
+	})
+	When("copies select namespaces by labels", func() {
+		var statusWriter *cfakes.FakeStatusWriter
+
+		BeforeEach(func() {
+			quarksSecret.Spec.Copies = []qsv1a1.Copy{{
+				Name:              "generated-secret-copy",
+				NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"tenant": "true"}},
+			}}
+
+			client.ListCalls(func(context context.Context, object crc.ObjectList, _ ...crc.ListOption) error {
+				if list, ok := object.(*qsv1a1.QuarksSecretCopyGrantList); ok {
+					list.Items = grants
+				}
+				list, ok := object.(*corev1.NamespaceList)
+				if !ok {
+					return nil
+				}
+				list.Items = []corev1.Namespace{
+					{ObjectMeta: metav1.ObjectMeta{Name: "allowed", Annotations: map[string]string{qsv1a1.AnnotationAllowCopiesFrom: "other, default"}}},
+					{ObjectMeta: metav1.ObjectMeta{Name: "denied"}},
+					{ObjectMeta: metav1.ObjectMeta{Name: defaultNamespace, Annotations: map[string]string{qsv1a1.AnnotationAllowCopiesFrom: "*"}}},
+				}
+				return nil
+			})
+			statusWriter = &cfakes.FakeStatusWriter{}
+			client.StatusCalls(func() crc.StatusWriter { return statusWriter })
+		})
+
+		It("creates the copies in the namespaces, which allow copies", func() {
+			_, err := reconciler.Reconcile(context.Background(), request)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(client.CreateCallCount()).To(Equal(1))
+			_, object, _ := client.CreateArgsForCall(0)
+			Expect(object.GetNamespace()).To(Equal("allowed"))
+			Expect(object.GetName()).To(Equal("generated-secret-copy"))
+			Expect(object.GetLabels()).To(HaveKeyWithValue(qsv1a1.LabelKind, qsv1a1.CopySecretKind))
+			Expect(object.GetAnnotations()).To(HaveKeyWithValue(qsv1a1.AnnotationCopyOf, defaultNamespace+"/"+quarksSecretName))
+
+			_, object, _ = statusWriter.UpdateArgsForCall(statusWriter.UpdateCallCount() - 1)
+			Expect(object.(*qsv1a1.QuarksSecret).Status.Copies).To(ConsistOf(qsv1a1.Copy{Name: "generated-secret-copy", Namespace: "allowed"}))
+		})
+
+		It("does not overwrite existing secrets, which are not a copy", func() {
+			client.GetCalls(withNamespaces(func(context context.Context, nn types.NamespacedName, object crc.Object) error {
+				switch object := object.(type) {
+				case *qsv1a1.QuarksSecret:
+					quarksSecret.DeepCopyInto(object)
+				case *corev1.Secret:
+					passwordSecret.DeepCopyInto(object)
+				}
+				return nil
+			}))
+
+			_, err := reconciler.Reconcile(context.Background(), request)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(client.CreateCallCount()).To(Equal(0))
+			Expect(client.UpdateCallCount()).To(Equal(0))
+		})
+
+		It("deletes copies in namespaces, which are no longer selected", func() {
+			quarksSecret.Status.Copies = []qsv1a1.Copy{{Name: "generated-secret-copy", Namespace: "removed"}}
+			client.GetCalls(withNamespaces(func(context context.Context, nn types.NamespacedName, object crc.Object) error {
+				switch object := object.(type) {
+				case *qsv1a1.QuarksSecret:
+					if nn.Namespace == defaultNamespace {
+						quarksSecret.DeepCopyInto(object)
+						return nil
+					}
+				case *corev1.Secret:
+					if nn.Name == secretName {
+						passwordSecret.DeepCopyInto(object)
+						return nil
+					}
+					if nn.Namespace == "removed" {
+						object.Name = nn.Name
+						object.Namespace = nn.Namespace
+						object.Labels = map[string]string{qsv1a1.LabelKind: qsv1a1.CopySecretKind}
+						object.Annotations = map[string]string{qsv1a1.AnnotationCopyOf: defaultNamespace + "/" + quarksSecretName}
+						return nil
+					}
+				}
+				return errors.NewNotFound(schema.GroupResource{}, "not found")
+			}))
+
+			_, err := reconciler.Reconcile(context.Background(), request)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(client.DeleteCallCount()).To(Equal(1))
+			_, object, _ := client.DeleteArgsForCall(0)
+			Expect(object.GetNamespace()).To(Equal("removed"))
+
+			_, object, _ = statusWriter.UpdateArgsForCall(statusWriter.UpdateCallCount() - 1)
+			Expect(object.(*qsv1a1.QuarksSecret).Status.Copies).To(ConsistOf(qsv1a1.Copy{Name: "generated-secret-copy", Namespace: "allowed"}))
+		})
+	})
+	When("the copy lists keys", func() {
+		BeforeEach(func() {
+			passwordSecret.Data = map[string][]byte{
+				"ca":          []byte("ca-cert"),
+				"private_key": []byte("ca-key"),
+			}
+			quarksSecret.Spec.Copies[0].Keys = map[string]string{"ca": "ca.crt"}
+
+			client.GetCalls(withNamespaces(func(context context.Context, nn types.NamespacedName, object crc.Object) error {
+				switch object := object.(type) {
+				case *qsv1a1.QuarksSecret:
+					if nn.Namespace == defaultNamespace {
+						quarksSecret.DeepCopyInto(object)
+					} else {
+						quarksCopySecret.DeepCopyInto(object)
+					}
+					return nil
+				case *corev1.Secret:
+					if nn.Name == secretName {
+						passwordSecret.DeepCopyInto(object)
+						return nil
+					}
+				}
+				return errors.NewNotFound(schema.GroupResource{}, "not found")
+			}))
+		})
+
+		It("only copies and renames the listed keys", func() {
+			_, err := reconciler.Reconcile(context.Background(), request)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(client.CreateCallCount()).To(Equal(1))
+			_, object, _ := client.CreateArgsForCall(0)
+			Expect(object.(*corev1.Secret).Data).To(Equal(map[string][]byte{"ca.crt": []byte("ca-cert")}))
+		})
+
+		It("keeps the name of keys without a new name", func() {
+			quarksSecret.Spec.Copies[0].Keys = map[string]string{"ca": ""}
+
+			_, err := reconciler.Reconcile(context.Background(), request)
+			Expect(err).ToNot(HaveOccurred())
+			_, object, _ := client.CreateArgsForCall(0)
+			Expect(object.(*corev1.Secret).Data).To(Equal(map[string][]byte{"ca": []byte("ca-cert")}))
+		})
+
+		It("fails, if a listed key is missing", func() {
+			quarksSecret.Spec.Copies[0].Keys = map[string]string{"certificate": ""}
+
+			_, err := reconciler.Reconcile(context.Background(), request)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("key 'certificate' not found"))
+			Expect(client.CreateCallCount()).To(Equal(0))
+		})
+	})
+	When("copy grants exist in the namespace", func() {
+		var (
+			grant        *qsv1a1.QuarksSecretCopyGrant
+			statusWriter *cfakes.FakeStatusWriter
+		)
+
+		// lastStatus returns the status of the last status update
+		lastStatus := func() qsv1a1.QuarksSecretStatus {
+			_, object, _ := statusWriter.UpdateArgsForCall(statusWriter.UpdateCallCount() - 1)
+			return object.(*qsv1a1.QuarksSecret).Status
+		}
+
+		BeforeEach(func() {
+			grant = &qsv1a1.QuarksSecretCopyGrant{
+				ObjectMeta: metav1.ObjectMeta{Name: "grant", Namespace: defaultNamespace},
+				Spec: qsv1a1.QuarksSecretCopyGrantSpec{
+					QuarksSecretNames: []string{quarksSecretName},
+					Namespaces:        []string{copyNamespace},
+				},
+			}
+
+			client.ListCalls(func(context context.Context, object crc.ObjectList, _ ...crc.ListOption) error {
+				if list, ok := object.(*qsv1a1.QuarksSecretCopyGrantList); ok {
+					list.Items = []qsv1a1.QuarksSecretCopyGrant{*grant}
+				}
+				return nil
+			})
+			client.GetCalls(withNamespaces(func(context context.Context, nn types.NamespacedName, object crc.Object) error {
+				switch object := object.(type) {
+				case *qsv1a1.QuarksSecret:
+					if nn.Namespace == defaultNamespace {
+						quarksSecret.DeepCopyInto(object)
+					} else {
+						quarksCopySecret.DeepCopyInto(object)
+					}
+					return nil
+				case *corev1.Secret:
+					if nn.Name == secretName {
+						passwordSecret.DeepCopyInto(object)
+						return nil
+					}
+				}
+				return errors.NewNotFound(schema.GroupResource{}, "not found")
+			}))
+			statusWriter = &cfakes.FakeStatusWriter{}
+			client.StatusCalls(func() crc.StatusWriter { return statusWriter })
+		})
+
+		It("copies to granted namespaces", func() {
+			_, err := reconciler.Reconcile(context.Background(), request)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(client.CreateCallCount()).To(Equal(1))
+			Expect(lastStatus().DeniedCopies).To(BeEmpty())
+		})
+
+		It("denies copies to namespaces, which are not granted", func() {
+			grant.Spec.Namespaces = []string{"other"}
+
+			_, err := reconciler.Reconcile(context.Background(), request)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(client.CreateCallCount()).To(Equal(0))
+			Expect(lastStatus().DeniedCopies).To(ConsistOf(qsv1a1.Copy{Name: "generated-secret-copy", Namespace: copyNamespace}))
+
+			condition := meta.FindStatusCondition(lastStatus().Conditions, qsv1a1.ConditionCopied)
+			Expect(condition).ToNot(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Reason).To(Equal("CopyDenied"))
+		})
+
+		It("denies copies, if no grant exists in the namespace", func() {
+			client.ListCalls(func(context.Context, crc.ObjectList, ...crc.ListOption) error { return nil })
+
+			_, err := reconciler.Reconcile(context.Background(), request)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(client.CreateCallCount()).To(Equal(0))
+			Expect(lastStatus().DeniedCopies).To(ConsistOf(qsv1a1.Copy{Name: "generated-secret-copy", Namespace: copyNamespace}))
+		})
+
+		It("denies granted copies, if the destination namespace doesn't allow them", func() {
+			namespaces[copyNamespace].Annotations = map[string]string{qsv1a1.AnnotationAllowCopiesFrom: "other"}
+
+			_, err := reconciler.Reconcile(context.Background(), request)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(client.CreateCallCount()).To(Equal(0))
+			Expect(lastStatus().DeniedCopies).To(ConsistOf(qsv1a1.Copy{Name: "generated-secret-copy", Namespace: copyNamespace}))
+		})
+
+		It("does not require a grant for copies in the same namespace", func() {
+			client.ListCalls(func(context.Context, crc.ObjectList, ...crc.ListOption) error { return nil })
+			quarksSecret.Spec.Copies[0].Namespace = defaultNamespace
+
+			_, err := reconciler.Reconcile(context.Background(), request)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(lastStatus().DeniedCopies).To(BeEmpty())
+		})
+
+		Context("when ungranted copies are allowed", func() {
+			BeforeEach(func() {
+				allowUngrantedCopies = true
+			})
+
+			It("copies without a grant and without the consent of the destination namespace", func() {
+				client.ListCalls(func(context.Context, crc.ObjectList, ...crc.ListOption) error { return nil })
+				delete(namespaces, copyNamespace)
+
+				_, err := reconciler.Reconcile(context.Background(), request)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(client.CreateCallCount()).To(Equal(1))
+				Expect(lastStatus().DeniedCopies).To(BeEmpty())
+			})
+
+			It("still checks the grants, once a grant exists", func() {
+				grant.Spec.Namespaces = []string{"other"}
+
+				_, err := reconciler.Reconcile(context.Background(), request)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(client.CreateCallCount()).To(Equal(0))
+				Expect(lastStatus().DeniedCopies).To(HaveLen(1))
+			})
+		})
+
+		It("denies copies of quarks secrets, which are not granted", func() {
+			grant.Spec.QuarksSecretNames = []string{"other"}
+
+			_, err := reconciler.Reconcile(context.Background(), request)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(client.CreateCallCount()).To(Equal(0))
+			Expect(lastStatus().DeniedCopies).To(HaveLen(1))
+		})
+
+		It("prunes copies, which are no longer granted", func() {
+			grant.Spec.Namespaces = []string{"other"}
+			quarksSecret.Status.Copies = []qsv1a1.Copy{{Name: "generated-secret-copy", Namespace: copyNamespace}}
+			client.GetCalls(withNamespaces(func(context context.Context, nn types.NamespacedName, object crc.Object) error {
+				switch object := object.(type) {
+				case *qsv1a1.QuarksSecret:
+					if nn.Namespace == defaultNamespace {
+						quarksSecret.DeepCopyInto(object)
+					} else {
+						quarksCopySecret.DeepCopyInto(object)
+					}
+				case *corev1.Secret:
+					passwordSecret.DeepCopyInto(object)
+					object.Annotations = map[string]string{qsv1a1.AnnotationCopyOf: defaultNamespace + "/" + quarksSecretName}
+				}
+				return nil
+			}))
+
+			_, err := reconciler.Reconcile(context.Background(), request)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(client.DeleteCallCount()).To(Equal(1))
+			Expect(lastStatus().Copies).To(BeEmpty())
+		})
+	})
 })
@@ -0,0 +1,197 @@
+package quarkssecret
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	crc "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	qsv1a1 "code.cloudfoundry.org/quarks-secret/pkg/kube/apis/quarkssecret/v1alpha1"
+	"code.cloudfoundry.org/quarks-utils/pkg/ctxlog"
+)
+
+// selectedCopies resolves the namespace selectors of the copies to the
+// namespaces, which allow copies from the quarks secret's namespace
+func (r *ReconcileCopy) selectedCopies(ctx context.Context, qsec *qsv1a1.QuarksSecret) ([]qsv1a1.Copy, error) {
+	copies := []qsv1a1.Copy{}
+	for _, copy := range qsec.Spec.Copies {
+		if copy.NamespaceSelector == nil {
+			continue
+		}
+
+		selector, err := metav1.LabelSelectorAsSelector(copy.NamespaceSelector)
+		if err != nil {
+			return copies, errors.Wrapf(err, "invalid namespace selector for copy '%s'", copy.Name)
+		}
+
+		namespaces := &corev1.NamespaceList{}
+		err = r.client.List(ctx, namespaces, crc.MatchingLabelsSelector{Selector: selector})
+		if err != nil {
+			return copies, errors.Wrapf(err, "could not list namespaces for copy '%s'", copy.Name)
+		}
+
+		for i := range namespaces.Items {
+			ns := &namespaces.Items[i]
+			if ns.Name == qsec.Namespace || !ns.GetDeletionTimestamp().IsZero() {
+				continue
+			}
+			if !allowsCopiesFrom(ns, qsec.Namespace) {
+				ctxlog.WithEvent(qsec, "CopyReconcile").Infof(ctx, "Skip copy creation: namespace '%s' does not allow copies from '%s'", ns.Name, qsec.Namespace)
+				continue
+			}
+
+			selected := qsv1a1.Copy{Name: copy.Name, Namespace: ns.Name, Keys: copy.Keys}
+			if !containsCopy(copies, selected) {
+				copies = append(copies, selected)
+			}
+		}
+	}
+
+	return copies, nil
+}
+
+// allowsCopiesFrom returns true, if the namespace is annotated to allow
+// copies from the source namespace
+func allowsCopiesFrom(ns *corev1.Namespace, source string) bool {
+	return allowsNamespace(ns.GetAnnotations()[qsv1a1.AnnotationAllowCopiesFrom], source)
+}
+
+// allowsNamespace returns true, if the comma separated allow-list contains
+// the namespace or '*'
+func allowsNamespace(allowList string, namespace string) bool {
+	for _, allowed := range strings.Split(allowList, ",") {
+		allowed = strings.TrimSpace(allowed)
+		if allowed == "*" || allowed == namespace {
+			return true
+		}
+	}
+	return false
+}
+
+// handleSelectedCopies creates or updates the copies in the selected
+// namespaces, unless they are denied. Existing secrets, which are not a copy
+// of the quarks secret, are never overwritten.
+func (r *ReconcileCopy) handleSelectedCopies(ctx context.Context, qsec *qsv1a1.QuarksSecret, copies []qsv1a1.Copy, denied []qsv1a1.Copy) error {
+	if len(copies) == 0 {
+		return nil
+	}
+
+	sourceSecret, err := GetSourceSecret(ctx, r.client, qsec)
+	if err != nil {
+		return err
+	}
+
+	for _, copy := range copies {
+		if containsCopy(denied, copy) {
+			continue
+		}
+
+		data, err := copyData(sourceSecret.Data, copy.Keys)
+		if err != nil {
+			return errors.Wrapf(err, "could not copy to '%s'", copy.String())
+		}
+
+		existing := &corev1.Secret{}
+		err = r.client.Get(ctx, types.NamespacedName{Name: copy.Name, Namespace: copy.Namespace}, existing)
+		if err == nil && existing.GetAnnotations()[qsv1a1.AnnotationCopyOf] != qsec.GetNamespacedName() {
+			ctxlog.WithEvent(qsec, "CopyReconcile").Infof(ctx, "Skip copy creation: Secret '%s' exists and is not a copy", copy.String())
+			continue
+		}
+		if err != nil && !apierrors.IsNotFound(err) {
+			return errors.Wrapf(err, "could not get target secret '%s'", copy.String())
+		}
+
+		targetSecret := &corev1.Secret{}
+		targetSecret.Name = copy.Name
+		targetSecret.Namespace = copy.Namespace
+		op, err := controllerutil.CreateOrUpdate(ctx, r.client, targetSecret, func() error {
+			labels := map[string]string{}
+			for k, v := range sourceSecret.Labels {
+				labels[k] = v
+			}
+			labels[qsv1a1.LabelKind] = qsv1a1.CopySecretKind
+
+			annotations := map[string]string{}
+			for k, v := range sourceSecret.Annotations {
+				annotations[k] = v
+			}
+			annotations[qsv1a1.AnnotationCopyOf] = qsec.GetNamespacedName()
+
+			targetSecret.Labels = labels
+			targetSecret.Annotations = annotations
+			if len(copy.Keys) == 0 {
+				targetSecret.Type = sourceSecret.Type
+			}
+			targetSecret.Data = data
+			return nil
+		})
+		if err != nil {
+			return errors.Wrapf(err, "could not create or update target secret '%s'", copy.String())
+		}
+		if op != controllerutil.OperationResultNone {
+			ctxlog.WithEvent(qsec, "CopyReconcile").Infof(ctx, "Copy secret '%s' has been %s in namespace '%s'", copy.Name, op, copy.Namespace)
+		}
+
+		if !containsCopy(qsec.Status.Copies, copy) {
+			qsec.Status.Copies = append(qsec.Status.Copies, qsv1a1.Copy{Name: copy.Name, Namespace: copy.Namespace})
+		}
+	}
+
+	return nil
+}
+
+// namespaceSelectorReconciles lists the quarks secrets in monitored
+// namespaces, which select namespaces by labels
+func namespaceSelectorReconciles(ctx context.Context, client crc.Client, id string, hasSelector func(*qsv1a1.QuarksSecret) bool) ([]reconcile.Request, error) {
+	quarksSecretList := &qsv1a1.QuarksSecretList{}
+	err := client.List(ctx, quarksSecretList)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list QuarksSecrets")
+	}
+
+	result := []reconcile.Request{}
+	monitored := map[string]bool{}
+	for i := range quarksSecretList.Items {
+		qsec := &quarksSecretList.Items[i]
+		if !hasSelector(qsec) {
+			continue
+		}
+
+		if _, ok := monitored[qsec.Namespace]; !ok {
+			ns := &corev1.Namespace{}
+			if err := client.Get(ctx, types.NamespacedName{Name: qsec.Namespace}, ns); err != nil {
+				return result, errors.Wrapf(err, "failed to get namespace '%s'", qsec.Namespace)
+			}
+			monitored[qsec.Namespace] = qsv1a1.IsMonitoredNamespace(ns, id)
+		}
+		if !monitored[qsec.Namespace] {
+			continue
+		}
+
+		result = append(result, reconcile.Request{
+			NamespacedName: types.NamespacedName{
+				Name:      qsec.Name,
+				Namespace: qsec.Namespace,
+			}})
+	}
+	return result, nil
+}
+
+// hasCopiesToOtherNamespaces returns true, if one of the copies selects
+// namespaces by labels or is copied to another namespace, which has to
+// allow it
+func hasCopiesToOtherNamespaces(qsec *qsv1a1.QuarksSecret) bool {
+	for _, copy := range qsec.Spec.Copies {
+		if copy.NamespaceSelector != nil || copy.Namespace != qsec.Namespace {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,167 @@
+package quarkssecret
+
+import (
+	"context"
+	"crypto"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/cloudflare/cfssl/helpers"
+	"github.com/pkg/errors"
+	certv1 "k8s.io/api/certificates/v1"
+	certv1beta1 "k8s.io/api/certificates/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	qsv1a1 "code.cloudfoundry.org/quarks-secret/pkg/kube/apis/quarkssecret/v1alpha1"
+	"code.cloudfoundry.org/quarks-utils/pkg/names"
+)
+
+// csrDenial is returned by verifyCSR, if the CSR must not be approved
+type csrDenial struct {
+	reason  string
+	message string
+}
+
+func newCSRDenial(reason string, format string, args ...interface{}) *csrDenial {
+	return &csrDenial{reason: reason, message: fmt.Sprintf(format, args...)}
+}
+
+func (d *csrDenial) Error() string {
+	return d.message
+}
+
+// verifyCSR checks, that the CSR is the one the quarks secret reconciler
+// generated for the quarks secret: its public key belongs to the stored
+// private key and it only requests the names, usages and signer of the
+// certificate request. The names also have to match the patterns allowed
+// by the namespace. A *csrDenial is returned, if the CSR must be denied.
+func verifyCSR(ctx context.Context, c client.Client, qsec *qsv1a1.QuarksSecret, csr *certv1.CertificateSigningRequest) error {
+	qsec = qsec.DeepCopy()
+	if err := applyIssuer(ctx, c, qsec); err != nil {
+		return err
+	}
+	request := qsec.Spec.Request.CertificateRequest
+	if qsec.Spec.Type != qsv1a1.Certificate || request.SignerType != qsv1a1.ClusterSigner {
+		return newCSRDenial("UnexpectedQuarksSecret", "quarks secret '%s' doesn't request a certificate from the cluster signer", qsec.GetNamespacedName())
+	}
+
+	signerName := request.SignerName
+	if signerName == "" {
+		signerName = certv1beta1.LegacyUnknownSignerName
+	}
+	if csr.Spec.SignerName != signerName {
+		return newCSRDenial("UnexpectedSigner", "signer '%s' doesn't match signer '%s' of the quarks secret", csr.Spec.SignerName, signerName)
+	}
+
+	block, _ := pem.Decode(csr.Spec.Request)
+	if block == nil {
+		return newCSRDenial("InvalidRequest", "could not decode certificate request PEM")
+	}
+	certReq, err := x509.ParseCertificateRequest(block.Bytes)
+	if err != nil {
+		return newCSRDenial("InvalidRequest", "could not parse certificate request: %s", err)
+	}
+	if err := certReq.CheckSignature(); err != nil {
+		return newCSRDenial("InvalidRequest", "invalid certificate request signature: %s", err)
+	}
+
+	keySecret := &corev1.Secret{}
+	err = c.Get(ctx, types.NamespacedName{Namespace: qsec.Namespace, Name: names.CsrPrivateKeySecretName(csr.Name)}, keySecret)
+	if err != nil {
+		return errors.Wrapf(err, "could not get private key secret of CSR '%s'", csr.Name)
+	}
+	key, err := helpers.ParsePrivateKeyPEM(keySecret.Data["private_key"])
+	if err != nil {
+		return newCSRDenial("PublicKeyMismatch", "could not parse stored private key: %s", err)
+	}
+	if pub, ok := key.Public().(interface{ Equal(crypto.PublicKey) bool }); !ok || !pub.Equal(certReq.PublicKey) {
+		return newCSRDenial("PublicKeyMismatch", "public key doesn't belong to the private key generated for quarks secret '%s'", qsec.GetNamespacedName())
+	}
+
+	serviceNames, _, err := serviceAlternativeNames(ctx, c, qsec)
+	if err != nil {
+		return err
+	}
+	expected := map[string]bool{request.CommonName: true}
+	for _, name := range append(request.AlternativeNames, serviceNames...) {
+		expected[name] = true
+	}
+	requested := requestedNames(certReq)
+	unexpected := []string{}
+	for _, name := range requested {
+		if !expected[name] {
+			unexpected = append(unexpected, name)
+		}
+	}
+	if len(unexpected) > 0 {
+		return newCSRDenial("UnexpectedNames", "names '%s' are not requested by quarks secret '%s'", strings.Join(unexpected, ", "), qsec.GetNamespacedName())
+	}
+
+	usages := request.Usages
+	if len(usages) == 0 {
+		usages = defaultCSRUsages
+	}
+	for _, usage := range csr.Spec.Usages {
+		if !containsUsage(usages, usage) {
+			return newCSRDenial("UnexpectedUsages", "usage '%s' is not requested by quarks secret '%s'", usage, qsec.GetNamespacedName())
+		}
+	}
+
+	ns := &corev1.Namespace{}
+	err = c.Get(ctx, types.NamespacedName{Name: qsec.Namespace}, ns)
+	if err != nil {
+		return errors.Wrapf(err, "could not get namespace '%s'", qsec.Namespace)
+	}
+	if patterns, ok := ns.GetAnnotations()[qsv1a1.AnnotationAllowedCSRNames]; ok {
+		for _, name := range requested {
+			if !matchesAny(patterns, name) {
+				return newCSRDenial("NamesNotAllowed", "name '%s' is not allowed in namespace '%s'", name, qsec.Namespace)
+			}
+		}
+	}
+
+	return nil
+}
+
+// requestedNames returns the common name and the alternative names of the
+// certificate request
+func requestedNames(certReq *x509.CertificateRequest) []string {
+	result := []string{}
+	if certReq.Subject.CommonName != "" {
+		result = append(result, certReq.Subject.CommonName)
+	}
+	result = append(result, certReq.DNSNames...)
+	result = append(result, certReq.EmailAddresses...)
+	for _, ip := range certReq.IPAddresses {
+		result = append(result, ip.String())
+	}
+	for _, uri := range certReq.URIs {
+		result = append(result, uri.String())
+	}
+	return result
+}
+
+func containsUsage(usages []certv1.KeyUsage, usage certv1.KeyUsage) bool {
+	for _, u := range usages {
+		if u == usage {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesAny returns true, if the name matches one of the comma separated
+// glob patterns
+func matchesAny(patterns string, name string) bool {
+	for _, pattern := range strings.Split(patterns, ",") {
+		if ok, _ := path.Match(strings.TrimSpace(pattern), name); ok {
+			return true
+		}
+	}
+	return false
+}
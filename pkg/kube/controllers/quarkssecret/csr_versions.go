@@ -0,0 +1,131 @@
+package quarkssecret
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	certv1 "k8s.io/api/certificates/v1"
+	certv1beta1 "k8s.io/api/certificates/v1beta1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"code.cloudfoundry.org/quarks-utils/pkg/ctxlog"
+)
+
+// The operator works with certificates.k8s.io/v1 CSRs. Clusters before
+// Kubernetes 1.19 only serve v1beta1, the helpers in this file fall back
+// to it and convert the objects.
+
+// csrKind is the kind of certificate signing requests
+const csrKind = "CertificateSigningRequest"
+
+// defaultCSRUsages are the usages v1beta1 used to default to, v1 requires
+// usages to be set
+var defaultCSRUsages = []certv1.KeyUsage{certv1.UsageDigitalSignature, certv1.UsageKeyEncipherment}
+
+// servesCSRv1 returns true if the cluster serves certificates.k8s.io/v1
+func servesCSRv1(mapper meta.RESTMapper) bool {
+	_, err := mapper.RESTMapping(schema.GroupKind{Group: certv1.GroupName, Kind: csrKind}, certv1.SchemeGroupVersion.Version)
+	return err == nil
+}
+
+// convertCSR converts between the v1 and v1beta1 CSR types, which share
+// the same serialization
+func convertCSR(in runtime.Object, out runtime.Object) error {
+	u, err := runtime.DefaultUnstructuredConverter.ToUnstructured(in)
+	if err != nil {
+		return errors.Wrap(err, "could not convert CSR")
+	}
+	delete(u, "apiVersion")
+	delete(u, "kind")
+	return runtime.DefaultUnstructuredConverter.FromUnstructured(u, out)
+}
+
+// getCSR reads the CSR as v1 object
+func getCSR(ctx context.Context, c client.Client, name string, csr *certv1.CertificateSigningRequest) error {
+	err := c.Get(ctx, types.NamespacedName{Name: name}, csr)
+	if !meta.IsNoMatchError(err) {
+		return err
+	}
+
+	legacy := &certv1beta1.CertificateSigningRequest{}
+	err = c.Get(ctx, types.NamespacedName{Name: name}, legacy)
+	if err != nil {
+		return err
+	}
+	return convertCSR(legacy, csr)
+}
+
+// createCSR creates the CSR. A CSR without a signer name is created with
+// v1beta1, which defaults to the legacy-unknown signer. The duration is
+// requested with 'spec.expirationSeconds', which is dropped by clusters
+// that don't support it.
+func createCSR(ctx context.Context, c client.Client, csr *certv1.CertificateSigningRequest, duration *metav1.Duration) error {
+	if len(csr.Spec.Usages) == 0 {
+		csr.Spec.Usages = defaultCSRUsages
+	}
+
+	if csr.Spec.SignerName != "" {
+		obj, err := withExpiration(csr, certv1.SchemeGroupVersion.WithKind(csrKind), duration)
+		if err != nil {
+			return err
+		}
+		err = c.Create(ctx, obj)
+		if !meta.IsNoMatchError(err) {
+			return err
+		}
+		ctxlog.Debugf(ctx, "Cluster doesn't serve certificates.k8s.io/v1, creating CSR '%s' with v1beta1", csr.Name)
+	}
+
+	legacy := &certv1beta1.CertificateSigningRequest{}
+	if err := convertCSR(csr, legacy); err != nil {
+		return err
+	}
+	if csr.Spec.SignerName == "" {
+		legacy.Spec.SignerName = nil
+	}
+	obj, err := withExpiration(legacy, certv1beta1.SchemeGroupVersion.WithKind(csrKind), duration)
+	if err != nil {
+		return err
+	}
+	err = c.Create(ctx, obj)
+	if meta.IsNoMatchError(err) {
+		return errors.Wrap(err, "cluster doesn't serve certificates.k8s.io/v1beta1, CSRs need a signerName")
+	}
+	return err
+}
+
+// withExpiration returns an unstructured copy of the CSR, which requests
+// the duration as expiration
+func withExpiration(csr client.Object, gvk schema.GroupVersionKind, duration *metav1.Duration) (client.Object, error) {
+	if duration == nil {
+		return csr, nil
+	}
+
+	u, err := runtime.DefaultUnstructuredConverter.ToUnstructured(csr)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not convert CSR")
+	}
+	obj := &unstructured.Unstructured{Object: u}
+	obj.SetGroupVersionKind(gvk)
+	err = unstructured.SetNestedField(obj.Object, int64(duration.Seconds()), "spec", "expirationSeconds")
+	if err != nil {
+		return nil, errors.Wrap(err, "could not set CSR expiration")
+	}
+	return obj, nil
+}
+
+// deleteCSR deletes the CSR
+func deleteCSR(ctx context.Context, c client.Client, name string) error {
+	err := c.Delete(ctx, &certv1.CertificateSigningRequest{ObjectMeta: metav1.ObjectMeta{Name: name}})
+	if !meta.IsNoMatchError(err) {
+		return err
+	}
+	return c.Delete(ctx, &certv1beta1.CertificateSigningRequest{ObjectMeta: metav1.ObjectMeta{Name: name}})
+}
@@ -0,0 +1,63 @@
+package quarkssecret
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	qsv1a1 "code.cloudfoundry.org/quarks-secret/pkg/kube/apis/quarkssecret/v1alpha1"
+	"code.cloudfoundry.org/quarks-utils/pkg/config"
+	"code.cloudfoundry.org/quarks-utils/pkg/ctxlog"
+)
+
+// AddDrift creates a new drift controller to watch for generated secrets,
+// which are deleted or modified, and repair them according to the drift
+// policy of their QuarksSecret.
+func AddDrift(ctx context.Context, config *config.Config, mgr manager.Manager) error {
+	ctx = ctxlog.NewContextWithRecorder(ctx, "drift-reconciler", mgr.GetEventRecorderFor("drift-recorder"))
+	r := NewDriftReconciler(ctx, config, mgr, controllerutil.SetControllerReference)
+
+	c, err := controller.New("drift-controller", mgr, controller.Options{
+		Reconciler:              r,
+		MaxConcurrentReconciles: config.MaxQuarksSecretWorkers,
+	})
+	if err != nil {
+		return errors.Wrap(err, "Adding drift controller to manager failed.")
+	}
+
+	nsPred := newNSPredicate(ctx, mgr.GetClient(), config.MonitoredID)
+
+	// Watch for generated secrets being deleted or their data being changed
+	p := predicate.Funcs{
+		CreateFunc:  func(e event.CreateEvent) bool { return false },
+		DeleteFunc:  func(e event.DeleteEvent) bool { return isGeneratedSecret(e.Object) },
+		GenericFunc: func(e event.GenericEvent) bool { return false },
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			n := e.ObjectNew.(*corev1.Secret)
+			o := e.ObjectOld.(*corev1.Secret)
+
+			return isGeneratedSecret(n) && !reflect.DeepEqual(n.Data, o.Data)
+		},
+	}
+	err = c.Watch(&source.Kind{Type: &corev1.Secret{}}, ownerQuarksSecretHandler(ctx), nsPred, p)
+	if err != nil {
+		return errors.Wrapf(err, "Watching generated secrets failed in drift controller.")
+	}
+
+	// Watch for QuarksSecrets, which are resumed with a pending drift repair
+	err = c.Watch(&source.Kind{Type: &qsv1a1.QuarksSecret{}}, &handler.EnqueueRequestForObject{}, nsPred, resumedPredicate(qsv1a1.WorkDriftRepair))
+	if err != nil {
+		return errors.Wrapf(err, "Watching quarks secrets failed in drift controller.")
+	}
+
+	return nil
+}
@@ -0,0 +1,310 @@
+package quarkssecret
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	qsv1a1 "code.cloudfoundry.org/quarks-secret/pkg/kube/apis/quarkssecret/v1alpha1"
+	"code.cloudfoundry.org/quarks-utils/pkg/config"
+	"code.cloudfoundry.org/quarks-utils/pkg/ctxlog"
+	"code.cloudfoundry.org/quarks-utils/pkg/names"
+	"code.cloudfoundry.org/quarks-utils/pkg/pointers"
+)
+
+// NewDriftReconciler returns a new ReconcileDrift
+func NewDriftReconciler(ctx context.Context, config *config.Config, mgr manager.Manager, srf setReferenceFunc) reconcile.Reconciler {
+	return &ReconcileDrift{
+		ctx:          ctx,
+		config:       config,
+		client:       mgr.GetClient(),
+		scheme:       mgr.GetScheme(),
+		setReference: srf,
+	}
+}
+
+// ReconcileDrift repairs generated secrets, which were deleted or modified
+type ReconcileDrift struct {
+	ctx          context.Context
+	client       client.Client
+	scheme       *runtime.Scheme
+	setReference setReferenceFunc
+	config       *config.Config
+}
+
+// Reconcile compares the generated secret of a QuarksSecret with the content
+// hash the operator stored on it. If the secret is missing or was modified,
+// it is regenerated, restored from its backup or only reported, depending on
+// the drift policy.
+func (r *ReconcileDrift) Reconcile(_ context.Context, request reconcile.Request) (reconcile.Result, error) {
+	qsec := &qsv1a1.QuarksSecret{}
+
+	// Set the ctx to be Background, as the top-level context for incoming requests.
+	ctx, cancel := context.WithTimeout(r.ctx, r.config.CtxTimeOut)
+	defer cancel()
+
+	ctxlog.Infof(ctx, "Reconciling drift of QuarksSecret %s", request.NamespacedName)
+	err := r.client.Get(ctx, request.NamespacedName, qsec)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			ctxlog.Info(ctx, "Skip reconcile: quarks secret not found")
+			return reconcile.Result{}, nil
+		}
+		ctxlog.Info(ctx, "Error reading the object")
+		return reconcile.Result{}, errors.Wrap(err, "Error reading quarksSecret")
+	}
+
+	if qsec.DeletionTimestamp != nil || qsec.Spec.Type == qsv1a1.SecretCopy || !isSettled(qsec) {
+		ctxlog.Debugf(ctx, "Skip reconcile: QuarksSecret '%s' is not generated by the operator or generation is in progress", qsec.GetNamespacedName())
+		return reconcile.Result{}, nil
+	}
+
+	if qsec.Spec.Paused {
+		return reconcile.Result{}, deferWork(ctx, r.client, qsec, qsv1a1.WorkDriftRepair)
+	}
+
+	secret := &corev1.Secret{}
+	err = r.client.Get(ctx, types.NamespacedName{Name: qsec.Spec.SecretName, Namespace: qsec.Namespace}, secret)
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			return reconcile.Result{}, errors.Wrapf(err, "could not get secret '%s/%s'", qsec.Namespace, qsec.Spec.SecretName)
+		}
+		ctxlog.WithEvent(qsec, "SecretDeleted").Infof(ctx, "Generated secret '%s/%s' was deleted", qsec.Namespace, qsec.Spec.SecretName)
+		return reconcile.Result{}, r.repair(ctx, qsec, nil, "SecretDeleted", fmt.Sprintf("Secret '%s' was deleted", qsec.Spec.SecretName))
+	}
+
+	if !isGeneratedSecret(secret) {
+		ctxlog.Debugf(ctx, "Skip reconcile: secret '%s/%s' is not generated", secret.Namespace, secret.Name)
+		return reconcile.Result{}, nil
+	}
+
+	expected, ok := secret.Annotations[qsv1a1.AnnotationContentHash]
+	if !ok {
+		ctxlog.Debugf(ctx, "Skip reconcile: secret '%s/%s' has no content hash", secret.Namespace, secret.Name)
+		return reconcile.Result{}, nil
+	}
+
+	hash, err := secretDataChecksum(secret.Data)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	if hash == expected {
+		if qsec.Status.DriftDetected != nil {
+			qsec.Status.DriftDetected = nil
+			err = r.client.Status().Update(ctx, qsec)
+			if err != nil {
+				return reconcile.Result{}, errors.Wrapf(err, "could not update status of QuarksSecret '%s'", qsec.GetNamespacedName())
+			}
+		}
+		return reconcile.Result{}, nil
+	}
+
+	ctxlog.WithEvent(qsec, "SecretDrift").Infof(ctx, "Data of generated secret '%s/%s' was modified", secret.Namespace, secret.Name)
+	return reconcile.Result{}, r.repair(ctx, qsec, secret, "SecretDrift", fmt.Sprintf("Data of secret '%s' was modified", qsec.Spec.SecretName))
+}
+
+// repair applies the drift policy to a deleted or modified secret. A
+// modified secret is never regenerated, as new values would break its
+// consumers.
+func (r *ReconcileDrift) repair(ctx context.Context, qsec *qsv1a1.QuarksSecret, secret *corev1.Secret, reason string, message string) error {
+	policy := qsec.Spec.DriftPolicy
+	if secret != nil && policy != qsv1a1.DriftRestore {
+		policy = qsv1a1.DriftReportOnly
+	}
+
+	switch policy {
+	case qsv1a1.DriftReportOnly:
+		if secret == nil {
+			setCondition(qsec, qsv1a1.ConditionGenerated, metav1.ConditionFalse, reason, message)
+		}
+		if qsec.Status.DriftDetected == nil {
+			now := metav1.Now()
+			qsec.Status.DriftDetected = &now
+		}
+		err := r.client.Status().Update(ctx, qsec)
+		if err != nil {
+			return errors.Wrapf(err, "could not update status of QuarksSecret '%s'", qsec.GetNamespacedName())
+		}
+		return nil
+	case qsv1a1.DriftRestore:
+		restored, err := r.restore(ctx, qsec, secret)
+		if err != nil {
+			return err
+		}
+		if restored {
+			ctxlog.WithEvent(qsec, "SecretRestored").Infof(ctx, "Restored secret '%s/%s' from backup", qsec.Namespace, qsec.Spec.SecretName)
+			return nil
+		}
+		ctxlog.WithEvent(qsec, "BackupNotFound").Infof(ctx, "No valid backup for secret '%s/%s', regenerating it", qsec.Namespace, qsec.Spec.SecretName)
+	}
+
+	// Regenerate by resetting the status, like secret rotation does
+	qsec.Status.Generated = pointers.Bool(false)
+	qsec.Status.InputsHash = ""
+	setCondition(qsec, qsv1a1.ConditionGenerated, metav1.ConditionFalse, reason, message)
+	err := r.client.Status().Update(ctx, qsec)
+	if err != nil {
+		return errors.Wrapf(err, "could not update status of QuarksSecret '%s'", qsec.GetNamespacedName())
+	}
+	ctxlog.WithEvent(qsec, "Regenerate").Infof(ctx, "QuarksSecret '%s' will be regenerated", qsec.GetNamespacedName())
+	return nil
+}
+
+// restore writes the data from the backup to the generated secret. It
+// returns false, if there is no valid backup.
+func (r *ReconcileDrift) restore(ctx context.Context, qsec *qsv1a1.QuarksSecret, secret *corev1.Secret) (bool, error) {
+	backup := &corev1.Secret{}
+	err := r.client.Get(ctx, types.NamespacedName{Name: backupSecretName(qsec.Spec.SecretName), Namespace: qsec.Namespace}, backup)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, errors.Wrapf(err, "could not get backup of secret '%s/%s'", qsec.Namespace, qsec.Spec.SecretName)
+	}
+
+	if !isBackupOf(backup, qsec) {
+		ctxlog.Infof(ctx, "Secret '%s/%s' is not a backup of QuarksSecret '%s'", backup.Namespace, backup.Name, qsec.GetNamespacedName())
+		return false, nil
+	}
+
+	hash, err := secretDataChecksum(backup.Data)
+	if err != nil {
+		return false, err
+	}
+	if hash != backup.Annotations[qsv1a1.AnnotationContentHash] {
+		return false, nil
+	}
+
+	if secret == nil {
+		secret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        qsec.Spec.SecretName,
+				Namespace:   qsec.Namespace,
+				Labels:      map[string]string{},
+				Annotations: map[string]string{},
+			},
+			Type: backup.Type,
+		}
+		for k, v := range qsec.Spec.SecretLabels {
+			secret.Labels[k] = v
+		}
+		for k, v := range qsec.Spec.SecretAnnotations {
+			secret.Annotations[k] = v
+		}
+		secret.Labels[qsv1a1.LabelKind] = qsv1a1.GeneratedSecretKind
+		if err := r.setReference(qsec, secret, r.scheme); err != nil {
+			return false, errors.Wrapf(err, "error setting owner for secret '%s' to QuarksSecret '%s'", secret.GetName(), qsec.GetNamespacedName())
+		}
+	}
+	if secret.Annotations == nil {
+		secret.Annotations = map[string]string{}
+	}
+	secret.Annotations[qsv1a1.AnnotationContentHash] = hash
+	secret.Data = backup.Data
+
+	if len(secret.ResourceVersion) == 0 {
+		err = r.client.Create(ctx, secret)
+	} else {
+		err = r.client.Update(ctx, secret)
+	}
+	if err != nil {
+		return false, errors.Wrapf(err, "could not restore secret '%s/%s'", secret.Namespace, secret.Name)
+	}
+	return true, nil
+}
+
+// isSettled returns true, if the operator generated the secret of the
+// QuarksSecret and is not about to generate it again
+func isSettled(qsec *qsv1a1.QuarksSecret) bool {
+	c := meta.FindStatusCondition(qsec.Status.Conditions, qsv1a1.ConditionGenerated)
+	if c == nil {
+		return qsec.Status.IsGenerated()
+	}
+	return c.Status == metav1.ConditionTrue && c.Reason != reasonUserProvided
+}
+
+// backupSecretName returns the name of the secret, which holds the backup
+// of a generated secret
+func backupSecretName(secretName string) string {
+	return names.TruncateMD5(secretName+"-backup", 253)
+}
+
+// secretData returns the data of the secret, including the string data,
+// which is merged into the data on write
+func secretData(secret *corev1.Secret) map[string][]byte {
+	data := map[string][]byte{}
+	for k, v := range secret.Data {
+		data[k] = v
+	}
+	for k, v := range secret.StringData {
+		data[k] = []byte(v)
+	}
+	return data
+}
+
+// setContentHash stores the checksum of the data on the secret, so changes
+// can be detected later on
+func setContentHash(secret *corev1.Secret) error {
+	hash, err := secretDataChecksum(secretData(secret))
+	if err != nil {
+		return err
+	}
+
+	annotations := map[string]string{}
+	for k, v := range secret.Annotations {
+		annotations[k] = v
+	}
+	annotations[qsv1a1.AnnotationContentHash] = hash
+	secret.Annotations = annotations
+	return nil
+}
+
+// storeBackup keeps a copy of the generated secret, for the restore drift
+// policy. An existing secret, which is not a backup of the quarks secret, is
+// never overwritten.
+func storeBackup(ctx context.Context, c client.Client, scheme *runtime.Scheme, setReference setReferenceFunc, qsec *qsv1a1.QuarksSecret, secret *corev1.Secret) error {
+	backup := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      backupSecretName(secret.Name),
+			Namespace: secret.Namespace,
+		},
+	}
+
+	data := secretData(secret)
+	_, err := controllerutil.CreateOrUpdate(ctx, c, backup, func() error {
+		if backup.ResourceVersion != "" && !isBackupOf(backup, qsec) {
+			return errors.Errorf("secret '%s/%s' exists and is not a backup of QuarksSecret '%s'", backup.Namespace, backup.Name, qsec.GetNamespacedName())
+		}
+		if err := setReference(qsec, backup, scheme); err != nil {
+			return errors.Wrapf(err, "error setting owner for secret '%s' to QuarksSecret '%s'", backup.Name, qsec.GetNamespacedName())
+		}
+
+		backup.Labels = map[string]string{qsv1a1.LabelKind: qsv1a1.BackupSecretKind}
+		backup.Annotations = map[string]string{qsv1a1.AnnotationContentHash: secret.Annotations[qsv1a1.AnnotationContentHash]}
+		backup.Type = secret.Type
+		backup.Data = data
+		return nil
+	})
+	if err != nil {
+		return errors.Wrapf(err, "could not store backup of secret '%s/%s'", secret.Namespace, secret.Name)
+	}
+	return nil
+}
+
+// isBackupOf returns true, if the secret is a backup, which is controlled by
+// the quarks secret
+func isBackupOf(backup *corev1.Secret, qsec *qsv1a1.QuarksSecret) bool {
+	return backup.GetLabels()[qsv1a1.LabelKind] == qsv1a1.BackupSecretKind && metav1.IsControlledBy(backup, qsec)
+}
@@ -0,0 +1,277 @@
+package quarkssecret_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"go.uber.org/zap"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	crc "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	qsv1a1 "code.cloudfoundry.org/quarks-secret/pkg/kube/apis/quarkssecret/v1alpha1"
+	"code.cloudfoundry.org/quarks-secret/pkg/kube/client/clientset/versioned/scheme"
+	"code.cloudfoundry.org/quarks-secret/pkg/kube/controllers"
+	cfakes "code.cloudfoundry.org/quarks-secret/pkg/kube/controllers/fakes"
+	qscontroller "code.cloudfoundry.org/quarks-secret/pkg/kube/controllers/quarkssecret"
+	cfcfg "code.cloudfoundry.org/quarks-utils/pkg/config"
+	"code.cloudfoundry.org/quarks-utils/pkg/ctxlog"
+	"code.cloudfoundry.org/quarks-utils/pkg/pointers"
+	helper "code.cloudfoundry.org/quarks-utils/testing/testhelper"
+)
+
+var _ = Describe("ReconcileDrift", func() {
+	var (
+		manager      *cfakes.FakeManager
+		reconciler   reconcile.Reconciler
+		request      reconcile.Request
+		ctx          context.Context
+		log          *zap.SugaredLogger
+		config       *cfcfg.Config
+		client       *cfakes.FakeClient
+		statusWriter *cfakes.FakeStatusWriter
+		qSecret      *qsv1a1.QuarksSecret
+		secret       *corev1.Secret
+		backup       *corev1.Secret
+		generated    = map[string][]byte{"password": []byte("securepassword")}
+
+		setReferenceFunc = func(owner, object metav1.Object, scheme *runtime.Scheme) error { return nil }
+	)
+
+	checksum := func(data map[string][]byte) string {
+		b, err := json.Marshal(data)
+		Expect(err).ToNot(HaveOccurred())
+		sum := sha256.Sum256(b)
+		return hex.EncodeToString(sum[:])
+	}
+
+	// updatedStatus returns the status of the last status update
+	updatedStatus := func() qsv1a1.QuarksSecretStatus {
+		Expect(statusWriter.UpdateCallCount()).To(Equal(1))
+		_, object, _ := statusWriter.UpdateArgsForCall(0)
+		return object.(*qsv1a1.QuarksSecret).Status
+	}
+
+	BeforeEach(func() {
+		err := controllers.AddToScheme(scheme.Scheme)
+		Expect(err).ToNot(HaveOccurred())
+		manager = &cfakes.FakeManager{}
+		request = reconcile.Request{NamespacedName: types.NamespacedName{Name: "foo", Namespace: "default"}}
+		config = &cfcfg.Config{CtxTimeOut: 10 * time.Second}
+		_, log = helper.NewTestLogger()
+		ctx = ctxlog.NewContextWithRecorder(ctxlog.NewParentContext(log), "TestRecorder", record.NewFakeRecorder(10))
+
+		qSecret = &qsv1a1.QuarksSecret{
+			ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "default", UID: "foo-uid"},
+			Spec: qsv1a1.QuarksSecretSpec{
+				Type:       "password",
+				SecretName: "generated-secret",
+			},
+			Status: qsv1a1.QuarksSecretStatus{
+				Generated: pointers.Bool(true),
+				Conditions: []metav1.Condition{
+					{Type: qsv1a1.ConditionGenerated, Status: metav1.ConditionTrue, Reason: "Generated"},
+				},
+			},
+		}
+		secret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            "generated-secret",
+				Namespace:       "default",
+				ResourceVersion: "1",
+				Labels:          map[string]string{qsv1a1.LabelKind: qsv1a1.GeneratedSecretKind},
+				Annotations:     map[string]string{qsv1a1.AnnotationContentHash: checksum(generated)},
+			},
+			Data: map[string][]byte{"password": []byte("securepassword")},
+		}
+		backup = nil
+
+		client = &cfakes.FakeClient{}
+		client.GetCalls(func(context context.Context, nn types.NamespacedName, object crc.Object) error {
+			switch object := object.(type) {
+			case *qsv1a1.QuarksSecret:
+				qSecret.DeepCopyInto(object)
+				return nil
+			case *corev1.Secret:
+				if nn.Name == "generated-secret" && secret != nil {
+					secret.DeepCopyInto(object)
+					return nil
+				}
+				if nn.Name == "generated-secret-backup" && backup != nil {
+					backup.DeepCopyInto(object)
+					return nil
+				}
+			}
+			return errors.NewNotFound(schema.GroupResource{}, "not found")
+		})
+		statusWriter = &cfakes.FakeStatusWriter{}
+		client.StatusCalls(func() crc.StatusWriter { return statusWriter })
+		manager.GetClientReturns(client)
+	})
+
+	JustBeforeEach(func() {
+		reconciler = qscontroller.NewDriftReconciler(ctx, config, manager, setReferenceFunc)
+	})
+
+	It("does nothing if the secret is unchanged", func() {
+		_, err := reconciler.Reconcile(context.Background(), request)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(statusWriter.UpdateCallCount()).To(Equal(0))
+		Expect(client.UpdateCallCount()).To(Equal(0))
+	})
+
+	It("skips quarks secrets, which are being generated", func() {
+		secret = nil
+		meta.SetStatusCondition(&qSecret.Status.Conditions, metav1.Condition{Type: qsv1a1.ConditionGenerated, Status: metav1.ConditionFalse, Reason: "Rotating"})
+
+		_, err := reconciler.Reconcile(context.Background(), request)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(statusWriter.UpdateCallCount()).To(Equal(0))
+	})
+
+	It("defers the repair of a paused quarks secret", func() {
+		secret = nil
+		qSecret.Spec.Paused = true
+
+		_, err := reconciler.Reconcile(context.Background(), request)
+		Expect(err).ToNot(HaveOccurred())
+
+		status := updatedStatus()
+		Expect(status.IsGenerated()).To(BeTrue())
+		Expect(status.PendingWork).To(ConsistOf(qsv1a1.WorkDriftRepair))
+	})
+
+	It("regenerates a deleted secret by default", func() {
+		secret = nil
+
+		_, err := reconciler.Reconcile(context.Background(), request)
+		Expect(err).ToNot(HaveOccurred())
+
+		status := updatedStatus()
+		Expect(status.NotGenerated()).To(BeTrue())
+		Expect(meta.FindStatusCondition(status.Conditions, qsv1a1.ConditionGenerated).Reason).To(Equal("SecretDeleted"))
+	})
+
+	It("doesn't regenerate an edited secret by default", func() {
+		secret.Data["password"] = []byte("tampered")
+
+		_, err := reconciler.Reconcile(context.Background(), request)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(client.UpdateCallCount()).To(Equal(0))
+
+		status := updatedStatus()
+		Expect(status.IsGenerated()).To(BeTrue())
+		Expect(status.DriftDetected).ToNot(BeNil())
+	})
+
+	It("doesn't regenerate an edited secret, if the drift policy is regenerate", func() {
+		qSecret.Spec.DriftPolicy = qsv1a1.DriftRegenerate
+		secret.Data["password"] = []byte("tampered")
+
+		_, err := reconciler.Reconcile(context.Background(), request)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(updatedStatus().IsGenerated()).To(BeTrue())
+	})
+
+	Context("when the drift policy is report-only", func() {
+		BeforeEach(func() {
+			qSecret.Spec.DriftPolicy = qsv1a1.DriftReportOnly
+		})
+
+		It("only reports a modified secret", func() {
+			secret.Data["password"] = []byte("tampered")
+
+			_, err := reconciler.Reconcile(context.Background(), request)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(client.UpdateCallCount()).To(Equal(0))
+
+			status := updatedStatus()
+			Expect(status.IsGenerated()).To(BeTrue())
+			Expect(status.DriftDetected).ToNot(BeNil())
+		})
+
+		It("clears the drift once the secret matches again", func() {
+			now := metav1.Now()
+			qSecret.Status.DriftDetected = &now
+
+			_, err := reconciler.Reconcile(context.Background(), request)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(updatedStatus().DriftDetected).To(BeNil())
+		})
+	})
+
+	Context("when the drift policy is restore", func() {
+		BeforeEach(func() {
+			qSecret.Spec.DriftPolicy = qsv1a1.DriftRestore
+			backup = &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "generated-secret-backup",
+					Namespace:   "default",
+					Labels:      map[string]string{qsv1a1.LabelKind: qsv1a1.BackupSecretKind},
+					Annotations: map[string]string{qsv1a1.AnnotationContentHash: checksum(generated)},
+					OwnerReferences: []metav1.OwnerReference{
+						{Kind: "QuarksSecret", Name: "foo", UID: "foo-uid", Controller: pointers.Bool(true)},
+					},
+				},
+				Data: generated,
+			}
+		})
+
+		It("restores a modified secret from the backup", func() {
+			secret.Data["password"] = []byte("tampered")
+
+			_, err := reconciler.Reconcile(context.Background(), request)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(statusWriter.UpdateCallCount()).To(Equal(0))
+			Expect(client.UpdateCallCount()).To(Equal(1))
+			_, object, _ := client.UpdateArgsForCall(0)
+			Expect(object.(*corev1.Secret).Data).To(Equal(generated))
+		})
+
+		It("restores a deleted secret from the backup", func() {
+			secret = nil
+
+			_, err := reconciler.Reconcile(context.Background(), request)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(client.CreateCallCount()).To(Equal(1))
+			_, object, _ := client.CreateArgsForCall(0)
+			restored := object.(*corev1.Secret)
+			Expect(restored.Name).To(Equal("generated-secret"))
+			Expect(restored.Data).To(Equal(generated))
+			Expect(restored.Labels).To(HaveKeyWithValue(qsv1a1.LabelKind, qsv1a1.GeneratedSecretKind))
+		})
+
+		It("regenerates the secret, if the backup is not controlled by the quarks secret", func() {
+			backup.OwnerReferences = nil
+			secret.Data["password"] = []byte("tampered")
+
+			_, err := reconciler.Reconcile(context.Background(), request)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(client.UpdateCallCount()).To(Equal(0))
+			Expect(updatedStatus().NotGenerated()).To(BeTrue())
+		})
+
+		It("regenerates the secret, if the backup is missing", func() {
+			backup = nil
+			secret = nil
+
+			_, err := reconciler.Reconcile(context.Background(), request)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(client.CreateCallCount()).To(Equal(0))
+			Expect(updatedStatus().NotGenerated()).To(BeTrue())
+		})
+	})
+})
@@ -3,21 +3,30 @@ package quarkssecret
 import (
 	"context"
 	"encoding/base64"
+	"encoding/json"
 	"fmt"
 
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
-	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 
 	"code.cloudfoundry.org/quarks-secret/pkg/credsgen"
 	qsv1a1 "code.cloudfoundry.org/quarks-secret/pkg/kube/apis/quarkssecret/v1alpha1"
+	"code.cloudfoundry.org/quarks-utils/pkg/ctxlog"
 )
 
 func (r *ReconcileQuarksSecret) createPasswordSecret(ctx context.Context, qsec *qsv1a1.QuarksSecret) error {
-	request := credsgen.PasswordGenerationRequest{}
-	password := r.generator.GeneratePassword(qsec.GetName(), request)
+	values, err := providedValues(ctx, r.client, qsec)
+	if err != nil {
+		return err
+	}
+
+	password, ok := values["password"]
+	if !ok {
+		request := credsgen.PasswordGenerationRequest{}
+		password = r.generator.GeneratePassword(qsec.GetName(), request)
+	}
 
 	secret := &corev1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
@@ -30,12 +39,18 @@ func (r *ReconcileQuarksSecret) createPasswordSecret(ctx context.Context, qsec *
 			"password": password,
 		},
 	}
+	mergeProvidedValues(secret, values)
 
 	return r.createSecrets(ctx, qsec, secret)
 }
 
 func (r *ReconcileQuarksSecret) createRSASecret(ctx context.Context, qsec *qsv1a1.QuarksSecret) error {
-	key, err := r.generator.GenerateRSAKey(qsec.GetName())
+	values, err := providedValues(ctx, r.client, qsec)
+	if err != nil {
+		return err
+	}
+
+	key, err := r.rsaKey(qsec, values)
 	if err != nil {
 		return err
 	}
@@ -52,12 +67,41 @@ func (r *ReconcileQuarksSecret) createRSASecret(ctx context.Context, qsec *qsv1a
 			"public_key":  string(key.PublicKey),
 		},
 	}
+	mergeProvidedValues(secret, values)
 
-	return r.createSecrets(ctx, qsec, secret)
+	err = r.createSecrets(ctx, qsec, secret)
+	if err != nil {
+		return err
+	}
+
+	if !isUserProvided(qsec) {
+		qsec.Status.KeyFingerprint, err = rsaKeyFingerprint([]byte(secret.StringData["public_key"]))
+		if err != nil {
+			ctxlog.Errorf(ctx, "Failed to fingerprint public key '%s/%s': %v", qsec.Namespace, qsec.Spec.SecretName, err)
+		}
+	}
+	return nil
+}
+
+// rsaKey generates a RSA key, unless the private key is provided
+func (r *ReconcileQuarksSecret) rsaKey(qsec *qsv1a1.QuarksSecret, values map[string]string) (credsgen.RSAKey, error) {
+	private, ok := values["private_key"]
+	if !ok {
+		if _, ok := values["public_key"]; ok {
+			return credsgen.RSAKey{}, errors.New("'public_key' can only be provided together with 'private_key'")
+		}
+		return r.generator.GenerateRSAKey(qsec.GetName())
+	}
+	return rsaKeyFromPrivateKey([]byte(private))
 }
 
 func (r *ReconcileQuarksSecret) createSSHSecret(ctx context.Context, qsec *qsv1a1.QuarksSecret) error {
-	key, err := r.generator.GenerateSSHKey(qsec.GetName())
+	values, err := providedValues(ctx, r.client, qsec)
+	if err != nil {
+		return err
+	}
+
+	key, err := r.sshKey(qsec, values)
 	if err != nil {
 		return err
 	}
@@ -75,16 +119,51 @@ func (r *ReconcileQuarksSecret) createSSHSecret(ctx context.Context, qsec *qsv1a
 			"public_key_fingerprint": key.Fingerprint,
 		},
 	}
+	mergeProvidedValues(secret, values)
 
-	return r.createSecrets(ctx, qsec, secret)
+	err = r.createSecrets(ctx, qsec, secret)
+	if err != nil {
+		return err
+	}
+
+	if !isUserProvided(qsec) {
+		qsec.Status.KeyFingerprint, err = sshKeyFingerprint([]byte(secret.StringData["public_key"]))
+		if err != nil {
+			ctxlog.Errorf(ctx, "Failed to fingerprint public key '%s/%s': %v", qsec.Namespace, qsec.Spec.SecretName, err)
+		}
+	}
+	return nil
+}
+
+// sshKey generates a SSH key, unless the private key is provided
+func (r *ReconcileQuarksSecret) sshKey(qsec *qsv1a1.QuarksSecret, values map[string]string) (credsgen.SSHKey, error) {
+	private, ok := values["private_key"]
+	if !ok {
+		if _, ok := values["public_key"]; ok {
+			return credsgen.SSHKey{}, errors.New("'public_key' can only be provided together with 'private_key'")
+		}
+		return r.generator.GenerateSSHKey(qsec.GetName())
+	}
+	return sshKeyFromPrivateKey([]byte(private))
 }
 
 func (r *ReconcileQuarksSecret) createBasicAuthSecret(ctx context.Context, qsec *qsv1a1.QuarksSecret) error {
-	username := qsec.Spec.Request.BasicAuthRequest.Username
+	values, err := providedValues(ctx, r.client, qsec)
+	if err != nil {
+		return err
+	}
+
+	username, ok := values["username"]
+	if !ok {
+		username = qsec.Spec.Request.BasicAuthRequest.Username
+	}
 	if username == "" {
 		username = r.generator.GeneratePassword(fmt.Sprintf("%s/username", qsec.Name), credsgen.PasswordGenerationRequest{})
 	}
-	password := r.generator.GeneratePassword(fmt.Sprintf("%s/password", qsec.Name), credsgen.PasswordGenerationRequest{})
+	password, ok := values["password"]
+	if !ok {
+		password = r.generator.GeneratePassword(fmt.Sprintf("%s/password", qsec.Name), credsgen.PasswordGenerationRequest{})
+	}
 
 	secret := &corev1.Secret{
 		Type: corev1.SecretTypeBasicAuth,
@@ -97,55 +176,88 @@ func (r *ReconcileQuarksSecret) createBasicAuthSecret(ctx context.Context, qsec
 			"password": password,
 		},
 	}
+	mergeProvidedValues(secret, values)
 
 	return r.createSecrets(ctx, qsec, secret)
 }
 
-func (r *ReconcileQuarksSecret) createDockerConfigJSON(ctx context.Context, qsec *qsv1a1.QuarksSecret) error {
+// dockerConfigAuth is a registry entry in a dockerconfigjson secret
+type dockerConfigAuth struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// dockerConfigInputs returns the referenced username and password, the
+// provided values and the hash over both and the request
+func (r *ReconcileQuarksSecret) dockerConfigInputs(ctx context.Context, qsec *qsv1a1.QuarksSecret) (map[string]string, map[string]string, string, error) {
+	request := qsec.Spec.Request.ImageCredentialsRequest
+
 	// Fetch username and password.
-	username := ""
-	if len(qsec.Spec.Request.ImageCredentialsRequest.Username.Name) > 0 {
-		userSecret := &corev1.Secret{}
-		userNamespacedName := types.NamespacedName{
-			Namespace: qsec.Namespace,
-			Name:      qsec.Spec.Request.ImageCredentialsRequest.Username.Name,
+	values := map[string]string{}
+	for name, ref := range map[string]qsv1a1.SecretReference{"username": request.Username, "password": request.Password} {
+		if len(ref.Name) == 0 {
+			continue
 		}
-		err := r.client.Get(ctx, userNamespacedName, userSecret)
+		value, err := getSecretReferenceValue(ctx, r.client, qsec.Namespace, ref)
 		if err != nil {
-			if apierrors.IsNotFound(err) {
-				return newSecNotReadyError("username secret not found")
-			}
-			return errors.Wrap(err, "getting username secret")
+			return nil, nil, "", errors.Wrapf(err, "getting %s", name)
 		}
-		data, ok := userSecret.Data[qsec.Spec.Request.ImageCredentialsRequest.Username.Key]
-		if !ok {
-			return errors.Errorf("Failed to get username data by key: %s", qsec.Spec.Request.ImageCredentialsRequest.Username.Key)
+		values[name] = value
+	}
+
+	provided, err := providedValues(ctx, r.client, qsec)
+	if err != nil {
+		return nil, nil, "", err
+	}
+
+	hash, err := inputsHash(request, hashedValues(values, provided))
+	if err != nil {
+		return nil, nil, "", err
+	}
+	return values, provided, hash, nil
+}
+
+// createDockerConfigJSON renders the image credentials into the secret.
+// Returns false if rendering was skipped, because the inputs did not change.
+func (r *ReconcileQuarksSecret) createDockerConfigJSON(ctx context.Context, qsec *qsv1a1.QuarksSecret) (bool, error) {
+	request := qsec.Spec.Request.ImageCredentialsRequest
+	values, provided, hash, err := r.dockerConfigInputs(ctx, qsec)
+	if err != nil {
+		return false, err
+	}
+	if !inputsChanged(qsec, hash) {
+		return false, nil
+	}
+
+	// Re-rendering because of changed references must not rotate the
+	// generated parts of the credentials
+	existing := dockerConfigAuth{}
+	if qsec.Status.IsGenerated() {
+		existing = r.existingDockerConfigAuth(ctx, qsec)
+	}
+
+	// provided credentials are only written to the registry entry
+	extra := map[string]string{}
+	for key, value := range provided {
+		switch key {
+		case "username", "password":
+			values[key] = value
+		default:
+			extra[key] = value
 		}
-		username = string(data)
+	}
+
+	username := values["username"]
+	if username == "" {
+		username = existing.Username
 	}
 	if username == "" {
 		username = r.generator.GeneratePassword(fmt.Sprintf("%s/username", qsec.Name), credsgen.PasswordGenerationRequest{})
 	}
 
-	password := ""
-	if len(qsec.Spec.Request.ImageCredentialsRequest.Password.Name) > 0 {
-		passSecret := &corev1.Secret{}
-		passNamespacedName := types.NamespacedName{
-			Namespace: qsec.Namespace,
-			Name:      qsec.Spec.Request.ImageCredentialsRequest.Password.Name,
-		}
-		err := r.client.Get(ctx, passNamespacedName, passSecret)
-		if err != nil {
-			if apierrors.IsNotFound(err) {
-				return newSecNotReadyError("password secret not found")
-			}
-			return errors.Wrap(err, "getting password secret")
-		}
-		data, ok := passSecret.Data[qsec.Spec.Request.ImageCredentialsRequest.Password.Key]
-		if !ok {
-			return errors.Errorf("Failed to get password data key: %s", qsec.Spec.Request.ImageCredentialsRequest.Password.Key)
-		}
-		password = string(data)
+	password := values["password"]
+	if password == "" {
+		password = existing.Password
 	}
 	if password == "" {
 		password = r.generator.GeneratePassword(fmt.Sprintf("%s/password", qsec.Name), credsgen.PasswordGenerationRequest{})
@@ -153,10 +265,10 @@ func (r *ReconcileQuarksSecret) createDockerConfigJSON(ctx context.Context, qsec
 
 	authEncode := base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf("%s:%s", username, password)))
 	dockerConfigJSONData := fmt.Sprintf("{\"auths\":{\"%s\":{\"username\":\"%s\",\"password\":\"%s\",\"email\":\"%s\",\"auth\":\"%s\"}}}",
-		qsec.Spec.Request.ImageCredentialsRequest.Registry,
+		request.Registry,
 		username,
 		password,
-		qsec.Spec.Request.ImageCredentialsRequest.Email,
+		request.Email,
 		authEncode,
 	)
 
@@ -170,6 +282,31 @@ func (r *ReconcileQuarksSecret) createDockerConfigJSON(ctx context.Context, qsec
 			corev1.DockerConfigJsonKey: dockerConfigJSONData,
 		},
 	}
+	mergeProvidedValues(secret, extra)
 
-	return r.createSecrets(ctx, qsec, secret)
+	if err := r.createSecrets(ctx, qsec, secret); err != nil {
+		return false, err
+	}
+
+	qsec.Status.InputsHash = hash
+	return true, nil
+}
+
+// existingDockerConfigAuth returns the credentials for the requested registry
+// from the previously generated secret, if there is one
+func (r *ReconcileQuarksSecret) existingDockerConfigAuth(ctx context.Context, qsec *qsv1a1.QuarksSecret) dockerConfigAuth {
+	secret := &corev1.Secret{}
+	err := r.client.Get(ctx, types.NamespacedName{Namespace: qsec.Namespace, Name: qsec.Spec.SecretName}, secret)
+	if err != nil {
+		return dockerConfigAuth{}
+	}
+
+	config := struct {
+		Auths map[string]dockerConfigAuth `json:"auths"`
+	}{}
+	if err := json.Unmarshal(secret.Data[corev1.DockerConfigJsonKey], &config); err != nil {
+		ctxlog.Debugf(ctx, "Ignoring invalid docker config in secret '%s/%s': %s", secret.Namespace, secret.Name, err)
+		return dockerConfigAuth{}
+	}
+	return config.Auths[qsec.Spec.Request.ImageCredentialsRequest.Registry]
 }
@@ -0,0 +1,101 @@
+package quarkssecret
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	crc "sigs.k8s.io/controller-runtime/pkg/client"
+
+	qsv1a1 "code.cloudfoundry.org/quarks-secret/pkg/kube/apis/quarkssecret/v1alpha1"
+)
+
+// issuerRefIndex is the name of the field index, which maps the issuer
+// referenced by a QuarksSecret's certificate request to the QuarksSecret
+const issuerRefIndex = "spec.request.certificate.issuerRef"
+
+// issuerIndexKey returns the index key of an issuer, which is its kind and
+// name
+func issuerIndexKey(kind string, name string) string {
+	return kind + "/" + name
+}
+
+// indexIssuerRef is the indexer func for issuerRefIndex
+func indexIssuerRef(o crc.Object) []string {
+	qsec, ok := o.(*qsv1a1.QuarksSecret)
+	if !ok {
+		return []string{}
+	}
+	ref := qsec.Spec.Request.CertificateRequest.IssuerRef
+	if ref == nil || !isCertificate(qsec) {
+		return []string{}
+	}
+	return []string{issuerIndexKey(ref.IssuerKind(), ref.Name)}
+}
+
+// isCertificate returns true, if the quarks secret generates a certificate
+func isCertificate(qsec *qsv1a1.QuarksSecret) bool {
+	return qsec.Spec.Type == qsv1a1.Certificate || qsec.Spec.Type == qsv1a1.TLS
+}
+
+// getIssuerSpec returns the spec of the issuer referenced from the namespace
+func getIssuerSpec(ctx context.Context, client crc.Client, namespace string, ref qsv1a1.IssuerReference) (qsv1a1.QuarksIssuerSpec, error) {
+	var err error
+	switch ref.IssuerKind() {
+	case qsv1a1.QuarksIssuerKind:
+		issuer := &qsv1a1.QuarksIssuer{}
+		err = client.Get(ctx, types.NamespacedName{Namespace: namespace, Name: ref.Name}, issuer)
+		if err == nil {
+			return issuer.Spec, nil
+		}
+	case qsv1a1.ClusterQuarksIssuerKind:
+		issuer := &qsv1a1.ClusterQuarksIssuer{}
+		err = client.Get(ctx, types.NamespacedName{Name: ref.Name}, issuer)
+		if err == nil {
+			if issuer.Spec.CARef.Name != "" || issuer.Spec.CAKeyRef.Name != "" {
+				return qsv1a1.QuarksIssuerSpec{}, errors.Errorf("cluster issuer '%s' can't use CARef, use clusterCARef instead", ref.Name)
+			}
+			return issuer.Spec, nil
+		}
+	default:
+		return qsv1a1.QuarksIssuerSpec{}, errors.Errorf("unrecognized issuer kind: %s", ref.Kind)
+	}
+
+	if apierrors.IsNotFound(err) {
+		return qsv1a1.QuarksIssuerSpec{}, newCaNotReadyError(fmt.Sprintf("%s '%s' not found", ref.IssuerKind(), ref.Name))
+	}
+	return qsv1a1.QuarksIssuerSpec{}, errors.Wrapf(err, "getting %s '%s'", ref.IssuerKind(), ref.Name)
+}
+
+// applyIssuer configures the certificate request of the quarks secret with
+// the signer of the referenced issuer. The duration and usages of the
+// issuer are defaults, which the certificate request can override.
+func applyIssuer(ctx context.Context, c crc.Client, qsec *qsv1a1.QuarksSecret) error {
+	request := &qsec.Spec.Request.CertificateRequest
+	if request.IssuerRef == nil {
+		return nil
+	}
+	if request.CARef.Name != "" || request.CAKeyRef.Name != "" || request.ClusterCARef != "" || request.SignerType != "" || request.SignerName != "" {
+		return errors.New("issuerRef can't be combined with CARef, CAKeyRef, clusterCARef, signerType or signerName")
+	}
+
+	spec, err := getIssuerSpec(ctx, c, qsec.Namespace, *request.IssuerRef)
+	if err != nil {
+		return err
+	}
+
+	request.SignerType = spec.SignerType
+	request.SignerName = spec.SignerName
+	request.CARef = spec.CARef
+	request.CAKeyRef = spec.CAKeyRef
+	request.ClusterCARef = spec.ClusterCARef
+	if len(request.Usages) == 0 {
+		request.Usages = spec.Usages
+	}
+	if request.Duration == nil {
+		request.Duration = spec.Duration
+	}
+	return nil
+}
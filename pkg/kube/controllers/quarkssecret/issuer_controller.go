@@ -0,0 +1,64 @@
+package quarkssecret
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	qsv1a1 "code.cloudfoundry.org/quarks-secret/pkg/kube/apis/quarkssecret/v1alpha1"
+	"code.cloudfoundry.org/quarks-utils/pkg/config"
+	"code.cloudfoundry.org/quarks-utils/pkg/ctxlog"
+)
+
+// AddIssuer creates a new issuer controller, which reissues the
+// certificates of QuarksIssuers and ClusterQuarksIssuers, when their spec
+// changes.
+func AddIssuer(ctx context.Context, config *config.Config, mgr manager.Manager) error {
+	ctx = ctxlog.NewContextWithRecorder(ctx, "issuer-reconciler", mgr.GetEventRecorderFor("issuer-recorder"))
+	r := NewIssuerReconciler(ctx, config, mgr)
+
+	c, err := controller.New("issuer-controller", mgr, controller.Options{
+		Reconciler:              r,
+		MaxConcurrentReconciles: config.MaxQuarksSecretWorkers,
+	})
+	if err != nil {
+		return errors.Wrap(err, "Adding issuer controller to manager failed.")
+	}
+
+	// Index QuarksSecrets by their issuer, so its certificates can be
+	// reissued
+	err = mgr.GetFieldIndexer().IndexField(ctx, &qsv1a1.QuarksSecret{}, issuerRefIndex, indexIssuerRef)
+	if err != nil {
+		return errors.Wrapf(err, "Indexing issuer references failed in issuer controller.")
+	}
+
+	nsPred := newNSPredicate(ctx, mgr.GetClient(), config.MonitoredID)
+
+	// Watch for issuers, which are created or whose spec changes, i.e. the
+	// generation is incremented
+	p := predicate.Funcs{
+		CreateFunc:  func(e event.CreateEvent) bool { return true },
+		DeleteFunc:  func(e event.DeleteEvent) bool { return false },
+		GenericFunc: func(e event.GenericEvent) bool { return false },
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			return e.ObjectNew.GetGeneration() != e.ObjectOld.GetGeneration()
+		},
+	}
+	err = c.Watch(&source.Kind{Type: &qsv1a1.QuarksIssuer{}}, &handler.EnqueueRequestForObject{}, nsPred, p)
+	if err != nil {
+		return errors.Wrapf(err, "Watching quarks issuers failed in issuer controller.")
+	}
+
+	err = c.Watch(&source.Kind{Type: &qsv1a1.ClusterQuarksIssuer{}}, &handler.EnqueueRequestForObject{}, p)
+	if err != nil {
+		return errors.Wrapf(err, "Watching cluster quarks issuers failed in issuer controller.")
+	}
+
+	return nil
+}
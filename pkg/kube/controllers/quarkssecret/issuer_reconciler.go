@@ -0,0 +1,131 @@
+package quarkssecret
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	qsv1a1 "code.cloudfoundry.org/quarks-secret/pkg/kube/apis/quarkssecret/v1alpha1"
+	"code.cloudfoundry.org/quarks-utils/pkg/config"
+	"code.cloudfoundry.org/quarks-utils/pkg/ctxlog"
+)
+
+// NewIssuerReconciler returns a new ReconcileIssuer
+func NewIssuerReconciler(ctx context.Context, config *config.Config, mgr manager.Manager) reconcile.Reconciler {
+	return &ReconcileIssuer{
+		ctx:    ctx,
+		config: config,
+		client: mgr.GetClient(),
+	}
+}
+
+// ReconcileIssuer reissues the certificates of QuarksIssuers and
+// ClusterQuarksIssuers, whose spec changed
+type ReconcileIssuer struct {
+	ctx    context.Context
+	client client.Client
+	config *config.Config
+}
+
+// Reconcile rotates all quarks secrets, which reference the issuer, once its
+// spec changed since the status was observed. Requests without a namespace
+// are for ClusterQuarksIssuers.
+func (r *ReconcileIssuer) Reconcile(_ context.Context, request reconcile.Request) (reconcile.Result, error) {
+	// Set the ctx to be Background, as the top-level context for incoming requests.
+	ctx, cancel := context.WithTimeout(r.ctx, r.config.CtxTimeOut)
+	defer cancel()
+
+	var (
+		issuer client.Object
+		status *qsv1a1.QuarksIssuerStatus
+		kind   string
+	)
+	if request.Namespace == "" {
+		i := &qsv1a1.ClusterQuarksIssuer{}
+		issuer, status, kind = i, &i.Status, qsv1a1.ClusterQuarksIssuerKind
+	} else {
+		i := &qsv1a1.QuarksIssuer{}
+		issuer, status, kind = i, &i.Status, qsv1a1.QuarksIssuerKind
+	}
+
+	ctxlog.Infof(ctx, "Reconciling %s %s", kind, request.NamespacedName)
+	err := r.client.Get(ctx, request.NamespacedName, issuer)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			ctxlog.Info(ctx, "Skip reconcile: issuer not found")
+			return reconcile.Result{}, nil
+		}
+		ctxlog.Info(ctx, "Error reading the object")
+		return reconcile.Result{}, errors.Wrapf(err, "Error reading %s", kind)
+	}
+
+	if status.ObservedGeneration == issuer.GetGeneration() {
+		return reconcile.Result{}, nil
+	}
+
+	// A new issuer has not issued certificates with another spec before
+	if status.ObservedGeneration != 0 {
+		names, err := r.listIssued(ctx, request.Namespace, issuerIndexKey(kind, request.Name))
+		if err != nil {
+			return reconcile.Result{}, ctxlog.WithEvent(issuer, "IssuerFailed").Errorf(ctx, "Failed to list certificates of %s '%s': %s", kind, request.NamespacedName, err)
+		}
+		// a retry would reissue the certificates again, which were already
+		// reissued, so failures are only reported
+		for _, name := range names {
+			if _, err := rotateQuarksSecret(ctx, r.client, issuer, name, false); err != nil {
+				_ = ctxlog.WithEvent(issuer, "IssuerFailed").Errorf(ctx, "Failed to reissue certificate '%s' of %s '%s': %s", name, kind, request.NamespacedName, err)
+			}
+		}
+	}
+
+	status.ObservedGeneration = issuer.GetGeneration()
+	if err := r.client.Status().Update(ctx, issuer); err != nil {
+		return reconcile.Result{}, errors.Wrapf(err, "could not update status of %s '%s'", kind, request.NamespacedName)
+	}
+
+	return reconcile.Result{}, nil
+}
+
+// listIssued returns the names of the quarks secrets, which reference the
+// issuer. An empty namespace lists all monitored namespaces.
+func (r *ReconcileIssuer) listIssued(ctx context.Context, namespace string, key string) ([]types.NamespacedName, error) {
+	list := &qsv1a1.QuarksSecretList{}
+	opts := []client.ListOption{client.MatchingFields{issuerRefIndex: key}}
+	if namespace != "" {
+		opts = append(opts, client.InNamespace(namespace))
+	}
+	if err := r.client.List(ctx, list, opts...); err != nil {
+		return nil, err
+	}
+
+	names := []types.NamespacedName{}
+	monitored := map[string]bool{}
+	for i := range list.Items {
+		qsec := &list.Items[i]
+		if namespace == "" {
+			if _, ok := monitored[qsec.Namespace]; !ok {
+				ns := &corev1.Namespace{}
+				if err := r.client.Get(ctx, types.NamespacedName{Name: qsec.Namespace}, ns); err != nil {
+					return nil, errors.Wrapf(err, "failed to get namespace '%s'", qsec.Namespace)
+				}
+				monitored[qsec.Namespace] = qsv1a1.IsMonitoredNamespace(ns, r.config.MonitoredID)
+			}
+			if !monitored[qsec.Namespace] {
+				continue
+			}
+		}
+
+		for _, k := range indexIssuerRef(qsec) {
+			if k == key {
+				names = append(names, types.NamespacedName{Namespace: qsec.Namespace, Name: qsec.Name})
+			}
+		}
+	}
+	return names, nil
+}
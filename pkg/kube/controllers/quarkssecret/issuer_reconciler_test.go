@@ -0,0 +1,194 @@
+package quarkssecret_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"go.uber.org/zap"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	crc "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	qsv1a1 "code.cloudfoundry.org/quarks-secret/pkg/kube/apis/quarkssecret/v1alpha1"
+	"code.cloudfoundry.org/quarks-secret/pkg/kube/client/clientset/versioned/scheme"
+	"code.cloudfoundry.org/quarks-secret/pkg/kube/controllers"
+	cfakes "code.cloudfoundry.org/quarks-secret/pkg/kube/controllers/fakes"
+	qscontroller "code.cloudfoundry.org/quarks-secret/pkg/kube/controllers/quarkssecret"
+	cfcfg "code.cloudfoundry.org/quarks-utils/pkg/config"
+	"code.cloudfoundry.org/quarks-utils/pkg/ctxlog"
+	"code.cloudfoundry.org/quarks-utils/pkg/pointers"
+	helper "code.cloudfoundry.org/quarks-utils/testing/testhelper"
+)
+
+var _ = Describe("ReconcileIssuer", func() {
+	var (
+		manager       *cfakes.FakeManager
+		reconciler    reconcile.Reconciler
+		request       reconcile.Request
+		ctx           context.Context
+		log           *zap.SugaredLogger
+		config        *cfcfg.Config
+		client        *cfakes.FakeClient
+		statusWriter  *cfakes.FakeStatusWriter
+		issuer        *qsv1a1.QuarksIssuer
+		clusterIssuer *qsv1a1.ClusterQuarksIssuer
+		quarksSecrets []qsv1a1.QuarksSecret
+	)
+
+	certificate := func(namespace string, name string, ref *qsv1a1.IssuerReference) qsv1a1.QuarksSecret {
+		qsec := qsv1a1.QuarksSecret{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			Spec:       qsv1a1.QuarksSecretSpec{Type: qsv1a1.Certificate, SecretName: name},
+			Status:     qsv1a1.QuarksSecretStatus{Generated: pointers.Bool(true)},
+		}
+		qsec.Spec.Request.CertificateRequest.IssuerRef = ref
+		return qsec
+	}
+
+	// rotated returns the quarks secrets, whose generated status was reset
+	rotated := func() []string {
+		result := []string{}
+		for i := 0; i < statusWriter.UpdateCallCount(); i++ {
+			_, object, _ := statusWriter.UpdateArgsForCall(i)
+			if qsec, ok := object.(*qsv1a1.QuarksSecret); ok && qsec.Status.NotGenerated() {
+				result = append(result, qsec.GetNamespacedName())
+			}
+		}
+		return result
+	}
+
+	BeforeEach(func() {
+		err := controllers.AddToScheme(scheme.Scheme)
+		Expect(err).ToNot(HaveOccurred())
+		manager = &cfakes.FakeManager{}
+		request = reconcile.Request{NamespacedName: types.NamespacedName{Name: "internal", Namespace: "default"}}
+		config = &cfcfg.Config{CtxTimeOut: 10 * time.Second, MonitoredID: "quarks"}
+		_, log = helper.NewTestLogger()
+		ctx = ctxlog.NewContextWithRecorder(ctxlog.NewParentContext(log), "TestRecorder", record.NewFakeRecorder(10))
+
+		issuer = &qsv1a1.QuarksIssuer{
+			ObjectMeta: metav1.ObjectMeta{Name: "internal", Namespace: "default", Generation: 2},
+			Status:     qsv1a1.QuarksIssuerStatus{ObservedGeneration: 1},
+		}
+		clusterIssuer = &qsv1a1.ClusterQuarksIssuer{
+			ObjectMeta: metav1.ObjectMeta{Name: "internal", Generation: 2},
+			Status:     qsv1a1.QuarksIssuerStatus{ObservedGeneration: 1},
+		}
+		quarksSecrets = []qsv1a1.QuarksSecret{
+			certificate("default", "leaf", &qsv1a1.IssuerReference{Name: "internal"}),
+			certificate("default", "other", &qsv1a1.IssuerReference{Name: "other"}),
+			certificate("default", "cluster-leaf", &qsv1a1.IssuerReference{Name: "internal", Kind: qsv1a1.ClusterQuarksIssuerKind}),
+			certificate("default", "no-issuer", nil),
+		}
+
+		client = &cfakes.FakeClient{}
+		client.GetCalls(func(context context.Context, nn types.NamespacedName, object crc.Object) error {
+			switch object := object.(type) {
+			case *qsv1a1.QuarksIssuer:
+				issuer.DeepCopyInto(object)
+				return nil
+			case *qsv1a1.ClusterQuarksIssuer:
+				clusterIssuer.DeepCopyInto(object)
+				return nil
+			case *corev1.Namespace:
+				if nn.Name != "unmonitored" {
+					object.Labels = map[string]string{qsv1a1.LabelNamespace: "quarks"}
+				}
+				return nil
+			case *qsv1a1.QuarksSecret:
+				for i := range quarksSecrets {
+					if quarksSecrets[i].Name == nn.Name && quarksSecrets[i].Namespace == nn.Namespace {
+						quarksSecrets[i].DeepCopyInto(object)
+						return nil
+					}
+				}
+			}
+			return apierrors.NewNotFound(schema.GroupResource{}, nn.Name)
+		})
+		client.ListCalls(func(context context.Context, object crc.ObjectList, _ ...crc.ListOption) error {
+			if list, ok := object.(*qsv1a1.QuarksSecretList); ok {
+				list.Items = quarksSecrets
+			}
+			return nil
+		})
+		statusWriter = &cfakes.FakeStatusWriter{}
+		client.StatusCalls(func() crc.StatusWriter { return statusWriter })
+		manager.GetClientReturns(client)
+	})
+
+	JustBeforeEach(func() {
+		reconciler = qscontroller.NewIssuerReconciler(ctx, config, manager)
+	})
+
+	It("reissues the certificates of an issuer, whose spec changed", func() {
+		_, err := reconciler.Reconcile(context.Background(), request)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rotated()).To(Equal([]string{"default/leaf"}))
+
+		_, object, _ := statusWriter.UpdateArgsForCall(statusWriter.UpdateCallCount() - 1)
+		Expect(object.(*qsv1a1.QuarksIssuer).Status.ObservedGeneration).To(Equal(int64(2)))
+	})
+
+	It("continues and records the generation, if reissuing a certificate fails", func() {
+		quarksSecrets = append(quarksSecrets, certificate("default", "second-leaf", &qsv1a1.IssuerReference{Name: "internal"}))
+		statusWriter.UpdateCalls(func(context context.Context, object crc.Object, _ ...crc.UpdateOption) error {
+			if object.GetName() == "leaf" {
+				return apierrors.NewConflict(schema.GroupResource{}, "leaf", nil)
+			}
+			return nil
+		})
+
+		_, err := reconciler.Reconcile(context.Background(), request)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rotated()).To(ContainElement("default/second-leaf"))
+
+		_, object, _ := statusWriter.UpdateArgsForCall(statusWriter.UpdateCallCount() - 1)
+		Expect(object.(*qsv1a1.QuarksIssuer).Status.ObservedGeneration).To(Equal(int64(2)))
+	})
+
+	It("reissues the certificates of a cluster issuer", func() {
+		request = reconcile.Request{NamespacedName: types.NamespacedName{Name: "internal"}}
+
+		_, err := reconciler.Reconcile(context.Background(), request)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rotated()).To(Equal([]string{"default/cluster-leaf"}))
+
+		_, object, _ := statusWriter.UpdateArgsForCall(statusWriter.UpdateCallCount() - 1)
+		Expect(object.(*qsv1a1.ClusterQuarksIssuer).Status.ObservedGeneration).To(Equal(int64(2)))
+	})
+
+	It("doesn't reissue certificates of a cluster issuer in namespaces, which are not monitored", func() {
+		request = reconcile.Request{NamespacedName: types.NamespacedName{Name: "internal"}}
+		quarksSecrets = append(quarksSecrets, certificate("unmonitored", "cluster-leaf", &qsv1a1.IssuerReference{Name: "internal", Kind: qsv1a1.ClusterQuarksIssuerKind}))
+
+		_, err := reconciler.Reconcile(context.Background(), request)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rotated()).To(Equal([]string{"default/cluster-leaf"}))
+	})
+
+	It("only records the generation of a new issuer", func() {
+		issuer.Status.ObservedGeneration = 0
+
+		_, err := reconciler.Reconcile(context.Background(), request)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rotated()).To(BeEmpty())
+		Expect(statusWriter.UpdateCallCount()).To(Equal(1))
+	})
+
+	It("does nothing, if the spec didn't change", func() {
+		issuer.Status.ObservedGeneration = 2
+
+		_, err := reconciler.Reconcile(context.Background(), request)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(client.ListCallCount()).To(Equal(0))
+		Expect(statusWriter.UpdateCallCount()).To(Equal(0))
+	})
+})
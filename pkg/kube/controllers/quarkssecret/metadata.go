@@ -0,0 +1,90 @@
+package quarkssecret
+
+import (
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/ssh"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	qsv1a1 "code.cloudfoundry.org/quarks-secret/pkg/kube/apis/quarkssecret/v1alpha1"
+)
+
+// certificateStatus returns the metadata of the first certificate in the PEM data
+func certificateStatus(data []byte) (*qsv1a1.CertificateStatus, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("failed to decode certificate PEM")
+	}
+
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse certificate")
+	}
+
+	sans := append([]string{}, cert.DNSNames...)
+	for _, ip := range cert.IPAddresses {
+		sans = append(sans, ip.String())
+	}
+	for _, uri := range cert.URIs {
+		sans = append(sans, uri.String())
+	}
+	sans = append(sans, cert.EmailAddresses...)
+
+	notBefore := metav1.NewTime(cert.NotBefore)
+	notAfter := metav1.NewTime(cert.NotAfter)
+	return &qsv1a1.CertificateStatus{
+		SerialNumber:     cert.SerialNumber.Text(16),
+		Issuer:           cert.Issuer.String(),
+		Subject:          cert.Subject.String(),
+		NotBefore:        &notBefore,
+		NotAfter:         &notAfter,
+		AlternativeNames: sans,
+		Fingerprint:      fingerprintSHA256(cert.Raw),
+	}, nil
+}
+
+// fingerprintSHA256 returns the SHA256 checksum as colon separated hex bytes,
+// which is how openssl prints certificate fingerprints
+func fingerprintSHA256(data []byte) string {
+	sum := sha256.Sum256(data)
+	hex := make([]string, len(sum))
+	for i, b := range sum {
+		hex[i] = fmt.Sprintf("%02X", b)
+	}
+	return strings.Join(hex, ":")
+}
+
+// rsaKeyFingerprint returns the SHA256 fingerprint of a PEM encoded public
+// key, as printed by ssh-keygen
+func rsaKeyFingerprint(data []byte) (string, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return "", errors.New("failed to decode public key PEM")
+	}
+
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to parse public key")
+	}
+
+	public, err := ssh.NewPublicKey(key)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to convert public key")
+	}
+	return ssh.FingerprintSHA256(public), nil
+}
+
+// sshKeyFingerprint returns the SHA256 fingerprint of a public key in the
+// authorized keys format
+func sshKeyFingerprint(data []byte) (string, error) {
+	public, _, _, _, err := ssh.ParseAuthorizedKey(data)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to parse public key")
+	}
+	return ssh.FingerprintSHA256(public), nil
+}
@@ -0,0 +1,77 @@
+package quarkssecret
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	qsv1a1 "code.cloudfoundry.org/quarks-secret/pkg/kube/apis/quarkssecret/v1alpha1"
+	"code.cloudfoundry.org/quarks-utils/pkg/ctxlog"
+)
+
+// deferWork records the work a controller skipped, because the quarks secret
+// is paused. The controller applies it, once the quarks secret is resumed.
+// Several controllers defer their work at the same time, so the status update
+// can conflict. The error is returned to requeue, otherwise the work is lost.
+func deferWork(ctx context.Context, c client.Client, qsec *qsv1a1.QuarksSecret, work qsv1a1.Work) error {
+	ctxlog.Infof(ctx, "Skip reconcile: quarks secret '%s' is paused, deferring %s", qsec.GetNamespacedName(), work)
+
+	status := qsec.Status.DeepCopy()
+	addPendingWork(qsec, work)
+	if reflect.DeepEqual(*status, qsec.Status) {
+		return nil
+	}
+
+	if err := c.Status().Update(ctx, qsec); err != nil {
+		return errors.Wrapf(err, "could not defer %s for paused QuarksSecret '%s'", work, qsec.GetNamespacedName())
+	}
+	return nil
+}
+
+// addPendingWork adds the work to the pending work of a paused quarks secret
+func addPendingWork(qsec *qsv1a1.QuarksSecret, work qsv1a1.Work) {
+	if !hasPendingWork(qsec, work) {
+		qsec.Status.PendingWork = append(qsec.Status.PendingWork, work)
+	}
+	setPausedCondition(qsec)
+}
+
+// setPausedCondition marks the quarks secret as paused
+func setPausedCondition(qsec *qsv1a1.QuarksSecret) {
+	setCondition(qsec, qsv1a1.ConditionPaused, metav1.ConditionTrue, "Paused", "Reconciliation is paused")
+}
+
+// hasPendingWork returns true, if the work was deferred while the quarks
+// secret was paused
+func hasPendingWork(qsec *qsv1a1.QuarksSecret, work qsv1a1.Work) bool {
+	for _, w := range qsec.Status.PendingWork {
+		if w == work {
+			return true
+		}
+	}
+	return false
+}
+
+// resumedPredicate passes updates, which resume a quarks secret with pending
+// work for the controller
+func resumedPredicate(work qsv1a1.Work) predicate.Funcs {
+	return predicate.Funcs{
+		CreateFunc:  func(e event.CreateEvent) bool { return false },
+		DeleteFunc:  func(e event.DeleteEvent) bool { return false },
+		GenericFunc: func(e event.GenericEvent) bool { return false },
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			return isResumed(e.ObjectOld.(*qsv1a1.QuarksSecret), e.ObjectNew.(*qsv1a1.QuarksSecret), work)
+		},
+	}
+}
+
+// isResumed returns true, if the update resumed the quarks secret and the
+// work was deferred while it was paused
+func isResumed(old, new *qsv1a1.QuarksSecret, work qsv1a1.Work) bool {
+	return old.Spec.Paused && !new.Spec.Paused && hasPendingWork(new, work)
+}
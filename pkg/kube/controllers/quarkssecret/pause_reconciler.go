@@ -0,0 +1,81 @@
+package quarkssecret
+
+import (
+	"context"
+	"reflect"
+	"strings"
+
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	qsv1a1 "code.cloudfoundry.org/quarks-secret/pkg/kube/apis/quarkssecret/v1alpha1"
+	"code.cloudfoundry.org/quarks-utils/pkg/config"
+	"code.cloudfoundry.org/quarks-utils/pkg/ctxlog"
+)
+
+// NewPauseReconciler returns a new ReconcilePause
+func NewPauseReconciler(ctx context.Context, config *config.Config, mgr manager.Manager) reconcile.Reconciler {
+	return &ReconcilePause{
+		ctx:    ctx,
+		config: config,
+		client: mgr.GetClient(),
+	}
+}
+
+// ReconcilePause reflects pausing and resuming a QuarksSecret in its status
+type ReconcilePause struct {
+	ctx    context.Context
+	client client.Client
+	config *config.Config
+}
+
+// Reconcile sets the Paused condition of a paused QuarksSecret. Once it is
+// resumed, the condition and the pending work are removed. The controllers,
+// which deferred the work, apply it when they see the QuarksSecret resume.
+func (r *ReconcilePause) Reconcile(_ context.Context, request reconcile.Request) (reconcile.Result, error) {
+	qsec := &qsv1a1.QuarksSecret{}
+
+	// Set the ctx to be Background, as the top-level context for incoming requests.
+	ctx, cancel := context.WithTimeout(r.ctx, r.config.CtxTimeOut)
+	defer cancel()
+
+	ctxlog.Infof(ctx, "Reconciling pause of QuarksSecret %s", request.NamespacedName)
+	err := r.client.Get(ctx, request.NamespacedName, qsec)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			ctxlog.Info(ctx, "Skip reconcile: quarks secret not found")
+			return reconcile.Result{}, nil
+		}
+		ctxlog.Info(ctx, "Error reading the object")
+		return reconcile.Result{}, errors.Wrap(err, "Error reading quarksSecret")
+	}
+
+	status := qsec.Status.DeepCopy()
+	if qsec.Spec.Paused {
+		if !meta.IsStatusConditionTrue(qsec.Status.Conditions, qsv1a1.ConditionPaused) {
+			ctxlog.WithEvent(qsec, "Paused").Infof(ctx, "Paused reconciliation of QuarksSecret '%s'", qsec.GetNamespacedName())
+		}
+		setPausedCondition(qsec)
+	} else {
+		if meta.FindStatusCondition(qsec.Status.Conditions, qsv1a1.ConditionPaused) == nil && len(qsec.Status.PendingWork) == 0 {
+			return reconcile.Result{}, nil
+		}
+		ctxlog.WithEvent(qsec, "Resumed").Infof(ctx, "Resumed reconciliation of QuarksSecret '%s', pending work: [%s]", qsec.GetNamespacedName(), strings.Join(qsec.Status.PendingWork, ", "))
+		qsec.Status.PendingWork = nil
+		removeCondition(qsec, qsv1a1.ConditionPaused)
+	}
+
+	if reflect.DeepEqual(*status, qsec.Status) {
+		return reconcile.Result{}, nil
+	}
+
+	err = r.client.Status().Update(ctx, qsec)
+	if err != nil {
+		return reconcile.Result{}, errors.Wrapf(err, "could not update status of QuarksSecret '%s'", qsec.GetNamespacedName())
+	}
+	return reconcile.Result{}, nil
+}
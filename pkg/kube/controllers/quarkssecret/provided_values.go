@@ -0,0 +1,134 @@
+package quarkssecret
+
+import (
+	"context"
+	"crypto"
+	"crypto/x509"
+	"encoding/pem"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/ssh"
+	corev1 "k8s.io/api/core/v1"
+	crc "sigs.k8s.io/controller-runtime/pkg/client"
+
+	"code.cloudfoundry.org/quarks-secret/pkg/credsgen"
+	qsv1a1 "code.cloudfoundry.org/quarks-secret/pkg/kube/apis/quarkssecret/v1alpha1"
+)
+
+// providedValues reads the values of the keys, which are provided by
+// referenced secrets instead of being generated
+func providedValues(ctx context.Context, client crc.Client, qsec *qsv1a1.QuarksSecret) (map[string]string, error) {
+	values := map[string]string{}
+	for key, ref := range qsec.Spec.ProvidedValues {
+		value, err := getSecretReferenceValue(ctx, client, qsec.Namespace, ref)
+		if err != nil {
+			return map[string]string{}, errors.Wrapf(err, "getting provided value for '%s'", key)
+		}
+		values[key] = value
+	}
+	return values, nil
+}
+
+// hashedValues adds the provided values to the values, which are used to
+// calculate the inputs hash of a rendered secret
+func hashedValues(values map[string]string, provided map[string]string) map[string]string {
+	if len(provided) == 0 {
+		return values
+	}
+	result := make(map[string]string, len(values)+len(provided))
+	for key, value := range values {
+		result[key] = value
+	}
+	for key, value := range provided {
+		result["providedValues/"+key] = value
+	}
+	return result
+}
+
+// mergeProvidedValues overwrites the generated data of the secret with the
+// provided values
+func mergeProvidedValues(secret *corev1.Secret, values map[string]string) {
+	if len(values) == 0 {
+		return
+	}
+	if secret.StringData == nil {
+		secret.StringData = map[string]string{}
+	}
+	for key, value := range values {
+		delete(secret.Data, key)
+		secret.StringData[key] = value
+	}
+}
+
+// providedPair returns the values of both keys, if they are provided. It's
+// an error to only provide one of them, since they have to match.
+func providedPair(values map[string]string, first, second string) (string, string, bool, error) {
+	a, hasFirst := values[first]
+	b, hasSecond := values[second]
+	if hasFirst != hasSecond {
+		return "", "", false, errors.Errorf("'%s' and '%s' have to be provided together", first, second)
+	}
+	return a, b, hasFirst, nil
+}
+
+// validateCertificateChain checks that the PEM encoded certificate is signed
+// by one of the PEM encoded CA certificates
+func validateCertificateChain(cert, ca []byte) error {
+	roots := x509.NewCertPool()
+	if !roots.AppendCertsFromPEM(ca) {
+		return errors.New("invalid 'ca'")
+	}
+
+	block, _ := pem.Decode(cert)
+	if block == nil {
+		return errors.New("failed to decode certificate PEM")
+	}
+	leaf, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return errors.Wrap(err, "invalid certificate")
+	}
+
+	_, err = leaf.Verify(x509.VerifyOptions{Roots: roots, KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageAny}})
+	if err != nil {
+		return errors.Wrap(err, "certificate is not signed by the provided 'ca'")
+	}
+	return nil
+}
+
+// rsaKeyFromPrivateKey returns the key pair for a provided PEM encoded private
+// key, the public key is encoded like the generated ones
+func rsaKeyFromPrivateKey(private []byte) (credsgen.RSAKey, error) {
+	key, err := ssh.ParseRawPrivateKey(private)
+	if err != nil {
+		return credsgen.RSAKey{}, errors.Wrap(err, "invalid private key")
+	}
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return credsgen.RSAKey{}, errors.New("invalid private key")
+	}
+
+	public, err := x509.MarshalPKIXPublicKey(signer.Public())
+	if err != nil {
+		return credsgen.RSAKey{}, errors.Wrap(err, "marshalling public key")
+	}
+
+	return credsgen.RSAKey{
+		PrivateKey: private,
+		PublicKey:  pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: public}),
+	}, nil
+}
+
+// sshKeyFromPrivateKey returns the key pair for a provided PEM encoded
+// private key, the public key is in the authorized keys format
+func sshKeyFromPrivateKey(private []byte) (credsgen.SSHKey, error) {
+	signer, err := ssh.ParsePrivateKey(private)
+	if err != nil {
+		return credsgen.SSHKey{}, errors.Wrap(err, "invalid private key")
+	}
+
+	return credsgen.SSHKey{
+		PrivateKey:  private,
+		PublicKey:   ssh.MarshalAuthorizedKey(signer.PublicKey()),
+		Fingerprint: ssh.FingerprintLegacyMD5(signer.PublicKey()),
+	}, nil
+}
@@ -0,0 +1,108 @@
+package quarkssecret
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	qsv1a1 "code.cloudfoundry.org/quarks-secret/pkg/kube/apis/quarkssecret/v1alpha1"
+	"code.cloudfoundry.org/quarks-utils/pkg/config"
+	"code.cloudfoundry.org/quarks-utils/pkg/ctxlog"
+)
+
+// AddPublicOutput creates a new public output controller, which publishes
+// the public parts of generated secrets in config maps.
+func AddPublicOutput(ctx context.Context, config *config.Config, mgr manager.Manager) error {
+	ctx = ctxlog.NewContextWithRecorder(ctx, "public-output-reconciler", mgr.GetEventRecorderFor("public-output-recorder"))
+	r := NewPublicOutputReconciler(ctx, config, mgr, controllerutil.SetControllerReference)
+
+	c, err := controller.New("public-output-controller", mgr, controller.Options{
+		Reconciler:              r,
+		MaxConcurrentReconciles: config.MaxQuarksSecretWorkers,
+	})
+	if err != nil {
+		return errors.Wrap(err, "Adding public output controller to manager failed.")
+	}
+
+	nsPred := newNSPredicate(ctx, mgr.GetClient(), config.MonitoredID)
+
+	// Watch for QuarksSecrets, whose public output is added, changed or
+	// removed
+	p := predicate.Funcs{
+		CreateFunc: func(e event.CreateEvent) bool {
+			return e.Object.(*qsv1a1.QuarksSecret).Spec.PublicOutput != nil
+		},
+		DeleteFunc:  func(e event.DeleteEvent) bool { return false },
+		GenericFunc: func(e event.GenericEvent) bool { return false },
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			n := e.ObjectNew.(*qsv1a1.QuarksSecret)
+			o := e.ObjectOld.(*qsv1a1.QuarksSecret)
+
+			return !reflect.DeepEqual(n.Spec.PublicOutput, o.Spec.PublicOutput) || isResumed(o, n, qsv1a1.WorkPublicOutput)
+		},
+	}
+	err = c.Watch(&source.Kind{Type: &qsv1a1.QuarksSecret{}}, &handler.EnqueueRequestForObject{}, nsPred, p)
+	if err != nil {
+		return errors.Wrapf(err, "Watching quarks secrets failed in public output controller.")
+	}
+
+	// Watch for generated secrets, whose data changes
+	p = predicate.Funcs{
+		CreateFunc:  func(e event.CreateEvent) bool { return isGeneratedSecret(e.Object) },
+		DeleteFunc:  func(e event.DeleteEvent) bool { return false },
+		GenericFunc: func(e event.GenericEvent) bool { return false },
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			n := e.ObjectNew.(*corev1.Secret)
+			o := e.ObjectOld.(*corev1.Secret)
+
+			return isGeneratedSecret(n) && !reflect.DeepEqual(n.Data, o.Data)
+		},
+	}
+	err = c.Watch(&source.Kind{Type: &corev1.Secret{}}, ownerQuarksSecretHandler(ctx), nsPred, p)
+	if err != nil {
+		return errors.Wrapf(err, "Watching generated secrets failed in public output controller.")
+	}
+
+	// Watch for namespaces, which appear or change their labels or
+	// annotations, to keep the config maps of namespace selectors in sync
+	p = predicate.Funcs{
+		CreateFunc:  func(e event.CreateEvent) bool { return true },
+		DeleteFunc:  func(e event.DeleteEvent) bool { return false },
+		GenericFunc: func(e event.GenericEvent) bool { return false },
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			return !reflect.DeepEqual(e.ObjectNew.GetLabels(), e.ObjectOld.GetLabels()) ||
+				!reflect.DeepEqual(e.ObjectNew.GetAnnotations(), e.ObjectOld.GetAnnotations())
+		},
+	}
+	err = c.Watch(&source.Kind{Type: &corev1.Namespace{}}, handler.EnqueueRequestsFromMapFunc(
+		func(a client.Object) []reconcile.Request {
+			reconciles, err := namespaceSelectorReconciles(ctx, mgr.GetClient(), config.MonitoredID, hasPublicOutputSelector)
+			if err != nil {
+				ctxlog.Errorf(ctx, "Failed to calculate reconciles for namespace '%s': %v", a.GetName(), err)
+			}
+
+			return reconciles
+		}), p)
+	if err != nil {
+		return errors.Wrapf(err, "Watching namespaces failed in public output controller.")
+	}
+
+	return nil
+}
+
+// hasPublicOutputSelector returns true, if the public output is copied to
+// namespaces selected by labels
+func hasPublicOutputSelector(qsec *qsv1a1.QuarksSecret) bool {
+	return qsec.Spec.PublicOutput != nil && qsec.Spec.PublicOutput.NamespaceSelector != nil
+}
@@ -0,0 +1,350 @@
+package quarkssecret
+
+import (
+	"context"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	qsv1a1 "code.cloudfoundry.org/quarks-secret/pkg/kube/apis/quarkssecret/v1alpha1"
+	"code.cloudfoundry.org/quarks-utils/pkg/config"
+	"code.cloudfoundry.org/quarks-utils/pkg/ctxlog"
+)
+
+// NewPublicOutputReconciler returns a new ReconcilePublicOutput
+func NewPublicOutputReconciler(ctx context.Context, config *config.Config, mgr manager.Manager, srf setReferenceFunc) reconcile.Reconciler {
+	return &ReconcilePublicOutput{
+		ctx:          ctx,
+		config:       config,
+		client:       mgr.GetClient(),
+		scheme:       mgr.GetScheme(),
+		setReference: srf,
+	}
+}
+
+// ReconcilePublicOutput publishes the public parts of generated secrets in
+// config maps, so they can be read without permission to read secrets
+type ReconcilePublicOutput struct {
+	ctx          context.Context
+	client       client.Client
+	scheme       *runtime.Scheme
+	config       *config.Config
+	setReference setReferenceFunc
+}
+
+// Reconcile writes the public parts of the generated secret to the config
+// map of the public output, in the namespace of the QuarksSecret and in the
+// selected namespaces. Config maps, which are no longer part of the public
+// output, are deleted.
+func (r *ReconcilePublicOutput) Reconcile(_ context.Context, request reconcile.Request) (reconcile.Result, error) {
+	qsec := &qsv1a1.QuarksSecret{}
+
+	// Set the ctx to be Background, as the top-level context for incoming requests.
+	ctx, cancel := context.WithTimeout(r.ctx, r.config.CtxTimeOut)
+	defer cancel()
+
+	ctxlog.Infof(ctx, "Reconciling public output of QuarksSecret %s", request.NamespacedName)
+	err := r.client.Get(ctx, request.NamespacedName, qsec)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			ctxlog.Info(ctx, "Skip reconcile: quarks secret not found")
+			return reconcile.Result{}, nil
+		}
+		ctxlog.Info(ctx, "Error reading the object")
+		return reconcile.Result{}, errors.Wrap(err, "Error reading quarksSecret")
+	}
+
+	if !qsec.GetDeletionTimestamp().IsZero() {
+		ctxlog.Debugf(ctx, "Skip reconcile: QuarksSecret '%s' is being deleted", qsec.GetNamespacedName())
+		return reconcile.Result{}, nil
+	}
+
+	if qsec.Spec.Paused {
+		return reconcile.Result{}, deferWork(ctx, r.client, qsec, qsv1a1.WorkPublicOutput)
+	}
+
+	if qsec.Spec.PublicOutput == nil {
+		return reconcile.Result{}, r.prunePublicOutput(ctx, qsec, map[string]bool{})
+	}
+
+	secret := &corev1.Secret{}
+	err = r.client.Get(ctx, types.NamespacedName{Name: qsec.Spec.SecretName, Namespace: qsec.Namespace}, secret)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			ctxlog.Debugf(ctx, "Skip reconcile: secret '%s/%s' not found", qsec.Namespace, qsec.Spec.SecretName)
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, errors.Wrapf(err, "could not get secret '%s/%s'", qsec.Namespace, qsec.Spec.SecretName)
+	}
+
+	data, err := publicData(qsec.Spec.Type, secret.Data)
+	if err != nil {
+		return reconcile.Result{}, ctxlog.WithEvent(qsec, "PublicOutputFailed").Errorf(ctx, "Failed to publish public output of QuarksSecret '%s': %s", qsec.GetNamespacedName(), err)
+	}
+
+	namespaces, err := r.publicOutputNamespaces(ctx, qsec)
+	if err != nil {
+		return reconcile.Result{}, ctxlog.WithEvent(qsec, "PublicOutputFailed").Errorf(ctx, "Failed to publish public output of QuarksSecret '%s': %s", qsec.GetNamespacedName(), err)
+	}
+
+	published := map[string]bool{}
+	for _, ns := range namespaces {
+		ok, err := r.writeConfigMap(ctx, qsec, ns, data)
+		if err != nil {
+			return reconcile.Result{}, ctxlog.WithEvent(qsec, "PublicOutputFailed").Errorf(ctx, "Failed to publish public output of QuarksSecret '%s': %s", qsec.GetNamespacedName(), err)
+		}
+		if ok {
+			published[ns] = true
+		}
+	}
+
+	return reconcile.Result{}, r.prunePublicOutput(ctx, qsec, published)
+}
+
+// publicOutputNamespaces returns the namespace of the quarks secret and the
+// monitored namespaces selected by the public output, which allow copies
+// from the namespace of the quarks secret
+func (r *ReconcilePublicOutput) publicOutputNamespaces(ctx context.Context, qsec *qsv1a1.QuarksSecret) ([]string, error) {
+	result := []string{qsec.Namespace}
+	if qsec.Spec.PublicOutput.NamespaceSelector == nil {
+		return result, nil
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(qsec.Spec.PublicOutput.NamespaceSelector)
+	if err != nil {
+		return result, errors.Wrap(err, "invalid namespace selector")
+	}
+
+	namespaces := &corev1.NamespaceList{}
+	err = r.client.List(ctx, namespaces, client.MatchingLabelsSelector{Selector: selector})
+	if err != nil {
+		return result, errors.Wrap(err, "could not list namespaces")
+	}
+
+	for i := range namespaces.Items {
+		ns := &namespaces.Items[i]
+		if ns.Name == qsec.Namespace || !ns.GetDeletionTimestamp().IsZero() {
+			continue
+		}
+		if !qsv1a1.IsMonitoredNamespace(ns, r.config.MonitoredID) {
+			ctxlog.Debugf(ctx, "Skip public output: namespace '%s' is not monitored", ns.Name)
+			continue
+		}
+		if !allowsCopiesFrom(ns, qsec.Namespace) {
+			ctxlog.WithEvent(qsec, "PublicOutputReconcile").Infof(ctx, "Skip public output: namespace '%s' does not allow copies from '%s'", ns.Name, qsec.Namespace)
+			continue
+		}
+		result = append(result, ns.Name)
+	}
+	return result, nil
+}
+
+// writeConfigMap creates or updates the config map of the public output in
+// the namespace. Existing config maps, which are not published by the
+// quarks secret, are never overwritten.
+func (r *ReconcilePublicOutput) writeConfigMap(ctx context.Context, qsec *qsv1a1.QuarksSecret, namespace string, data map[string]string) (bool, error) {
+	name := qsec.Spec.PublicOutput.ConfigMapName
+
+	existing := &corev1.ConfigMap{}
+	err := r.client.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, existing)
+	if err == nil && existing.GetAnnotations()[qsv1a1.AnnotationPublicOutputOf] != qsec.GetNamespacedName() {
+		ctxlog.WithEvent(qsec, "PublicOutputReconcile").Infof(ctx, "Skip public output: config map '%s/%s' exists and is not published by the quarks secret", namespace, name)
+		return false, nil
+	}
+	if err != nil && !apierrors.IsNotFound(err) {
+		return false, errors.Wrapf(err, "could not get config map '%s/%s'", namespace, name)
+	}
+
+	configMap := &corev1.ConfigMap{}
+	configMap.Name = name
+	configMap.Namespace = namespace
+	op, err := controllerutil.CreateOrUpdate(ctx, r.client, configMap, func() error {
+		if configMap.Labels == nil {
+			configMap.Labels = map[string]string{}
+		}
+		configMap.Labels[qsv1a1.LabelKind] = qsv1a1.PublicConfigMapKind
+
+		if configMap.Annotations == nil {
+			configMap.Annotations = map[string]string{}
+		}
+		configMap.Annotations[qsv1a1.AnnotationPublicOutputOf] = qsec.GetNamespacedName()
+
+		configMap.Data = data
+
+		// Only the config map in the quarks secret's namespace can be garbage collected
+		if namespace == qsec.Namespace {
+			return r.setReference(qsec, configMap, r.scheme)
+		}
+		return nil
+	})
+	if err != nil {
+		return false, errors.Wrapf(err, "could not create or update config map '%s/%s'", namespace, name)
+	}
+	if op != controllerutil.OperationResultNone {
+		ctxlog.WithEvent(qsec, "PublicOutputReconcile").Infof(ctx, "Public output config map '%s' has been %s in namespace '%s'", name, op, namespace)
+	}
+
+	return true, nil
+}
+
+// prunePublicOutput deletes the config maps published by the quarks
+// secret, which are not the public output config map in one of the
+// published namespaces
+func (r *ReconcilePublicOutput) prunePublicOutput(ctx context.Context, qsec *qsv1a1.QuarksSecret, published map[string]bool) error {
+	configMaps, err := listPublicConfigMaps(ctx, r.client, qsec)
+	if err != nil {
+		return err
+	}
+
+	for i := range configMaps {
+		configMap := &configMaps[i]
+		if published[configMap.Namespace] && configMap.Name == qsec.Spec.PublicOutput.ConfigMapName {
+			continue
+		}
+
+		if err := r.client.Delete(ctx, configMap); err != nil && !apierrors.IsNotFound(err) {
+			return errors.Wrapf(err, "could not delete config map '%s/%s'", configMap.Namespace, configMap.Name)
+		}
+		ctxlog.WithEvent(qsec, "PublicOutputReconcile").Infof(ctx, "Public output config map '%s' has been deleted in namespace '%s'", configMap.Name, configMap.Namespace)
+	}
+	return nil
+}
+
+// listPublicConfigMaps lists the config maps in all namespaces, which are
+// published by the quarks secret
+func listPublicConfigMaps(ctx context.Context, c client.Client, qsec *qsv1a1.QuarksSecret) ([]corev1.ConfigMap, error) {
+	list := &corev1.ConfigMapList{}
+	err := c.List(ctx, list, client.MatchingLabels{qsv1a1.LabelKind: qsv1a1.PublicConfigMapKind})
+	if err != nil {
+		return nil, errors.Wrap(err, "could not list public output config maps")
+	}
+
+	result := []corev1.ConfigMap{}
+	for _, configMap := range list.Items {
+		if configMap.GetAnnotations()[qsv1a1.AnnotationPublicOutputOf] == qsec.GetNamespacedName() {
+			result = append(result, configMap)
+		}
+	}
+	return result, nil
+}
+
+// publicData returns the public parts of the secret data and the
+// fingerprint of the certificate or public key
+func publicData(secretType qsv1a1.SecretType, data map[string][]byte) (map[string]string, error) {
+	result := map[string]string{}
+
+	switch secretType {
+	case qsv1a1.Certificate, qsv1a1.TLS:
+		key := "certificate"
+		if secretType == qsv1a1.TLS {
+			key = "tls.crt"
+		}
+		if len(data[key]) == 0 {
+			return nil, fmt.Errorf("secret has no '%s'", key)
+		}
+		result[key] = string(data[key])
+		if ca, ok := data["ca"]; ok {
+			result["ca"] = string(ca)
+		}
+
+		status, err := certificateStatus(data[key])
+		if err != nil {
+			return nil, err
+		}
+		result["fingerprint"] = status.Fingerprint
+	case qsv1a1.RSAKey:
+		if len(data["public_key"]) == 0 {
+			return nil, errors.New("secret has no 'public_key'")
+		}
+		result["public_key"] = string(data["public_key"])
+
+		fingerprint, err := rsaKeyFingerprint(data["public_key"])
+		if err != nil {
+			return nil, err
+		}
+		result["fingerprint"] = fingerprint
+
+		jwks, err := rsaJWKS(data["public_key"], fingerprint)
+		if err != nil {
+			return nil, err
+		}
+		result["jwks.json"] = jwks
+	case qsv1a1.SSHKey:
+		if len(data["public_key"]) == 0 {
+			return nil, errors.New("secret has no 'public_key'")
+		}
+		result["public_key"] = string(data["public_key"])
+
+		fingerprint, err := sshKeyFingerprint(data["public_key"])
+		if err != nil {
+			return nil, err
+		}
+		result["fingerprint"] = fingerprint
+	default:
+		return nil, fmt.Errorf("secrets of type '%s' have no public parts", secretType)
+	}
+
+	return result, nil
+}
+
+// jsonWebKey is the JWK representation of an RSA public key, see RFC 7517
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+// rsaJWKS returns a JSON web key set, which holds the PEM encoded RSA
+// public key
+func rsaJWKS(data []byte, kid string) (string, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return "", errors.New("failed to decode public key PEM")
+	}
+
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to parse public key")
+	}
+	rsaKey, ok := key.(*rsa.PublicKey)
+	if !ok {
+		return "", errors.New("public key is not an RSA key")
+	}
+
+	jwks := struct {
+		Keys []jsonWebKey `json:"keys"`
+	}{
+		Keys: []jsonWebKey{{
+			Kty: "RSA",
+			Use: "sig",
+			Alg: "RS256",
+			Kid: kid,
+			N:   base64.RawURLEncoding.EncodeToString(rsaKey.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(rsaKey.E)).Bytes()),
+		}},
+	}
+
+	out, err := json.Marshal(jwks)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to marshal JWKS")
+	}
+	return string(out), nil
+}
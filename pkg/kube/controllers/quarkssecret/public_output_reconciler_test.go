@@ -0,0 +1,290 @@
+package quarkssecret_test
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"go.uber.org/zap"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	crc "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"code.cloudfoundry.org/quarks-secret/pkg/credsgen"
+	inmemorygenerator "code.cloudfoundry.org/quarks-secret/pkg/credsgen/in_memory_generator"
+	qsv1a1 "code.cloudfoundry.org/quarks-secret/pkg/kube/apis/quarkssecret/v1alpha1"
+	"code.cloudfoundry.org/quarks-secret/pkg/kube/client/clientset/versioned/scheme"
+	"code.cloudfoundry.org/quarks-secret/pkg/kube/controllers"
+	cfakes "code.cloudfoundry.org/quarks-secret/pkg/kube/controllers/fakes"
+	qscontroller "code.cloudfoundry.org/quarks-secret/pkg/kube/controllers/quarkssecret"
+	cfcfg "code.cloudfoundry.org/quarks-utils/pkg/config"
+	"code.cloudfoundry.org/quarks-utils/pkg/ctxlog"
+	helper "code.cloudfoundry.org/quarks-utils/testing/testhelper"
+)
+
+var _ = Describe("ReconcilePublicOutput", func() {
+	var (
+		manager          *cfakes.FakeManager
+		reconciler       reconcile.Reconciler
+		request          reconcile.Request
+		ctx              context.Context
+		log              *zap.SugaredLogger
+		config           *cfcfg.Config
+		client           *cfakes.FakeClient
+		statusWriter     *cfakes.FakeStatusWriter
+		inMemory         credsgen.Generator
+		qSecret          *qsv1a1.QuarksSecret
+		secret           *corev1.Secret
+		configMaps       []corev1.ConfigMap
+		namespaces       []corev1.Namespace
+		setReferenceFunc func(owner, object metav1.Object, scheme *runtime.Scheme) error
+	)
+
+	// written returns the config maps, which were created or updated
+	written := func() []*corev1.ConfigMap {
+		result := []*corev1.ConfigMap{}
+		for i := 0; i < client.CreateCallCount(); i++ {
+			_, object, _ := client.CreateArgsForCall(i)
+			result = append(result, object.(*corev1.ConfigMap))
+		}
+		for i := 0; i < client.UpdateCallCount(); i++ {
+			_, object, _ := client.UpdateArgsForCall(i)
+			result = append(result, object.(*corev1.ConfigMap))
+		}
+		return result
+	}
+
+	BeforeEach(func() {
+		err := controllers.AddToScheme(scheme.Scheme)
+		Expect(err).ToNot(HaveOccurred())
+		manager = &cfakes.FakeManager{}
+		request = reconcile.Request{NamespacedName: types.NamespacedName{Name: "foo", Namespace: "default"}}
+		config = &cfcfg.Config{CtxTimeOut: 10 * time.Second, MonitoredID: "quarks"}
+		_, log = helper.NewTestLogger()
+		ctx = ctxlog.NewContextWithRecorder(ctxlog.NewParentContext(log), "TestRecorder", record.NewFakeRecorder(10))
+		inMemory = inmemorygenerator.NewInMemoryGenerator(log)
+		setReferenceFunc = func(owner, object metav1.Object, scheme *runtime.Scheme) error { return nil }
+
+		cert, err := inMemory.GenerateCertificate("ca", credsgen.CertificateGenerationRequest{IsCA: true, CommonName: "ca.example.com"})
+		Expect(err).ToNot(HaveOccurred())
+
+		qSecret = &qsv1a1.QuarksSecret{
+			ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "default"},
+			Spec: qsv1a1.QuarksSecretSpec{
+				Type:         qsv1a1.Certificate,
+				SecretName:   "generated-secret",
+				PublicOutput: &qsv1a1.PublicOutput{ConfigMapName: "foo-public"},
+			},
+		}
+		secret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "generated-secret",
+				Namespace: "default",
+				Labels:    map[string]string{qsv1a1.LabelKind: qsv1a1.GeneratedSecretKind},
+			},
+			Data: map[string][]byte{
+				"certificate": cert.Certificate,
+				"private_key": cert.PrivateKey,
+				"ca":          cert.Certificate,
+				"is_ca":       []byte("true"),
+			},
+		}
+		configMaps = []corev1.ConfigMap{}
+		namespaces = []corev1.Namespace{}
+
+		client = &cfakes.FakeClient{}
+		client.GetCalls(func(context context.Context, nn types.NamespacedName, object crc.Object) error {
+			switch object := object.(type) {
+			case *qsv1a1.QuarksSecret:
+				qSecret.DeepCopyInto(object)
+				return nil
+			case *corev1.Secret:
+				if nn.Name == secret.Name {
+					secret.DeepCopyInto(object)
+					return nil
+				}
+			case *corev1.ConfigMap:
+				for i := range configMaps {
+					if configMaps[i].Name == nn.Name && configMaps[i].Namespace == nn.Namespace {
+						configMaps[i].DeepCopyInto(object)
+						return nil
+					}
+				}
+			}
+			return apierrors.NewNotFound(schema.GroupResource{}, nn.Name)
+		})
+		client.ListCalls(func(context context.Context, object crc.ObjectList, _ ...crc.ListOption) error {
+			switch object := object.(type) {
+			case *corev1.ConfigMapList:
+				object.Items = configMaps
+			case *corev1.NamespaceList:
+				object.Items = namespaces
+			}
+			return nil
+		})
+		statusWriter = &cfakes.FakeStatusWriter{}
+		client.StatusCalls(func() crc.StatusWriter { return statusWriter })
+		manager.GetClientReturns(client)
+		manager.GetSchemeReturns(scheme.Scheme)
+	})
+
+	JustBeforeEach(func() {
+		reconciler = qscontroller.NewPublicOutputReconciler(ctx, config, manager, setReferenceFunc)
+	})
+
+	It("publishes the certificate, the CA and the fingerprint", func() {
+		_, err := reconciler.Reconcile(context.Background(), request)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(written()).To(HaveLen(1))
+		configMap := written()[0]
+		Expect(configMap.Namespace).To(Equal("default"))
+		Expect(configMap.Name).To(Equal("foo-public"))
+		Expect(configMap.Labels).To(HaveKeyWithValue(qsv1a1.LabelKind, qsv1a1.PublicConfigMapKind))
+		Expect(configMap.Annotations).To(HaveKeyWithValue(qsv1a1.AnnotationPublicOutputOf, "default/foo"))
+		Expect(configMap.Data).To(HaveKeyWithValue("certificate", string(secret.Data["certificate"])))
+		Expect(configMap.Data).To(HaveKeyWithValue("ca", string(secret.Data["ca"])))
+		Expect(configMap.Data).To(HaveKey("fingerprint"))
+		Expect(configMap.Data).ToNot(HaveKey("private_key"))
+	})
+
+	It("publishes the public key as JWKS for RSA keys", func() {
+		key, err := inMemory.GenerateRSAKey("foo")
+		Expect(err).ToNot(HaveOccurred())
+		qSecret.Spec.Type = qsv1a1.RSAKey
+		secret.Data = map[string][]byte{"private_key": key.PrivateKey, "public_key": key.PublicKey}
+
+		_, err = reconciler.Reconcile(context.Background(), request)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(written()).To(HaveLen(1))
+		data := written()[0].Data
+		Expect(data).To(HaveKeyWithValue("public_key", string(key.PublicKey)))
+		Expect(data).ToNot(HaveKey("private_key"))
+
+		jwks := struct {
+			Keys []map[string]string `json:"keys"`
+		}{}
+		Expect(json.Unmarshal([]byte(data["jwks.json"]), &jwks)).To(Succeed())
+		Expect(jwks.Keys).To(HaveLen(1))
+		Expect(jwks.Keys[0]).To(HaveKeyWithValue("kty", "RSA"))
+		Expect(jwks.Keys[0]).To(HaveKeyWithValue("kid", data["fingerprint"]))
+		Expect(jwks.Keys[0]).To(HaveKeyWithValue("e", "AQAB"))
+	})
+
+	It("fails for secret types without public parts", func() {
+		qSecret.Spec.Type = qsv1a1.Password
+		secret.Data = map[string][]byte{"password": []byte("secret")}
+
+		_, err := reconciler.Reconcile(context.Background(), request)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("have no public parts"))
+		Expect(written()).To(BeEmpty())
+	})
+
+	It("copies the config map to the selected namespaces", func() {
+		qSecret.Spec.PublicOutput.NamespaceSelector = &metav1.LabelSelector{}
+		namespaces = []corev1.Namespace{
+			{ObjectMeta: metav1.ObjectMeta{Name: "default"}},
+			{ObjectMeta: metav1.ObjectMeta{
+				Name:        "app",
+				Labels:      map[string]string{qsv1a1.LabelNamespace: "quarks"},
+				Annotations: map[string]string{qsv1a1.AnnotationAllowCopiesFrom: "default"},
+			}},
+		}
+
+		_, err := reconciler.Reconcile(context.Background(), request)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(written()).To(HaveLen(2))
+		Expect(written()[0].Namespace).To(Equal("default"))
+		Expect(written()[1].Namespace).To(Equal("app"))
+		Expect(written()[1].Data).To(Equal(written()[0].Data))
+	})
+
+	It("skips selected namespaces, which are not monitored or don't allow copies", func() {
+		qSecret.Spec.PublicOutput.NamespaceSelector = &metav1.LabelSelector{}
+		namespaces = []corev1.Namespace{
+			{ObjectMeta: metav1.ObjectMeta{
+				Name:        "unmonitored",
+				Labels:      map[string]string{qsv1a1.LabelNamespace: "other"},
+				Annotations: map[string]string{qsv1a1.AnnotationAllowCopiesFrom: "*"},
+			}},
+			{ObjectMeta: metav1.ObjectMeta{
+				Name:   "not-opted-in",
+				Labels: map[string]string{qsv1a1.LabelNamespace: "quarks"},
+			}},
+		}
+
+		_, err := reconciler.Reconcile(context.Background(), request)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(written()).To(HaveLen(1))
+		Expect(written()[0].Namespace).To(Equal("default"))
+	})
+
+	It("does not overwrite config maps, which are not published by the quarks secret", func() {
+		configMaps = []corev1.ConfigMap{
+			{ObjectMeta: metav1.ObjectMeta{Name: "foo-public", Namespace: "default"}},
+		}
+
+		_, err := reconciler.Reconcile(context.Background(), request)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(written()).To(BeEmpty())
+		Expect(client.DeleteCallCount()).To(Equal(0))
+	})
+
+	It("deletes config maps in namespaces, which are no longer selected", func() {
+		configMaps = []corev1.ConfigMap{
+			{ObjectMeta: metav1.ObjectMeta{
+				Name:        "foo-public",
+				Namespace:   "app",
+				Labels:      map[string]string{qsv1a1.LabelKind: qsv1a1.PublicConfigMapKind},
+				Annotations: map[string]string{qsv1a1.AnnotationPublicOutputOf: "default/foo"},
+			}},
+		}
+
+		_, err := reconciler.Reconcile(context.Background(), request)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(client.DeleteCallCount()).To(Equal(1))
+		_, object, _ := client.DeleteArgsForCall(0)
+		Expect(object.GetNamespace()).To(Equal("app"))
+	})
+
+	It("deletes the config maps, once the public output is removed", func() {
+		qSecret.Spec.PublicOutput = nil
+		configMaps = []corev1.ConfigMap{
+			{ObjectMeta: metav1.ObjectMeta{
+				Name:        "foo-public",
+				Namespace:   "default",
+				Labels:      map[string]string{qsv1a1.LabelKind: qsv1a1.PublicConfigMapKind},
+				Annotations: map[string]string{qsv1a1.AnnotationPublicOutputOf: "default/foo"},
+			}},
+		}
+
+		_, err := reconciler.Reconcile(context.Background(), request)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(client.DeleteCallCount()).To(Equal(1))
+		Expect(written()).To(BeEmpty())
+	})
+
+	It("defers the public output, while the quarks secret is paused", func() {
+		qSecret.Spec.Paused = true
+
+		_, err := reconciler.Reconcile(context.Background(), request)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(written()).To(BeEmpty())
+		Expect(statusWriter.UpdateCallCount()).To(Equal(1))
+		_, object, _ := statusWriter.UpdateArgsForCall(0)
+		Expect(object.(*qsv1a1.QuarksSecret).Status.PendingWork).To(ConsistOf(qsv1a1.WorkPublicOutput))
+	})
+})
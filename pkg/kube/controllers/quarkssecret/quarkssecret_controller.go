@@ -15,12 +15,14 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	credsgen "code.cloudfoundry.org/quarks-secret/pkg/credsgen/in_memory_generator"
 	qsv1a1 "code.cloudfoundry.org/quarks-secret/pkg/kube/apis/quarkssecret/v1alpha1"
 	"code.cloudfoundry.org/quarks-utils/pkg/config"
 	"code.cloudfoundry.org/quarks-utils/pkg/ctxlog"
+	"code.cloudfoundry.org/quarks-utils/pkg/skip"
 )
 
 // AddQuarksSecret creates a new QuarksSecrets controller to watch for the
@@ -64,6 +66,15 @@ func AddQuarksSecret(ctx context.Context, config *config.Config, mgr manager.Man
 			n := e.ObjectNew.(*qsv1a1.QuarksSecret)
 			o := e.ObjectOld.(*qsv1a1.QuarksSecret)
 
+			// reconcile if it was resumed and the generation was deferred
+			if isResumed(o, n, qsv1a1.WorkGeneration) {
+				ctxlog.NewPredicateEvent(e.ObjectNew).Debug(
+					ctx, e.ObjectNew, "qsv1a1.QuarksSecret",
+					fmt.Sprintf("Update predicate passed for '%s/%s': resumed", e.ObjectNew.GetNamespace(), e.ObjectNew.GetName()),
+				)
+				return true
+			}
+
 			// reconcile if it was already generated and the spec changed except for `SecretLabels` & `SecretAnnotations`
 			if o.Status.IsGenerated() {
 				for _, key := range []string{"Type", "Request", "SecretName", "Copies"} {
@@ -80,6 +91,15 @@ func AddQuarksSecret(ctx context.Context, config *config.Config, mgr manager.Man
 				}
 			}
 
+			// reconcile if a user provided secret should be adopted now
+			if n.Spec.ExistingSecretPolicy != o.Spec.ExistingSecretPolicy && n.Spec.ExistingSecretPolicy == qsv1a1.ExistingSecretAdopt && isUserProvided(n) {
+				ctxlog.NewPredicateEvent(e.ObjectNew).Debug(
+					ctx, e.ObjectNew, "qsv1a1.QuarksSecret",
+					fmt.Sprintf("Update predicate passed for '%s/%s': existing secret should be adopted", e.ObjectNew.GetNamespace(), e.ObjectNew.GetName()),
+				)
+				return true
+			}
+
 			// reconcile if it was already generated and controller requested update
 			if n.Status.NotGenerated() {
 				ctxlog.NewPredicateEvent(e.ObjectNew).Debug(
@@ -96,6 +116,76 @@ func AddQuarksSecret(ctx context.Context, config *config.Config, mgr manager.Man
 		return errors.Wrapf(err, "Watching quarks secrets failed in quarksSecret controller.")
 	}
 
+	// Index QuarksSecrets by the secrets they reference, so changes can be
+	// rendered again
+	err = mgr.GetFieldIndexer().IndexField(ctx, &qsv1a1.QuarksSecret{}, referencedSecretsIndex, indexReferencedSecrets)
+	if err != nil {
+		return errors.Wrapf(err, "Indexing referenced secrets failed in quarksSecret controller.")
+	}
+	err = mgr.GetFieldIndexer().IndexField(ctx, &qsv1a1.QuarksSecret{}, referencedNamespacesIndex, indexReferencedNamespaces)
+	if err != nil {
+		return errors.Wrapf(err, "Indexing referenced namespaces failed in quarksSecret controller.")
+	}
+
+	// Watch for changes to secrets referenced by templated configs and docker
+	// configs. Referenced secrets can live in namespaces, which are not
+	// monitored, so only the referencing quarks secrets are filtered.
+	p = predicate.Funcs{
+		CreateFunc:  func(e event.CreateEvent) bool { return true },
+		DeleteFunc:  func(e event.DeleteEvent) bool { return false },
+		GenericFunc: func(e event.GenericEvent) bool { return false },
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			n := e.ObjectNew.(*corev1.Secret)
+			o := e.ObjectOld.(*corev1.Secret)
+
+			return !reflect.DeepEqual(n.Data, o.Data) ||
+				n.Annotations[qsv1a1.AnnotationAllowReferencesFrom] != o.Annotations[qsv1a1.AnnotationAllowReferencesFrom]
+		},
+	}
+	err = c.Watch(&source.Kind{Type: &corev1.Secret{}}, handler.EnqueueRequestsFromMapFunc(
+		func(a crc.Object) []reconcile.Request {
+			secret := a.(*corev1.Secret)
+
+			if skip.Reconciles(ctx, mgr.GetClient(), secret) {
+				return []reconcile.Request{}
+			}
+
+			reconciles, err := listReferencingQuarksSecretsReconciles(ctx, mgr.GetClient(), config.MonitoredID, secret)
+			if err != nil {
+				ctxlog.Errorf(ctx, "Failed to calculate reconciles for secret '%s/%s': %v", secret.Namespace, secret.Name, err)
+			}
+
+			return reconciles
+		}), p)
+	if err != nil {
+		return errors.Wrapf(err, "Watching referenced secrets failed in quarksSecret controller.")
+	}
+
+	// Watch for namespaces, which change whether they allow references to
+	// their secrets
+	p = predicate.Funcs{
+		CreateFunc:  func(e event.CreateEvent) bool { return false },
+		DeleteFunc:  func(e event.DeleteEvent) bool { return false },
+		GenericFunc: func(e event.GenericEvent) bool { return false },
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			return e.ObjectNew.GetAnnotations()[qsv1a1.AnnotationAllowReferencesFrom] != e.ObjectOld.GetAnnotations()[qsv1a1.AnnotationAllowReferencesFrom]
+		},
+	}
+	err = c.Watch(&source.Kind{Type: &corev1.Namespace{}}, handler.EnqueueRequestsFromMapFunc(
+		func(a crc.Object) []reconcile.Request {
+			ns := a.(*corev1.Namespace)
+
+			reconciles, err := listNamespaceReferencingQuarksSecretsReconciles(ctx, mgr.GetClient(), config.MonitoredID, ns)
+			if err != nil {
+				ctxlog.Errorf(ctx, "Failed to calculate reconciles for namespace '%s': %v", ns.Name, err)
+			}
+
+			return reconciles
+		}), p)
+	if err != nil {
+		return errors.Wrapf(err, "Watching namespaces failed in quarksSecret controller.")
+	}
+
 	return nil
 }
 
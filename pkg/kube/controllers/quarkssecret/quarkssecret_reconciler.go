@@ -8,6 +8,7 @@ import (
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
@@ -119,11 +120,28 @@ func (r *ReconcileQuarksSecret) Reconcile(_ context.Context, request reconcile.R
 		ctxlog.Info(ctx, "Error reading the object")
 		return reconcile.Result{}, errors.Wrap(err, "Error reading quarksSecret")
 	}
+	if qsec.Spec.Paused {
+		return reconcile.Result{}, deferWork(ctx, r.client, qsec, qsv1a1.WorkGeneration)
+	}
+
 	if meltdown.NewWindow(r.config.MeltdownDuration, qsec.Status.LastReconcile).Contains(time.Now()) {
 		ctxlog.WithEvent(qsec, "Meltdown").Debugf(ctx, "Resource '%s' is in meltdown, requeue reconcile after %s", qsec.GetNamespacedName(), r.config.MeltdownRequeueAfter)
 		return reconcile.Result{RequeueAfter: r.config.MeltdownRequeueAfter}, nil
 	}
 
+	status := qsec.Status.DeepCopy()
+
+	adopted, err := r.adoptSecret(ctx, qsec)
+	if err != nil {
+		setCondition(qsec, qsv1a1.ConditionGenerated, metav1.ConditionFalse, reasonAdoptionFailed, errors.Cause(err).Error())
+		updateConditions(ctx, r.client, qsec, status)
+		return reconcile.Result{}, errors.Wrap(err, "adopting existing secret failed.")
+	}
+	if adopted {
+		r.updateStatus(ctx, qsec)
+		return reconcile.Result{}, nil
+	}
+
 	// Create secret
 	switch qsec.Spec.Type {
 	case qsv1a1.Password:
@@ -131,6 +149,7 @@ func (r *ReconcileQuarksSecret) Reconcile(_ context.Context, request reconcile.R
 		err = r.createPasswordSecret(ctx, qsec)
 		if err != nil {
 			ctxlog.Infof(ctx, "Error generating password secret: %s", err.Error())
+			r.generationFailed(ctx, qsec, status, err)
 			return reconcile.Result{}, errors.Wrap(err, "generating password secret failed.")
 		}
 	case qsv1a1.RSAKey:
@@ -138,6 +157,7 @@ func (r *ReconcileQuarksSecret) Reconcile(_ context.Context, request reconcile.R
 		err = r.createRSASecret(ctx, qsec)
 		if err != nil {
 			ctxlog.Infof(ctx, "Error generating RSA key secret: %s", err.Error())
+			r.generationFailed(ctx, qsec, status, err)
 			return reconcile.Result{}, errors.Wrap(err, "generating RSA key secret failed.")
 		}
 	case qsv1a1.SSHKey:
@@ -145,6 +165,7 @@ func (r *ReconcileQuarksSecret) Reconcile(_ context.Context, request reconcile.R
 		err = r.createSSHSecret(ctx, qsec)
 		if err != nil {
 			ctxlog.Infof(ctx, "Error generating SSH key secret: %s", err.Error())
+			r.generationFailed(ctx, qsec, status, err)
 			return reconcile.Result{}, errors.Wrap(err, "generating SSH key secret failed.")
 		}
 	case qsv1a1.Certificate, qsv1a1.TLS:
@@ -153,53 +174,94 @@ func (r *ReconcileQuarksSecret) Reconcile(_ context.Context, request reconcile.R
 		if err != nil {
 			if isCaNotReady(err) {
 				ctxlog.Info(ctx, fmt.Sprintf("CA for secret '%s' is not ready yet: %s", request.NamespacedName, err))
+				setCondition(qsec, qsv1a1.ConditionCAReady, metav1.ConditionFalse, "CANotReady", errors.Cause(err).Error())
+				updateConditions(ctx, r.client, qsec, status)
 				return reconcile.Result{RequeueAfter: time.Second * 5}, nil
 			}
 			ctxlog.Info(ctx, "Error generating certificate secret: "+err.Error())
+			r.generationFailed(ctx, qsec, status, err)
 			return reconcile.Result{}, errors.Wrap(err, "generating certificate secret.")
 		}
 	case qsv1a1.BasicAuth:
 		err = r.createBasicAuthSecret(ctx, qsec)
 		if err != nil {
+			r.generationFailed(ctx, qsec, status, err)
 			return reconcile.Result{}, errors.Wrap(err, "generating basic-auth secret")
 		}
 	case qsv1a1.TemplatedConfig:
-		if err := r.createTemplatedConfigSecret(ctx, qsec); err != nil {
+		rendered, err := r.createTemplatedConfigSecret(ctx, qsec)
+		if err != nil {
 			if isSecNotReady(err) {
 				ctxlog.Info(ctx, fmt.Sprintf("Secrets '%s' is not ready yet: %s", request.NamespacedName, err))
+				setCondition(qsec, qsv1a1.ConditionDependenciesReady, metav1.ConditionFalse, "SecretNotFound", errors.Cause(err).Error())
+				updateConditions(ctx, r.client, qsec, status)
 				return reconcile.Result{RequeueAfter: time.Second * 5}, nil
 			}
 			ctxlog.Info(ctx, "Error generating templatedConfig secret: "+err.Error())
+			r.generationFailed(ctx, qsec, status, err)
 			return reconcile.Result{}, errors.Wrap(err, "generating templatedConfig secret.")
 		}
+		setCondition(qsec, qsv1a1.ConditionDependenciesReady, metav1.ConditionTrue, "SecretsFound", "All referenced secrets were found")
+		if !rendered {
+			ctxlog.Debugf(ctx, "Skip rendering: inputs of QuarksSecret '%s' did not change", request.NamespacedName)
+			updateConditions(ctx, r.client, qsec, status)
+			return reconcile.Result{}, nil
+		}
 	case qsv1a1.SecretCopy:
-		// noop
+		// the secret is copied from the source quarks secret by the copy reconciler
+		if !meta.IsStatusConditionTrue(qsec.Status.Conditions, qsv1a1.ConditionGenerated) {
+			setCondition(qsec, qsv1a1.ConditionGenerated, metav1.ConditionFalse, "WaitingForCopy", "Waiting for the secret to be copied")
+			updateConditions(ctx, r.client, qsec, status)
+		}
 		return reconcile.Result{}, nil
 	case qsv1a1.DockerConfigJSON:
 		ctxlog.Info(ctx, "Generating dockerConfigJson")
-		err = r.createDockerConfigJSON(ctx, qsec)
+		rendered, err := r.createDockerConfigJSON(ctx, qsec)
 		if err != nil {
 			if isSecNotReady(err) {
 				ctxlog.Info(ctx, fmt.Sprintf("Secrets '%s' is not ready yet: %s", request.NamespacedName, err))
+				setCondition(qsec, qsv1a1.ConditionDependenciesReady, metav1.ConditionFalse, "SecretNotFound", errors.Cause(err).Error())
+				updateConditions(ctx, r.client, qsec, status)
 				return reconcile.Result{RequeueAfter: time.Second * 5}, nil
 			}
 			ctxlog.Info(ctx, "Error generating dockerConfigJson secret: "+err.Error())
+			r.generationFailed(ctx, qsec, status, err)
 			return reconcile.Result{}, errors.Wrap(err, "generating dockerConfigJson secret.")
 		}
+		setCondition(qsec, qsv1a1.ConditionDependenciesReady, metav1.ConditionTrue, "SecretsFound", "All referenced secrets were found")
+		if !rendered {
+			ctxlog.Debugf(ctx, "Skip rendering: inputs of QuarksSecret '%s' did not change", request.NamespacedName)
+			updateConditions(ctx, r.client, qsec, status)
+			return reconcile.Result{}, nil
+		}
 	default:
 		err = ctxlog.WithEvent(qsec, "InvalidTypeError").Errorf(ctx, "Invalid type: %s", qsec.Spec.Type)
+		setCondition(qsec, qsv1a1.ConditionGenerated, metav1.ConditionFalse, "InvalidType", err.Error())
+		updateConditions(ctx, r.client, qsec, status)
 		return reconcile.Result{}, err
 	}
 	r.updateStatus(ctx, qsec)
 	return reconcile.Result{}, nil
 }
 
+// generationFailed records the error in the Generated condition, or in the
+// DependenciesReady condition if a referenced secret is missing
+func (r *ReconcileQuarksSecret) generationFailed(ctx context.Context, qsec *qsv1a1.QuarksSecret, status *qsv1a1.QuarksSecretStatus, err error) {
+	if isSecNotReady(err) {
+		setCondition(qsec, qsv1a1.ConditionDependenciesReady, metav1.ConditionFalse, "SecretNotFound", errors.Cause(err).Error())
+	} else {
+		setCondition(qsec, qsv1a1.ConditionGenerated, metav1.ConditionFalse, "GenerationFailed", err.Error())
+	}
+	updateConditions(ctx, r.client, qsec, status)
+}
+
 func (r *ReconcileQuarksSecret) updateStatus(ctx context.Context, qsec *qsv1a1.QuarksSecret) {
 	qsec.Status.Generated = pointers.Bool(true)
 	qsec.Status.Copied = pointers.Bool(false)
 
 	now := metav1.Now()
 	qsec.Status.LastReconcile = &now
+	qsec.Status.DriftDetected = nil
 	err := r.client.Status().Update(ctx, qsec)
 	if err != nil {
 		ctxlog.Errorf(ctx, "could not create or update QuarksSecret status '%s': %v", qsec.GetNamespacedName(), err)
@@ -249,10 +311,12 @@ func (r *ReconcileQuarksSecret) createSecrets(ctx context.Context, qsec *qsv1a1.
 	}
 	if skipCreation {
 		ctxlog.WithEvent(qsec, "SkipCreation").Infof(ctx, "Skip creation: Secret '%s/%s' already exists and it's not generated", qsec.Namespace, qsec.Spec.SecretName)
+		setCondition(qsec, qsv1a1.ConditionGenerated, metav1.ConditionTrue, reasonUserProvided, fmt.Sprintf("Secret '%s' was provided by the user", qsec.Spec.SecretName))
 	} else {
 		if err := r.createSecret(ctx, qsec, secret); err != nil {
 			return err
 		}
+		setCondition(qsec, qsv1a1.ConditionGenerated, metav1.ConditionTrue, "Generated", fmt.Sprintf("Secret '%s' has been generated", qsec.Spec.SecretName))
 	}
 
 	return nil
@@ -275,7 +339,14 @@ func (r *ReconcileQuarksSecret) createSecret(ctx context.Context, qsec *qsv1a1.Q
 		return errors.Wrapf(err, "error setting owner for secret '%s' to QuarksSecret '%s'", secret.GetName(), qsec.GetNamespacedName())
 	}
 
-	op, err := controllerutil.CreateOrUpdate(ctx, r.client, secret, mutate.SecretMutateFn(secret))
+	mutateFn := mutate.SecretMutateFn(secret)
+	op, err := controllerutil.CreateOrUpdate(ctx, r.client, secret, func() error {
+		if err := mutateFn(); err != nil {
+			return err
+		}
+		// the hash covers keys, which were added by others, too
+		return setContentHash(secret)
+	})
 	if err != nil {
 		return errors.Wrapf(err, "could not create or update secret '%s/%s'", secret.Namespace, secret.GetName())
 	}
@@ -284,5 +355,11 @@ func (r *ReconcileQuarksSecret) createSecret(ctx context.Context, qsec *qsv1a1.Q
 		ctxlog.Debugf(ctx, "Secret '%s' has been %s", secret.Name, op)
 	}
 
+	if qsec.Spec.DriftPolicy == qsv1a1.DriftRestore && secret.Name == qsec.Spec.SecretName {
+		if err := storeBackup(ctx, r.client, r.scheme, r.setReference, qsec, secret); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
@@ -11,9 +11,13 @@ import (
 	. "github.com/onsi/gomega"
 	"go.uber.org/zap"
 
+	certv1 "k8s.io/api/certificates/v1"
+	certv1beta1 "k8s.io/api/certificates/v1beta1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
@@ -22,6 +26,7 @@ import (
 
 	"code.cloudfoundry.org/quarks-secret/pkg/credsgen"
 	generatorfakes "code.cloudfoundry.org/quarks-secret/pkg/credsgen/fakes"
+	inmemorygenerator "code.cloudfoundry.org/quarks-secret/pkg/credsgen/in_memory_generator"
 	qsv1a1 "code.cloudfoundry.org/quarks-secret/pkg/kube/apis/quarkssecret/v1alpha1"
 	"code.cloudfoundry.org/quarks-secret/pkg/kube/client/clientset/versioned/scheme"
 	"code.cloudfoundry.org/quarks-secret/pkg/kube/controllers"
@@ -29,6 +34,7 @@ import (
 	qscontroller "code.cloudfoundry.org/quarks-secret/pkg/kube/controllers/quarkssecret"
 	cfcfg "code.cloudfoundry.org/quarks-utils/pkg/config"
 	"code.cloudfoundry.org/quarks-utils/pkg/ctxlog"
+	"code.cloudfoundry.org/quarks-utils/pkg/pointers"
 	helper "code.cloudfoundry.org/quarks-utils/testing/testhelper"
 )
 
@@ -106,6 +112,37 @@ var _ = Describe("ReconcileQuarksSecret", func() {
 		})
 	})
 
+	Context("when the quarks secret is paused", func() {
+		It("defers the generation", func() {
+			qSecret.Spec.Paused = true
+			statusWriter := &cfakes.FakeStatusWriter{}
+			client.StatusCalls(func() crc.StatusWriter { return statusWriter })
+
+			_, err := reconciler.Reconcile(context.Background(), request)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(generator.GeneratePasswordCallCount()).To(Equal(0))
+			Expect(client.CreateCallCount()).To(Equal(0))
+
+			Expect(statusWriter.UpdateCallCount()).To(Equal(1))
+			_, object, _ := statusWriter.UpdateArgsForCall(0)
+			status := object.(*qsv1a1.QuarksSecret).Status
+			Expect(status.PendingWork).To(ConsistOf(qsv1a1.WorkGeneration))
+			Expect(meta.IsStatusConditionTrue(status.Conditions, qsv1a1.ConditionPaused)).To(BeTrue())
+		})
+
+		It("returns an error, if the deferred work can't be recorded", func() {
+			qSecret.Spec.Paused = true
+			statusWriter := &cfakes.FakeStatusWriter{}
+			statusWriter.UpdateReturns(errors.NewConflict(schema.GroupResource{}, "foo", fmt.Errorf("conflict")))
+			client.StatusCalls(func() crc.StatusWriter { return statusWriter })
+
+			_, err := reconciler.Reconcile(context.Background(), request)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("could not defer generation"))
+			Expect(generator.GeneratePasswordCallCount()).To(Equal(0))
+		})
+	})
+
 	Context("when generating passwords", func() {
 		BeforeEach(func() {
 			generator.GeneratePasswordReturns("securepassword")
@@ -140,6 +177,42 @@ var _ = Describe("ReconcileQuarksSecret", func() {
 			Expect(reconcile.Result{}).To(Equal(result))
 		})
 
+		It("keeps keys, which were added to the generated secret by others", func() {
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:            "generated-secret",
+					Namespace:       "default",
+					ResourceVersion: "1",
+					Labels:          map[string]string{qsv1a1.LabelKind: qsv1a1.GeneratedSecretKind},
+				},
+				Data: map[string][]byte{
+					"password": []byte("foo"),
+					"extra":    []byte("added"),
+				},
+			}
+			client.GetCalls(func(context context.Context, nn types.NamespacedName, object crc.Object) error {
+				switch object := object.(type) {
+				case *qsv1a1.QuarksSecret:
+					qSecret.DeepCopyInto(object)
+				case *corev1.Secret:
+					if nn.Name == "generated-secret" {
+						secret.DeepCopyInto(object)
+						return nil
+					}
+					return errors.NewNotFound(schema.GroupResource{}, "not found")
+				}
+				return nil
+			})
+
+			_, err := reconciler.Reconcile(context.Background(), request)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(client.UpdateCallCount()).To(Equal(1))
+			_, object, _ := client.UpdateArgsForCall(0)
+			updated := object.(*corev1.Secret)
+			Expect(updated.Data).To(HaveKeyWithValue("extra", []byte("added")))
+			Expect(updated.StringData).To(HaveKeyWithValue("password", "securepassword"))
+		})
+
 		It("generates passwords", func() {
 			client.CreateCalls(func(context context.Context, object crc.Object, _ ...crc.CreateOption) error {
 				secret := object.(*corev1.Secret)
@@ -158,6 +231,92 @@ var _ = Describe("ReconcileQuarksSecret", func() {
 		})
 	})
 
+	Context("when the drift policy is restore", func() {
+		var (
+			backup     *corev1.Secret
+			referenced []string
+		)
+
+		BeforeEach(func() {
+			qSecret.UID = "foo-uid"
+			qSecret.Spec.DriftPolicy = qsv1a1.DriftRestore
+			generator.GeneratePasswordReturns("securepassword")
+
+			backup = nil
+			referenced = []string{}
+			setReferenceFunc = func(owner, object metav1.Object, scheme *runtime.Scheme) error {
+				referenced = append(referenced, object.GetName())
+				return nil
+			}
+
+			client.GetCalls(func(context context.Context, nn types.NamespacedName, object crc.Object) error {
+				switch object := object.(type) {
+				case *qsv1a1.QuarksSecret:
+					qSecret.DeepCopyInto(object)
+					return nil
+				case *corev1.Secret:
+					if nn.Name == "generated-secret-backup" && backup != nil {
+						backup.DeepCopyInto(object)
+						return nil
+					}
+				}
+				return errors.NewNotFound(schema.GroupResource{}, "not found")
+			})
+		})
+
+		AfterEach(func() {
+			setReferenceFunc = func(owner, object metav1.Object, scheme *runtime.Scheme) error { return nil }
+		})
+
+		It("stores a backup, which is controlled by the quarks secret", func() {
+			_, err := reconciler.Reconcile(context.Background(), request)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(client.CreateCallCount()).To(Equal(2))
+			_, object, _ := client.CreateArgsForCall(1)
+			Expect(object.GetName()).To(Equal("generated-secret-backup"))
+			Expect(object.GetLabels()).To(HaveKeyWithValue(qsv1a1.LabelKind, qsv1a1.BackupSecretKind))
+			Expect(referenced).To(ContainElement("generated-secret-backup"))
+		})
+
+		It("sets the reference, when it updates an existing backup", func() {
+			backup = &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:            "generated-secret-backup",
+					Namespace:       "default",
+					ResourceVersion: "1",
+					Labels:          map[string]string{qsv1a1.LabelKind: qsv1a1.BackupSecretKind},
+					OwnerReferences: []metav1.OwnerReference{
+						{Kind: "QuarksSecret", Name: "foo", UID: "foo-uid", Controller: pointers.Bool(true)},
+					},
+				},
+			}
+
+			_, err := reconciler.Reconcile(context.Background(), request)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(client.UpdateCallCount()).To(Equal(1))
+			_, object, _ := client.UpdateArgsForCall(0)
+			Expect(object.GetName()).To(Equal("generated-secret-backup"))
+			Expect(referenced).To(ContainElement("generated-secret-backup"))
+		})
+
+		It("doesn't overwrite secrets, which are not a backup of the quarks secret", func() {
+			backup = &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:            "generated-secret-backup",
+					Namespace:       "default",
+					ResourceVersion: "1",
+				},
+				Data: map[string][]byte{"user": []byte("data")},
+			}
+
+			_, err := reconciler.Reconcile(context.Background(), request)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("is not a backup of QuarksSecret 'default/foo'"))
+			Expect(client.UpdateCallCount()).To(Equal(0))
+			Expect(referenced).ToNot(ContainElement("generated-secret-backup"))
+		})
+	})
+
 	Context("when generating RSA keys", func() {
 		BeforeEach(func() {
 			qSecret.Spec.Type = "rsa"
@@ -278,6 +437,152 @@ var _ = Describe("ReconcileQuarksSecret", func() {
 		})
 	})
 
+	Context("when generating templated configs", func() {
+		var (
+			passSec      *corev1.Secret
+			statusWriter *cfakes.FakeStatusWriter
+		)
+
+		BeforeEach(func() {
+			qSecret.Spec.Type = qsv1a1.TemplatedConfig
+			qSecret.Spec.Request.TemplatedConfigRequest = qsv1a1.TemplatedConfigRequest{
+				Type:      qscontroller.HelmTemplate,
+				Templates: map[string]string{"uri": "postgres://admin:{{ .Values.password }}@db"},
+				Values:    map[string]qsv1a1.SecretReference{"password": {Name: "mypassword", Key: "password"}},
+			}
+
+			passSec = &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "mypassword",
+					Namespace: "default",
+				},
+				Data: map[string][]byte{
+					"password": []byte("secret1"),
+				},
+			}
+
+			client.GetCalls(func(context context.Context, nn types.NamespacedName, object crc.Object) error {
+				switch object := object.(type) {
+				case *qsv1a1.QuarksSecret:
+					qSecret.DeepCopyInto(object)
+				case *corev1.Secret:
+					if nn.Name == "mypassword" {
+						passSec.DeepCopyInto(object)
+					} else {
+						return errors.NewNotFound(schema.GroupResource{}, "not found is requeued")
+					}
+				}
+				return nil
+			})
+
+			statusWriter = &cfakes.FakeStatusWriter{}
+			statusWriter.UpdateCalls(func(_ context.Context, object crc.Object, _ ...crc.UpdateOption) error {
+				object.(*qsv1a1.QuarksSecret).Status.DeepCopyInto(&qSecret.Status)
+				return nil
+			})
+			client.StatusCalls(func() crc.StatusWriter { return statusWriter })
+		})
+
+		It("renders the referenced secret values", func() {
+			client.CreateCalls(func(context context.Context, object crc.Object, _ ...crc.CreateOption) error {
+				secret := object.(*corev1.Secret)
+				Expect(secret.StringData["uri"]).To(Equal("postgres://admin:secret1@db"))
+				return nil
+			})
+
+			result, err := reconciler.Reconcile(context.Background(), request)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(client.CreateCallCount()).To(Equal(1))
+			Expect(reconcile.Result{}).To(Equal(result))
+			Expect(qSecret.Status.IsGenerated()).To(BeTrue())
+			Expect(qSecret.Status.InputsHash).ToNot(BeEmpty())
+		})
+
+		It("skips rendering if the inputs did not change", func() {
+			_, err := reconciler.Reconcile(context.Background(), request)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(client.CreateCallCount()).To(Equal(1))
+
+			_, err = reconciler.Reconcile(context.Background(), request)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(client.CreateCallCount()).To(Equal(1))
+			Expect(statusWriter.UpdateCallCount()).To(Equal(1))
+		})
+
+		It("renders again when a referenced secret changed", func() {
+			_, err := reconciler.Reconcile(context.Background(), request)
+			Expect(err).ToNot(HaveOccurred())
+			hash := qSecret.Status.InputsHash
+
+			passSec.Data["password"] = []byte("secret2")
+			client.CreateCalls(func(context context.Context, object crc.Object, _ ...crc.CreateOption) error {
+				secret := object.(*corev1.Secret)
+				Expect(secret.StringData["uri"]).To(Equal("postgres://admin:secret2@db"))
+				return nil
+			})
+
+			_, err = reconciler.Reconcile(context.Background(), request)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(client.CreateCallCount()).To(Equal(2))
+			Expect(qSecret.Status.InputsHash).ToNot(Equal(hash))
+		})
+
+		Context("when the referenced secret is in another namespace", func() {
+			var platform *corev1.Namespace
+
+			BeforeEach(func() {
+				qSecret.Spec.Request.TemplatedConfigRequest.Values = map[string]qsv1a1.SecretReference{
+					"password": {Name: "mypassword", Key: "password", Namespace: "platform"},
+				}
+				passSec.Namespace = "platform"
+				platform = &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "platform"}}
+
+				client.GetCalls(func(context context.Context, nn types.NamespacedName, object crc.Object) error {
+					switch object := object.(type) {
+					case *qsv1a1.QuarksSecret:
+						qSecret.DeepCopyInto(object)
+					case *corev1.Namespace:
+						platform.DeepCopyInto(object)
+					case *corev1.Secret:
+						if nn.Name == "mypassword" && nn.Namespace == "platform" {
+							passSec.DeepCopyInto(object)
+						} else {
+							return errors.NewNotFound(schema.GroupResource{}, "not found is requeued")
+						}
+					}
+					return nil
+				})
+			})
+
+			It("renders the value, if the secret allows references from the namespace", func() {
+				passSec.Annotations = map[string]string{qsv1a1.AnnotationAllowReferencesFrom: "other, default"}
+
+				_, err := reconciler.Reconcile(context.Background(), request)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(client.CreateCallCount()).To(Equal(1))
+				_, object, _ := client.CreateArgsForCall(0)
+				Expect(object.(*corev1.Secret).StringData["uri"]).To(Equal("postgres://admin:secret1@db"))
+			})
+
+			It("renders the value, if the namespace of the secret allows references from all namespaces", func() {
+				platform.Annotations = map[string]string{qsv1a1.AnnotationAllowReferencesFrom: "*"}
+
+				_, err := reconciler.Reconcile(context.Background(), request)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(client.CreateCallCount()).To(Equal(1))
+			})
+
+			It("fails, if neither the secret nor its namespace allow the reference", func() {
+				passSec.Annotations = map[string]string{qsv1a1.AnnotationAllowReferencesFrom: "other"}
+
+				_, err := reconciler.Reconcile(context.Background(), request)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("doesn't allow references from namespace 'default'"))
+				Expect(client.CreateCallCount()).To(Equal(0))
+			})
+		})
+	})
+
 	Context("when generating certificates", func() {
 		BeforeEach(func() {
 			qSecret.Spec.Type = "certificate"
@@ -403,63 +708,355 @@ var _ = Describe("ReconcileQuarksSecret", func() {
 				})
 			})
 		})
-	})
 
-	Context("when generating tls secret", func() {
-		BeforeEach(func() {
-			qSecret.Spec.Type = "tls"
-			qSecret.Spec.Request.CertificateRequest.CommonName = "foo.com"
-			qSecret.Spec.Request.CertificateRequest.AlternativeNames = []string{"bar.com", "baz.com"}
-		})
+		Context("if the CA is a cluster quarks secret", func() {
+			var cqsec *qsv1a1.ClusterQuarksSecret
 
-		Context("when using a local signer", func() {
 			BeforeEach(func() {
-				qSecret.Spec.Request.CertificateRequest.SignerType = qsv1a1.LocalSigner
-				qSecret.Spec.Request.CertificateRequest.CARef = qsv1a1.SecretReference{Name: "root_ca", Key: "ca"}
-				qSecret.Spec.Request.CertificateRequest.CAKeyRef = qsv1a1.SecretReference{Name: "root_ca", Key: "key"}
+				config.OperatorNamespace = "quarks"
+				qSecret.Spec.Request.CertificateRequest.CARef = qsv1a1.SecretReference{}
+				qSecret.Spec.Request.CertificateRequest.CAKeyRef = qsv1a1.SecretReference{}
+				qSecret.Spec.Request.CertificateRequest.ClusterCARef = "platform-ca"
+
+				cqsec = &qsv1a1.ClusterQuarksSecret{
+					ObjectMeta: metav1.ObjectMeta{Name: "platform-ca"},
+					Spec: qsv1a1.QuarksSecretSpec{
+						Type:       qsv1a1.Certificate,
+						SecretName: "platform-ca",
+						Request: qsv1a1.Request{
+							CertificateRequest: qsv1a1.CertificateRequest{IsCA: true},
+						},
+					},
+				}
+				ca := &corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{Name: "platform-ca", Namespace: "quarks"},
+					Data: map[string][]byte{
+						"certificate": []byte("theca"),
+						"private_key": []byte("the_private_key"),
+					},
+				}
+
+				client.GetCalls(func(context context.Context, nn types.NamespacedName, object crc.Object) error {
+					switch object := object.(type) {
+					case *qsv1a1.QuarksSecret:
+						qSecret.DeepCopyInto(object)
+					case *qsv1a1.ClusterQuarksSecret:
+						cqsec.DeepCopyInto(object)
+					case *corev1.Secret:
+						if nn.Name == "platform-ca" && nn.Namespace == "quarks" {
+							ca.DeepCopyInto(object)
+						} else {
+							return errors.NewNotFound(schema.GroupResource{}, "not found is requeued")
+						}
+					}
+					return nil
+				})
 			})
 
-			Context("if the CA is not ready", func() {
-				It("requeues generation", func() {
-					result, err := reconciler.Reconcile(context.Background(), request)
-					Expect(err).ToNot(HaveOccurred())
-					Expect(client.CreateCallCount()).To(Equal(0))
-					Expect(result.RequeueAfter).To(Equal(time.Second * 5))
+			It("signs with the CA from the operator namespace, without copying its key", func() {
+				generator.GenerateCertificateCalls(func(name string, request credsgen.CertificateGenerationRequest) (credsgen.Certificate, error) {
+					Expect(request.CA.Certificate).To(Equal([]byte("theca")))
+					Expect(request.CA.PrivateKey).To(Equal([]byte("the_private_key")))
+					return credsgen.Certificate{Certificate: []byte("the_cert"), PrivateKey: []byte("private_key")}, nil
 				})
+
+				result, err := reconciler.Reconcile(context.Background(), request)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(reconcile.Result{}).To(Equal(result))
+				Expect(client.CreateCallCount()).To(Equal(1))
+				_, object, _ := client.CreateArgsForCall(0)
+				secret := object.(*corev1.Secret)
+				Expect(secret.Namespace).To(Equal("default"))
+				Expect(secret.StringData["ca"]).To(Equal("theca"))
+				Expect(secret.StringData).ToNot(ContainElement("the_private_key"))
 			})
 
-			Context("if the CA is ready", func() {
+			It("requeues generation, if the cluster quarks secret wasn't generated yet", func() {
+				cqsec.Spec.SecretName = "missing"
 
-				var (
-					generatedCert       string
-					generatedPrivateKey string
-				)
+				result, err := reconciler.Reconcile(context.Background(), request)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(client.CreateCallCount()).To(Equal(0))
+				Expect(reconcile.Result{RequeueAfter: time.Second * 5}).To(Equal(result))
+			})
 
-				BeforeEach(func() {
-					generatedCert = "the_cert-" + uniuri.NewLen(5)
-					generatedPrivateKey = "private_key-" + uniuri.NewLen(5)
+			It("fails if the cluster quarks secret is not a CA", func() {
+				cqsec.Spec.Request.CertificateRequest.IsCA = false
 
-					ca := &corev1.Secret{
-						ObjectMeta: metav1.ObjectMeta{
-							Name:      "root_ca",
-							Namespace: "default",
-						},
-						Data: map[string][]byte{
-							"ca":  []byte("the_ca"),
-							"key": []byte("ca_private_key"),
-						},
-					}
+				_, err := reconciler.Reconcile(context.Background(), request)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("is not a CA certificate"))
+				Expect(client.CreateCallCount()).To(Equal(0))
+			})
 
-					client.GetCalls(func(context context.Context, nn types.NamespacedName, object crc.Object) error {
-						switch object := object.(type) {
-						case *qsv1a1.QuarksSecret:
-							if nn.Name == "foo" {
-								qSecret.DeepCopyInto(object)
-							}
-						case *corev1.Secret:
-							if nn.Name == "root_ca" {
-								ca.DeepCopyInto(object)
-							}
+			It("fails if a CA certificate is requested", func() {
+				qSecret.Spec.Request.CertificateRequest.IsCA = true
+
+				_, err := reconciler.Reconcile(context.Background(), request)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("can't sign CA certificates"))
+				Expect(generator.GenerateCertificateCallCount()).To(Equal(0))
+				Expect(client.CreateCallCount()).To(Equal(0))
+			})
+
+			It("fails if signing usages are requested", func() {
+				qSecret.Spec.Request.CertificateRequest.Usages = []certv1.KeyUsage{certv1.UsageServerAuth, certv1.UsageCertSign}
+
+				_, err := reconciler.Reconcile(context.Background(), request)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("usage 'cert sign' is not allowed"))
+				Expect(generator.GenerateCertificateCallCount()).To(Equal(0))
+			})
+
+			It("fails if CARef is set, too", func() {
+				qSecret.Spec.Request.CertificateRequest.CARef = qsv1a1.SecretReference{Name: "mysecret", Key: "ca"}
+
+				_, err := reconciler.Reconcile(context.Background(), request)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("can't be used together"))
+			})
+		})
+
+		Context("if the certificate references an issuer", func() {
+			var (
+				issuer        *qsv1a1.QuarksIssuer
+				clusterIssuer *qsv1a1.ClusterQuarksIssuer
+			)
+
+			BeforeEach(func() {
+				qSecret.Spec.Request.CertificateRequest.CARef = qsv1a1.SecretReference{}
+				qSecret.Spec.Request.CertificateRequest.CAKeyRef = qsv1a1.SecretReference{}
+				qSecret.Spec.Request.CertificateRequest.IssuerRef = &qsv1a1.IssuerReference{Name: "internal"}
+
+				issuer = &qsv1a1.QuarksIssuer{
+					ObjectMeta: metav1.ObjectMeta{Name: "internal", Namespace: "default"},
+					Spec: qsv1a1.QuarksIssuerSpec{
+						CARef:    qsv1a1.SecretReference{Name: "mysecret", Key: "ca"},
+						CAKeyRef: qsv1a1.SecretReference{Name: "mysecret", Key: "key"},
+						Duration: &metav1.Duration{Duration: 72 * time.Hour},
+						Usages:   []certv1.KeyUsage{certv1.UsageServerAuth},
+					},
+				}
+				clusterIssuer = &qsv1a1.ClusterQuarksIssuer{
+					ObjectMeta: metav1.ObjectMeta{Name: "internal"},
+					Spec:       issuer.Spec,
+				}
+				ca := &corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{Name: "mysecret", Namespace: "default"},
+					Data: map[string][]byte{
+						"ca":  []byte("theca"),
+						"key": []byte("the_private_key"),
+					},
+				}
+
+				client.GetCalls(func(context context.Context, nn types.NamespacedName, object crc.Object) error {
+					switch object := object.(type) {
+					case *qsv1a1.QuarksSecret:
+						qSecret.DeepCopyInto(object)
+					case *qsv1a1.QuarksIssuer:
+						issuer.DeepCopyInto(object)
+					case *qsv1a1.ClusterQuarksIssuer:
+						clusterIssuer.DeepCopyInto(object)
+					case *corev1.Secret:
+						if nn.Name == "mysecret" {
+							ca.DeepCopyInto(object)
+						} else {
+							return errors.NewNotFound(schema.GroupResource{}, "not found is requeued")
+						}
+					}
+					return nil
+				})
+				generator.GenerateCertificateReturns(credsgen.Certificate{Certificate: []byte("the_cert"), PrivateKey: []byte("private_key")}, nil)
+			})
+
+			It("signs with the issuer's CA and applies its defaults", func() {
+				result, err := reconciler.Reconcile(context.Background(), request)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(reconcile.Result{}).To(Equal(result))
+				Expect(client.CreateCallCount()).To(Equal(1))
+
+				_, generationRequest := generator.GenerateCertificateArgsForCall(0)
+				Expect(generationRequest.CA.Certificate).To(Equal([]byte("theca")))
+				Expect(generationRequest.CA.PrivateKey).To(Equal([]byte("the_private_key")))
+				Expect(generationRequest.Duration).To(Equal(72 * time.Hour))
+				Expect(generationRequest.Usages).To(Equal([]string{"server auth"}))
+			})
+
+			It("prefers the duration and usages of the certificate", func() {
+				qSecret.Spec.Request.CertificateRequest.Duration = &metav1.Duration{Duration: time.Hour}
+				qSecret.Spec.Request.CertificateRequest.Usages = []certv1.KeyUsage{certv1.UsageClientAuth}
+
+				_, err := reconciler.Reconcile(context.Background(), request)
+				Expect(err).ToNot(HaveOccurred())
+
+				_, generationRequest := generator.GenerateCertificateArgsForCall(0)
+				Expect(generationRequest.Duration).To(Equal(time.Hour))
+				Expect(generationRequest.Usages).To(Equal([]string{"client auth"}))
+			})
+
+			It("fails if the certificate configures a signer, too", func() {
+				qSecret.Spec.Request.CertificateRequest.CARef = qsv1a1.SecretReference{Name: "mysecret", Key: "ca"}
+
+				_, err := reconciler.Reconcile(context.Background(), request)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("issuerRef can't be combined"))
+				Expect(client.CreateCallCount()).To(Equal(0))
+			})
+
+			It("fails if the issuer's cluster CA should sign a CA certificate", func() {
+				qSecret.Spec.Request.CertificateRequest.IssuerRef.Kind = qsv1a1.ClusterQuarksIssuerKind
+				qSecret.Spec.Request.CertificateRequest.IsCA = true
+				clusterIssuer.Spec = qsv1a1.QuarksIssuerSpec{ClusterCARef: "platform-ca"}
+
+				_, err := reconciler.Reconcile(context.Background(), request)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("can't sign CA certificates"))
+				Expect(generator.GenerateCertificateCallCount()).To(Equal(0))
+			})
+
+			It("fails if the issuer's usages allow signing with its cluster CA", func() {
+				qSecret.Spec.Request.CertificateRequest.IssuerRef.Kind = qsv1a1.ClusterQuarksIssuerKind
+				clusterIssuer.Spec = qsv1a1.QuarksIssuerSpec{ClusterCARef: "platform-ca", Usages: []certv1.KeyUsage{certv1.UsageCRLSign}}
+
+				_, err := reconciler.Reconcile(context.Background(), request)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("usage 'crl sign' is not allowed"))
+				Expect(generator.GenerateCertificateCallCount()).To(Equal(0))
+			})
+
+			It("doesn't resolve the CA of cluster issuers in the certificate's namespace", func() {
+				qSecret.Spec.Request.CertificateRequest.IssuerRef.Kind = qsv1a1.ClusterQuarksIssuerKind
+
+				_, err := reconciler.Reconcile(context.Background(), request)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("can't use CARef"))
+				Expect(client.CreateCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("if the certificate is signed by the cluster signer", func() {
+			var created []crc.Object
+
+			BeforeEach(func() {
+				qSecret.Spec.Request.CertificateRequest.CARef = qsv1a1.SecretReference{}
+				qSecret.Spec.Request.CertificateRequest.CAKeyRef = qsv1a1.SecretReference{}
+				qSecret.Spec.Request.CertificateRequest.SignerType = qsv1a1.ClusterSigner
+
+				client.GetCalls(func(context context.Context, nn types.NamespacedName, object crc.Object) error {
+					if qsec, ok := object.(*qsv1a1.QuarksSecret); ok {
+						qSecret.DeepCopyInto(qsec)
+						return nil
+					}
+					return errors.NewNotFound(schema.GroupResource{}, nn.Name)
+				})
+				created = []crc.Object{}
+				client.CreateCalls(func(context context.Context, object crc.Object, _ ...crc.CreateOption) error {
+					created = append(created, object)
+					return nil
+				})
+				generator.GenerateCertificateSigningRequestReturns([]byte("the_csr"), []byte("the_key"), nil)
+			})
+
+			It("creates a v1 CSR for the signer, which requests the duration", func() {
+				qSecret.Spec.Request.CertificateRequest.SignerName = "example.com/signer"
+				qSecret.Spec.Request.CertificateRequest.Duration = &metav1.Duration{Duration: time.Hour}
+
+				_, err := reconciler.Reconcile(context.Background(), request)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(created).To(HaveLen(2))
+
+				csr := created[1].(*unstructured.Unstructured)
+				Expect(csr.GetAPIVersion()).To(Equal("certificates.k8s.io/v1"))
+				Expect(csr.Object["spec"]).To(HaveKeyWithValue("signerName", "example.com/signer"))
+				Expect(csr.Object["spec"]).To(HaveKeyWithValue("expirationSeconds", int64(3600)))
+				Expect(csr.Object["spec"]).To(HaveKeyWithValue("usages", []interface{}{"digital signature", "key encipherment"}))
+			})
+
+			It("creates a v1beta1 CSR for the legacy signer without a signer name", func() {
+				_, err := reconciler.Reconcile(context.Background(), request)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(created).To(HaveLen(2))
+
+				csr := created[1].(*certv1beta1.CertificateSigningRequest)
+				Expect(csr.Spec.SignerName).To(BeNil())
+				Expect(csr.Spec.Request).To(Equal([]byte("the_csr")))
+			})
+
+			It("falls back to v1beta1, if the cluster doesn't serve v1", func() {
+				qSecret.Spec.Request.CertificateRequest.SignerName = "example.com/signer"
+				client.CreateCalls(func(context context.Context, object crc.Object, _ ...crc.CreateOption) error {
+					if _, ok := object.(*certv1.CertificateSigningRequest); ok {
+						return &meta.NoKindMatchError{GroupKind: schema.GroupKind{Group: "certificates.k8s.io", Kind: "CertificateSigningRequest"}}
+					}
+					created = append(created, object)
+					return nil
+				})
+
+				_, err := reconciler.Reconcile(context.Background(), request)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(created).To(HaveLen(2))
+
+				csr := created[1].(*certv1beta1.CertificateSigningRequest)
+				Expect(*csr.Spec.SignerName).To(Equal("example.com/signer"))
+			})
+		})
+	})
+
+	Context("when generating tls secret", func() {
+		BeforeEach(func() {
+			qSecret.Spec.Type = "tls"
+			qSecret.Spec.Request.CertificateRequest.CommonName = "foo.com"
+			qSecret.Spec.Request.CertificateRequest.AlternativeNames = []string{"bar.com", "baz.com"}
+		})
+
+		Context("when using a local signer", func() {
+			BeforeEach(func() {
+				qSecret.Spec.Request.CertificateRequest.SignerType = qsv1a1.LocalSigner
+				qSecret.Spec.Request.CertificateRequest.CARef = qsv1a1.SecretReference{Name: "root_ca", Key: "ca"}
+				qSecret.Spec.Request.CertificateRequest.CAKeyRef = qsv1a1.SecretReference{Name: "root_ca", Key: "key"}
+			})
+
+			Context("if the CA is not ready", func() {
+				It("requeues generation", func() {
+					result, err := reconciler.Reconcile(context.Background(), request)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(client.CreateCallCount()).To(Equal(0))
+					Expect(result.RequeueAfter).To(Equal(time.Second * 5))
+				})
+			})
+
+			Context("if the CA is ready", func() {
+
+				var (
+					generatedCert       string
+					generatedPrivateKey string
+				)
+
+				BeforeEach(func() {
+					generatedCert = "the_cert-" + uniuri.NewLen(5)
+					generatedPrivateKey = "private_key-" + uniuri.NewLen(5)
+
+					ca := &corev1.Secret{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      "root_ca",
+							Namespace: "default",
+						},
+						Data: map[string][]byte{
+							"ca":  []byte("the_ca"),
+							"key": []byte("ca_private_key"),
+						},
+					}
+
+					client.GetCalls(func(context context.Context, nn types.NamespacedName, object crc.Object) error {
+						switch object := object.(type) {
+						case *qsv1a1.QuarksSecret:
+							if nn.Name == "foo" {
+								qSecret.DeepCopyInto(object)
+							}
+						case *corev1.Secret:
+							if nn.Name == "root_ca" {
+								ca.DeepCopyInto(object)
+							}
 							if nn.Name == "generated-secret" {
 								return errors.NewNotFound(schema.GroupResource{}, "not found")
 							}
@@ -685,4 +1282,500 @@ var _ = Describe("ReconcileQuarksSecret", func() {
 
 		})
 	})
+
+	Context("when setting status conditions", func() {
+		var statusWriter *cfakes.FakeStatusWriter
+
+		// condition returns the condition from the last status update
+		condition := func(conditionType string) *metav1.Condition {
+			Expect(statusWriter.UpdateCallCount()).To(BeNumerically(">", 0))
+			_, object, _ := statusWriter.UpdateArgsForCall(statusWriter.UpdateCallCount() - 1)
+			qsec := object.(*qsv1a1.QuarksSecret)
+			Expect(qsec.Status.ObservedGeneration).To(Equal(int64(2)))
+			return meta.FindStatusCondition(qsec.Status.Conditions, conditionType)
+		}
+
+		BeforeEach(func() {
+			qSecret.Generation = 2
+			generator.GeneratePasswordReturns("securepassword")
+			statusWriter = &cfakes.FakeStatusWriter{}
+			client.StatusCalls(func() crc.StatusWriter { return statusWriter })
+		})
+
+		It("is ready once the secret was generated", func() {
+			_, err := reconciler.Reconcile(context.Background(), request)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(condition(qsv1a1.ConditionGenerated).Status).To(Equal(metav1.ConditionTrue))
+			Expect(condition(qsv1a1.ConditionReady).Status).To(Equal(metav1.ConditionTrue))
+		})
+
+		It("reports secrets provided by the user", func() {
+			client.GetCalls(func(context context.Context, nn types.NamespacedName, object crc.Object) error {
+				switch object := object.(type) {
+				case *qsv1a1.QuarksSecret:
+					qSecret.DeepCopyInto(object)
+				case *corev1.Secret:
+					object.Name = nn.Name
+				}
+				return nil
+			})
+
+			_, err := reconciler.Reconcile(context.Background(), request)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(condition(qsv1a1.ConditionGenerated).Reason).To(Equal("UserProvided"))
+			Expect(condition(qsv1a1.ConditionReady).Status).To(Equal(metav1.ConditionTrue))
+		})
+
+		It("reports a missing CA", func() {
+			qSecret.Spec.Type = "certificate"
+			qSecret.Spec.Request.CertificateRequest.CARef = qsv1a1.SecretReference{Name: "mysecret", Key: "ca"}
+
+			_, err := reconciler.Reconcile(context.Background(), request)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(condition(qsv1a1.ConditionCAReady).Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition(qsv1a1.ConditionCAReady).Message).To(Equal("CA secret not found"))
+			Expect(condition(qsv1a1.ConditionReady).Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition(qsv1a1.ConditionReady).Reason).To(Equal("CANotReady"))
+		})
+
+		It("doesn't update the status again, if the conditions didn't change", func() {
+			qSecret.Spec.Type = "certificate"
+			qSecret.Spec.Request.CertificateRequest.CARef = qsv1a1.SecretReference{Name: "mysecret", Key: "ca"}
+			statusWriter.UpdateCalls(func(_ context.Context, object crc.Object, _ ...crc.UpdateOption) error {
+				object.(*qsv1a1.QuarksSecret).Status.DeepCopyInto(&qSecret.Status)
+				return nil
+			})
+
+			_, err := reconciler.Reconcile(context.Background(), request)
+			Expect(err).ToNot(HaveOccurred())
+			_, err = reconciler.Reconcile(context.Background(), request)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(statusWriter.UpdateCallCount()).To(Equal(1))
+		})
+
+		It("reports an invalid type", func() {
+			qSecret.Spec.Type = "foo"
+
+			_, err := reconciler.Reconcile(context.Background(), request)
+			Expect(err).To(HaveOccurred())
+			Expect(condition(qsv1a1.ConditionGenerated).Reason).To(Equal("InvalidType"))
+			Expect(condition(qsv1a1.ConditionReady).Status).To(Equal(metav1.ConditionFalse))
+		})
+	})
+
+	Context("when recording metadata", func() {
+		var (
+			statusWriter *cfakes.FakeStatusWriter
+			inMemory     *inmemorygenerator.InMemoryGenerator
+		)
+
+		// status returns the status of the last status update
+		status := func() qsv1a1.QuarksSecretStatus {
+			Expect(statusWriter.UpdateCallCount()).To(Equal(1))
+			_, object, _ := statusWriter.UpdateArgsForCall(0)
+			return object.(*qsv1a1.QuarksSecret).Status
+		}
+
+		BeforeEach(func() {
+			inMemory = inmemorygenerator.NewInMemoryGenerator(log)
+			statusWriter = &cfakes.FakeStatusWriter{}
+			client.StatusCalls(func() crc.StatusWriter { return statusWriter })
+		})
+
+		It("records the certificate metadata", func() {
+			qSecret.Spec.Type = "certificate"
+			qSecret.Spec.Request.CertificateRequest.CommonName = "example.com"
+			qSecret.Spec.Request.CertificateRequest.AlternativeNames = []string{"foo.example.com", "10.0.0.1"}
+			ca, err := inMemory.GenerateCertificate("ca", credsgen.CertificateGenerationRequest{IsCA: true, CommonName: "ca.example.com"})
+			Expect(err).ToNot(HaveOccurred())
+			generator.GenerateCertificateCalls(func(name string, request credsgen.CertificateGenerationRequest) (credsgen.Certificate, error) {
+				request.CA = ca
+				return inMemory.GenerateCertificate(name, request)
+			})
+
+			_, err = reconciler.Reconcile(context.Background(), request)
+			Expect(err).ToNot(HaveOccurred())
+
+			cert := status().Certificate
+			Expect(cert).ToNot(BeNil())
+			Expect(cert.SerialNumber).ToNot(BeEmpty())
+			Expect(cert.Issuer).To(Equal("CN=ca.example.com"))
+			Expect(cert.AlternativeNames).To(ConsistOf("example.com", "foo.example.com", "10.0.0.1"))
+			Expect(cert.NotAfter.After(cert.NotBefore.Time)).To(BeTrue())
+			Expect(cert.Fingerprint).To(MatchRegexp("^([0-9A-F]{2}:){31}[0-9A-F]{2}$"))
+		})
+
+		It("records the fingerprint of RSA keys", func() {
+			qSecret.Spec.Type = "rsa"
+			generator.GenerateRSAKeyCalls(inMemory.GenerateRSAKey)
+
+			_, err := reconciler.Reconcile(context.Background(), request)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(status().KeyFingerprint).To(HavePrefix("SHA256:"))
+		})
+
+		It("records the fingerprint of SSH keys", func() {
+			qSecret.Spec.Type = "ssh"
+			generator.GenerateSSHKeyCalls(inMemory.GenerateSSHKey)
+
+			_, err := reconciler.Reconcile(context.Background(), request)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(status().KeyFingerprint).To(HavePrefix("SHA256:"))
+		})
+	})
+
+	Context("when adopting existing secrets", func() {
+		var (
+			statusWriter *cfakes.FakeStatusWriter
+			inMemory     *inmemorygenerator.InMemoryGenerator
+			secret       *corev1.Secret
+		)
+
+		// status returns the status of the last status update
+		status := func() qsv1a1.QuarksSecretStatus {
+			Expect(statusWriter.UpdateCallCount()).To(Equal(1))
+			_, object, _ := statusWriter.UpdateArgsForCall(0)
+			return object.(*qsv1a1.QuarksSecret).Status
+		}
+
+		BeforeEach(func() {
+			qSecret.Spec.ExistingSecretPolicy = qsv1a1.ExistingSecretAdopt
+			inMemory = inmemorygenerator.NewInMemoryGenerator(log)
+			secret = &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "generated-secret",
+					Namespace: "default",
+					Labels:    map[string]string{"app": "db"},
+				},
+				Data: map[string][]byte{"password": []byte("handmade")},
+			}
+			client.GetCalls(func(context context.Context, nn types.NamespacedName, object crc.Object) error {
+				switch object := object.(type) {
+				case *qsv1a1.QuarksSecret:
+					qSecret.DeepCopyInto(object)
+				case *corev1.Secret:
+					if nn.Name != "generated-secret" {
+						return errors.NewNotFound(schema.GroupResource{}, "not found")
+					}
+					secret.DeepCopyInto(object)
+				}
+				return nil
+			})
+			statusWriter = &cfakes.FakeStatusWriter{}
+			client.StatusCalls(func() crc.StatusWriter { return statusWriter })
+		})
+
+		It("takes ownership of the secret without changing its values", func() {
+			_, err := reconciler.Reconcile(context.Background(), request)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(generator.GeneratePasswordCallCount()).To(Equal(0))
+			Expect(client.CreateCallCount()).To(Equal(0))
+
+			Expect(client.UpdateCallCount()).To(Equal(1))
+			_, object, _ := client.UpdateArgsForCall(0)
+			adopted := object.(*corev1.Secret)
+			Expect(adopted.Data).To(Equal(map[string][]byte{"password": []byte("handmade")}))
+			Expect(adopted.Labels).To(HaveKeyWithValue(qsv1a1.LabelKind, qsv1a1.GeneratedSecretKind))
+			Expect(adopted.Labels).To(HaveKeyWithValue("app", "db"))
+			Expect(adopted.Labels).To(HaveKeyWithValue("Label", "generated-label"))
+			Expect(adopted.Annotations).To(HaveKey(qsv1a1.AnnotationContentHash))
+
+			Expect(status().IsGenerated()).To(BeTrue())
+			Expect(meta.FindStatusCondition(status().Conditions, qsv1a1.ConditionGenerated).Reason).To(Equal("Adopted"))
+		})
+
+		It("doesn't render an adopted templated config again", func() {
+			qSecret.Spec.Type = qsv1a1.TemplatedConfig
+			qSecret.Spec.Request.TemplatedConfigRequest = qsv1a1.TemplatedConfigRequest{
+				Type:      qscontroller.HelmTemplate,
+				Templates: map[string]string{"uri": "postgres://admin:{{ .Values.password }}@db"},
+				Values:    map[string]qsv1a1.SecretReference{"password": {Name: "mypassword", Key: "password"}},
+			}
+			secret.Data = map[string][]byte{"uri": []byte("postgres://admin:handmade@db")}
+			passSec := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "mypassword", Namespace: "default"},
+				Data:       map[string][]byte{"password": []byte("secret1")},
+			}
+			client.GetCalls(func(context context.Context, nn types.NamespacedName, object crc.Object) error {
+				switch object := object.(type) {
+				case *qsv1a1.QuarksSecret:
+					qSecret.DeepCopyInto(object)
+				case *corev1.Secret:
+					switch nn.Name {
+					case "generated-secret":
+						secret.DeepCopyInto(object)
+					case "mypassword":
+						passSec.DeepCopyInto(object)
+					default:
+						return errors.NewNotFound(schema.GroupResource{}, "not found")
+					}
+				}
+				return nil
+			})
+
+			_, err := reconciler.Reconcile(context.Background(), request)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(client.UpdateCallCount()).To(Equal(1))
+			Expect(status().InputsHash).ToNot(BeEmpty())
+
+			// the next reconcile finds the adopted secret and the stored hash
+			qSecret.Status = status()
+			_, object, _ := client.UpdateArgsForCall(0)
+			object.(*corev1.Secret).DeepCopyInto(secret)
+
+			_, err = reconciler.Reconcile(context.Background(), request)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(client.UpdateCallCount()).To(Equal(1))
+			Expect(client.CreateCallCount()).To(Equal(0))
+		})
+
+		It("regenerates secrets, which were adopted before", func() {
+			secret.Labels[qsv1a1.LabelKind] = qsv1a1.GeneratedSecretKind
+			generator.GeneratePasswordReturns("securepassword")
+
+			_, err := reconciler.Reconcile(context.Background(), request)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(generator.GeneratePasswordCallCount()).To(Equal(1))
+		})
+
+		It("fails if a key is missing", func() {
+			secret.Data = map[string][]byte{"pass": []byte("handmade")}
+
+			_, err := reconciler.Reconcile(context.Background(), request)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("missing key 'password'"))
+			Expect(client.UpdateCallCount()).To(Equal(0))
+			Expect(meta.FindStatusCondition(status().Conditions, qsv1a1.ConditionGenerated).Reason).To(Equal("AdoptionFailed"))
+		})
+
+		It("adopts a certificate, which matches its private key", func() {
+			qSecret.Spec.Type = "certificate"
+			cert, err := inMemory.GenerateCertificate("foo", credsgen.CertificateGenerationRequest{IsCA: true, CommonName: "example.com"})
+			Expect(err).ToNot(HaveOccurred())
+			secret.Data = map[string][]byte{"certificate": cert.Certificate, "private_key": cert.PrivateKey}
+
+			_, err = reconciler.Reconcile(context.Background(), request)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(client.UpdateCallCount()).To(Equal(1))
+			Expect(status().Certificate.Subject).To(Equal("CN=example.com"))
+		})
+
+		It("fails if the certificate doesn't match its private key", func() {
+			qSecret.Spec.Type = "certificate"
+			cert, err := inMemory.GenerateCertificate("foo", credsgen.CertificateGenerationRequest{IsCA: true, CommonName: "example.com"})
+			Expect(err).ToNot(HaveOccurred())
+			other, err := inMemory.GenerateCertificate("bar", credsgen.CertificateGenerationRequest{IsCA: true, CommonName: "example.com"})
+			Expect(err).ToNot(HaveOccurred())
+			secret.Data = map[string][]byte{"certificate": cert.Certificate, "private_key": other.PrivateKey}
+
+			_, err = reconciler.Reconcile(context.Background(), request)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("invalid certificate"))
+			Expect(client.UpdateCallCount()).To(Equal(0))
+		})
+
+		It("adopts matching RSA keys", func() {
+			qSecret.Spec.Type = "rsa"
+			key, err := inMemory.GenerateRSAKey("foo")
+			Expect(err).ToNot(HaveOccurred())
+			secret.Data = map[string][]byte{"private_key": key.PrivateKey, "public_key": key.PublicKey}
+
+			_, err = reconciler.Reconcile(context.Background(), request)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(status().KeyFingerprint).To(HavePrefix("SHA256:"))
+		})
+
+		It("fails if the SSH public key doesn't match the private key", func() {
+			qSecret.Spec.Type = "ssh"
+			key, err := inMemory.GenerateSSHKey("foo")
+			Expect(err).ToNot(HaveOccurred())
+			other, err := inMemory.GenerateSSHKey("bar")
+			Expect(err).ToNot(HaveOccurred())
+			secret.Data = map[string][]byte{"private_key": key.PrivateKey, "public_key": other.PublicKey}
+
+			_, err = reconciler.Reconcile(context.Background(), request)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("public key does not match private key"))
+		})
+	})
+
+	Context("when values are provided", func() {
+		var (
+			statusWriter *cfakes.FakeStatusWriter
+			inMemory     *inmemorygenerator.InMemoryGenerator
+			provided     map[string][]byte
+		)
+
+		// created returns the data of the created secret
+		created := func() map[string]string {
+			Expect(client.CreateCallCount()).To(Equal(1))
+			_, object, _ := client.CreateArgsForCall(0)
+			return object.(*corev1.Secret).StringData
+		}
+
+		BeforeEach(func() {
+			inMemory = inmemorygenerator.NewInMemoryGenerator(log)
+			provided = map[string][]byte{}
+			client.GetCalls(func(context context.Context, nn types.NamespacedName, object crc.Object) error {
+				switch object := object.(type) {
+				case *qsv1a1.QuarksSecret:
+					qSecret.DeepCopyInto(object)
+				case *corev1.Secret:
+					if nn.Name != "provided" {
+						return errors.NewNotFound(schema.GroupResource{}, "not found")
+					}
+					object.Name = nn.Name
+					object.Data = provided
+				}
+				return nil
+			})
+			statusWriter = &cfakes.FakeStatusWriter{}
+			client.StatusCalls(func() crc.StatusWriter { return statusWriter })
+		})
+
+		It("doesn't generate a provided password", func() {
+			provided["pass"] = []byte("handmade")
+			qSecret.Spec.ProvidedValues = map[string]qsv1a1.SecretReference{"password": {Name: "provided", Key: "pass"}}
+
+			_, err := reconciler.Reconcile(context.Background(), request)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(generator.GeneratePasswordCallCount()).To(Equal(0))
+			Expect(created()).To(HaveKeyWithValue("password", "handmade"))
+		})
+
+		It("generates the password for a provided basic-auth username", func() {
+			provided["user"] = []byte("admin")
+			qSecret.Spec.Type = "basic-auth"
+			qSecret.Spec.ProvidedValues = map[string]qsv1a1.SecretReference{"username": {Name: "provided", Key: "user"}}
+			generator.GeneratePasswordReturns("securepassword")
+
+			_, err := reconciler.Reconcile(context.Background(), request)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(generator.GeneratePasswordCallCount()).To(Equal(1))
+			Expect(created()).To(HaveKeyWithValue("username", "admin"))
+			Expect(created()).To(HaveKeyWithValue("password", "securepassword"))
+		})
+
+		It("derives the public key from a provided RSA private key", func() {
+			key, err := inMemory.GenerateRSAKey("foo")
+			Expect(err).ToNot(HaveOccurred())
+			provided["key"] = key.PrivateKey
+			qSecret.Spec.Type = "rsa"
+			qSecret.Spec.ProvidedValues = map[string]qsv1a1.SecretReference{"private_key": {Name: "provided", Key: "key"}}
+
+			_, err = reconciler.Reconcile(context.Background(), request)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(generator.GenerateRSAKeyCallCount()).To(Equal(0))
+			Expect(created()).To(HaveKeyWithValue("private_key", string(key.PrivateKey)))
+			Expect(created()).To(HaveKeyWithValue("public_key", string(key.PublicKey)))
+		})
+
+		It("fails if only the CA of a generated certificate is provided", func() {
+			provided["ca.crt"] = []byte("external-ca")
+			qSecret.Spec.Type = "certificate"
+			qSecret.Spec.ProvidedValues = map[string]qsv1a1.SecretReference{"ca": {Name: "provided", Key: "ca.crt"}}
+
+			_, err := reconciler.Reconcile(context.Background(), request)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("'ca' can only be provided together with the certificate"))
+			Expect(generator.GenerateCertificateCallCount()).To(Equal(0))
+			Expect(client.CreateCallCount()).To(Equal(0))
+		})
+
+		Context("when a certificate is provided with its CA", func() {
+			var ca, cert credsgen.Certificate
+
+			BeforeEach(func() {
+				var err error
+				ca, err = inMemory.GenerateCertificate("ca", credsgen.CertificateGenerationRequest{IsCA: true, CommonName: "ca"})
+				Expect(err).ToNot(HaveOccurred())
+				cert, err = inMemory.GenerateCertificate("leaf", credsgen.CertificateGenerationRequest{CommonName: "example.com", CA: ca})
+				Expect(err).ToNot(HaveOccurred())
+				provided["crt"] = cert.Certificate
+				provided["key"] = cert.PrivateKey
+				provided["ca.crt"] = ca.Certificate
+				qSecret.Spec.Type = "certificate"
+				qSecret.Spec.ProvidedValues = map[string]qsv1a1.SecretReference{
+					"certificate": {Name: "provided", Key: "crt"},
+					"private_key": {Name: "provided", Key: "key"},
+					"ca":          {Name: "provided", Key: "ca.crt"},
+				}
+			})
+
+			It("adds the CA, which signed the certificate", func() {
+				_, err := reconciler.Reconcile(context.Background(), request)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(created()).To(HaveKeyWithValue("ca", string(ca.Certificate)))
+			})
+
+			It("fails if the CA didn't sign the certificate", func() {
+				other, err := inMemory.GenerateCertificate("other", credsgen.CertificateGenerationRequest{IsCA: true, CommonName: "other"})
+				Expect(err).ToNot(HaveOccurred())
+				provided["ca.crt"] = other.Certificate
+
+				_, err = reconciler.Reconcile(context.Background(), request)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("certificate is not signed by the provided 'ca'"))
+				Expect(client.CreateCallCount()).To(Equal(0))
+			})
+		})
+
+		It("writes provided image credentials only to the registry entry", func() {
+			provided["user"] = []byte("admin")
+			provided["pass"] = []byte("handmade")
+			qSecret.Spec.Type = qsv1a1.DockerConfigJSON
+			qSecret.Spec.Request.ImageCredentialsRequest = qsv1a1.ImageCredentialsRequest{Registry: "registry.example.com"}
+			qSecret.Spec.ProvidedValues = map[string]qsv1a1.SecretReference{
+				"username": {Name: "provided", Key: "user"},
+				"password": {Name: "provided", Key: "pass"},
+			}
+
+			_, err := reconciler.Reconcile(context.Background(), request)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(generator.GeneratePasswordCallCount()).To(Equal(0))
+			Expect(created()).To(HaveLen(1))
+			Expect(created()[corev1.DockerConfigJsonKey]).To(ContainSubstring(`"username":"admin","password":"handmade"`))
+		})
+
+		It("doesn't generate a provided certificate", func() {
+			cert, err := inMemory.GenerateCertificate("foo", credsgen.CertificateGenerationRequest{IsCA: true, CommonName: "example.com"})
+			Expect(err).ToNot(HaveOccurred())
+			provided["tls.crt"] = cert.Certificate
+			provided["tls.key"] = cert.PrivateKey
+			qSecret.Spec.Type = "tls"
+			qSecret.Spec.ProvidedValues = map[string]qsv1a1.SecretReference{
+				"tls.crt": {Name: "provided", Key: "tls.crt"},
+				"tls.key": {Name: "provided", Key: "tls.key"},
+			}
+
+			_, err = reconciler.Reconcile(context.Background(), request)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(generator.GenerateCertificateCallCount()).To(Equal(0))
+			Expect(created()).To(HaveKeyWithValue("tls.crt", string(cert.Certificate)))
+			Expect(created()).To(HaveKeyWithValue("tls.key", string(cert.PrivateKey)))
+		})
+
+		It("fails if only the certificate is provided", func() {
+			provided["crt"] = []byte("the_cert")
+			qSecret.Spec.Type = "certificate"
+			qSecret.Spec.ProvidedValues = map[string]qsv1a1.SecretReference{"certificate": {Name: "provided", Key: "crt"}}
+
+			_, err := reconciler.Reconcile(context.Background(), request)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("'certificate' and 'private_key' have to be provided together"))
+			Expect(client.CreateCallCount()).To(Equal(0))
+		})
+
+		It("reports a missing referenced secret", func() {
+			qSecret.Spec.ProvidedValues = map[string]qsv1a1.SecretReference{"password": {Name: "missing", Key: "pass"}}
+
+			_, err := reconciler.Reconcile(context.Background(), request)
+			Expect(err).To(HaveOccurred())
+			Expect(client.CreateCallCount()).To(Equal(0))
+			_, object, _ := statusWriter.UpdateArgsForCall(0)
+			condition := meta.FindStatusCondition(object.(*qsv1a1.QuarksSecret).Status.Conditions, qsv1a1.ConditionDependenciesReady)
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Reason).To(Equal("SecretNotFound"))
+		})
+	})
 })
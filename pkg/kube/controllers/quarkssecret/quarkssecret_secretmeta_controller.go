@@ -46,7 +46,7 @@ func AddQuarksSecretSecretMeta(ctx context.Context, config *config.Config, mgr m
 			n := e.ObjectNew.(*qsv1a1.QuarksSecret)
 			o := e.ObjectOld.(*qsv1a1.QuarksSecret)
 
-			if !reflect.DeepEqual(o.Spec.SecretLabels, n.Spec.SecretLabels) || !reflect.DeepEqual(o.Spec.SecretAnnotations, n.Spec.SecretAnnotations) {
+			if !reflect.DeepEqual(o.Spec.SecretLabels, n.Spec.SecretLabels) || !reflect.DeepEqual(o.Spec.SecretAnnotations, n.Spec.SecretAnnotations) || isResumed(o, n, qsv1a1.WorkSecretMeta) {
 				ctxlog.NewPredicateEvent(e.ObjectNew).Debug(
 					ctx, e.ObjectNew, "qsv1a1.QuarksSecret",
 					fmt.Sprintf("Update predicate passed for '%s/%s'.", e.ObjectNew.GetNamespace(), e.ObjectNew.GetName()),
@@ -62,6 +62,10 @@ func (r *ReconcileQuarksSecretSecretMeta) Reconcile(_ context.Context, request r
 		return reconcile.Result{}, errors.Wrap(err, "Error reading quarksSecret")
 	}
 
+	if quarksSecret.Spec.Paused {
+		return reconcile.Result{}, deferWork(ctx, r.client, quarksSecret, qsv1a1.WorkSecretMeta)
+	}
+
 	secret, err := GetSourceSecret(ctx, r.client, quarksSecret)
 	if err != nil {
 		return reconcile.Result{}, err
@@ -81,6 +85,10 @@ func (r *ReconcileQuarksSecretSecretMeta) Reconcile(_ context.Context, request r
 	if ok {
 		newSecretLabels[qsv1a1.LabelKind] = secret.GetLabels()[qsv1a1.LabelKind]
 	}
+	hash, ok := secret.GetAnnotations()[qsv1a1.AnnotationContentHash]
+	if ok {
+		newSecretAnnotations[qsv1a1.AnnotationContentHash] = hash
+	}
 
 	if !reflect.DeepEqual(newSecretLabels, secret.Labels) || !reflect.DeepEqual(newSecretAnnotations, secret.Annotations) {
 		secret.SetLabels(newSecretLabels)
@@ -102,6 +110,7 @@ func (r *ReconcileQuarksSecretSecretMeta) Reconcile(_ context.Context, request r
 
 func (r *ReconcileQuarksSecretSecretMeta) updateStatus(ctx context.Context, qsec *qsv1a1.QuarksSecret) error {
 	qsec.Status.Copied = pointers.Bool(false)
+	qsec.Status.ObservedGeneration = qsec.Generation
 	err := r.client.Status().Update(ctx, qsec)
 	if err != nil {
 		return err
@@ -0,0 +1,80 @@
+package quarkssecret
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+
+	crc "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	qsv1a1 "code.cloudfoundry.org/quarks-secret/pkg/kube/apis/quarkssecret/v1alpha1"
+	"code.cloudfoundry.org/quarks-utils/pkg/config"
+	"code.cloudfoundry.org/quarks-utils/pkg/ctxlog"
+)
+
+// AddQuarksSecretRotation creates a new QuarksSecretRotation controller to
+// watch for rotation requests and rotate the selected quarks secrets
+func AddQuarksSecretRotation(ctx context.Context, config *config.Config, mgr manager.Manager) error {
+	ctx = ctxlog.NewContextWithRecorder(ctx, "quarks-secret-rotation-reconciler", mgr.GetEventRecorderFor("quarks-secret-rotation-recorder"))
+	r := NewQuarksSecretRotationReconciler(ctx, config, mgr)
+
+	c, err := controller.New("quarks-secret-rotation-controller", mgr, controller.Options{
+		Reconciler:              r,
+		MaxConcurrentReconciles: config.MaxQuarksSecretWorkers,
+	})
+	if err != nil {
+		return errors.Wrap(err, "Adding quarks secret rotation controller to manager failed.")
+	}
+
+	nsPred := newNSPredicate(ctx, mgr.GetClient(), config.MonitoredID)
+
+	// Watch for changes to QuarksSecretRotations, status updates don't
+	// change the generation, except for approvals
+	p := predicate.Funcs{
+		CreateFunc: func(e event.CreateEvent) bool {
+			ctxlog.NewPredicateEvent(e.Object).Debug(
+				ctx, e.Object, "qsv1a1.QuarksSecretRotation",
+				fmt.Sprintf("Create predicate passed for '%s/%s'", e.Object.GetNamespace(), e.Object.GetName()),
+			)
+			return true
+		},
+		DeleteFunc:  func(e event.DeleteEvent) bool { return false },
+		GenericFunc: func(e event.GenericEvent) bool { return false },
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			if e.ObjectOld.GetGeneration() == e.ObjectNew.GetGeneration() && !isApproval(e.ObjectOld, e.ObjectNew) {
+				return false
+			}
+			ctxlog.NewPredicateEvent(e.ObjectNew).Debug(
+				ctx, e.ObjectNew, "qsv1a1.QuarksSecretRotation",
+				fmt.Sprintf("Update predicate passed for '%s/%s'", e.ObjectNew.GetNamespace(), e.ObjectNew.GetName()),
+			)
+			return true
+		},
+	}
+	err = c.Watch(&source.Kind{Type: &qsv1a1.QuarksSecretRotation{}}, &handler.EnqueueRequestForObject{}, nsPred, p)
+	if err != nil {
+		return errors.Wrapf(err, "Watching quarks secret rotations failed in quarks secret rotation controller.")
+	}
+
+	return nil
+}
+
+// isApproval returns true if the update approved the rotation
+func isApproval(oldObj, newObj crc.Object) bool {
+	o, ok := oldObj.(*qsv1a1.QuarksSecretRotation)
+	if !ok {
+		return false
+	}
+	n, ok := newObj.(*qsv1a1.QuarksSecretRotation)
+	if !ok {
+		return false
+	}
+	return !o.Status.Approved && n.Status.Approved
+}
@@ -0,0 +1,146 @@
+package quarkssecret
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	qsv1a1 "code.cloudfoundry.org/quarks-secret/pkg/kube/apis/quarkssecret/v1alpha1"
+	"code.cloudfoundry.org/quarks-utils/pkg/config"
+	"code.cloudfoundry.org/quarks-utils/pkg/ctxlog"
+)
+
+// NewQuarksSecretRotationReconciler returns a new ReconcileQuarksSecretRotation
+func NewQuarksSecretRotationReconciler(ctx context.Context, config *config.Config, mgr manager.Manager) reconcile.Reconciler {
+	return &ReconcileQuarksSecretRotation{
+		ctx:    ctx,
+		config: config,
+		client: mgr.GetClient(),
+	}
+}
+
+// ReconcileQuarksSecretRotation triggers the regeneration of the quarks
+// secrets selected by a QuarksSecretRotation
+type ReconcileQuarksSecretRotation struct {
+	ctx    context.Context
+	client client.Client
+	config *config.Config
+}
+
+// Reconcile plans the rotation of the selected quarks secrets and, unless it
+// is a dry run or waits for approval, rotates them. The outcome is stored in
+// the status of the QuarksSecretRotation.
+func (r *ReconcileQuarksSecretRotation) Reconcile(_ context.Context, request reconcile.Request) (reconcile.Result, error) {
+	rotation := &qsv1a1.QuarksSecretRotation{}
+
+	// Set the ctx to be Background, as the top-level context for incoming requests.
+	ctx, cancel := context.WithTimeout(r.ctx, r.config.CtxTimeOut)
+	defer cancel()
+
+	ctxlog.Infof(ctx, "Reconciling QuarksSecretRotation %s", request.NamespacedName)
+	err := r.client.Get(ctx, request.NamespacedName, rotation)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			ctxlog.Info(ctx, "Skip reconcile: quarks secret rotation not found")
+			return reconcile.Result{}, nil
+		}
+		ctxlog.Info(ctx, "Error reading the object")
+		return reconcile.Result{}, errors.Wrap(err, "Error reading quarksSecretRotation")
+	}
+
+	if rotation.IsCompleted() {
+		ctxlog.Debugf(ctx, "Skip reconcile: QuarksSecretRotation '%s' is already completed", rotation.GetNamespacedName())
+		return reconcile.Result{}, nil
+	}
+
+	selection, err := rotationSelectionFromSpec(rotation.Spec)
+	if err != nil {
+		// retrying won't help, the spec has to change
+		_ = ctxlog.WithEvent(rotation, "InvalidSelector").Errorf(ctx, "Error reading secrets to rotate from '%s': %s", rotation.GetNamespacedName(), err)
+		return reconcile.Result{}, nil
+	}
+
+	targets, err := selectRotationTargets(ctx, r.client, r.config.MonitoredID, rotation, selection)
+	if err != nil {
+		return reconcile.Result{}, errors.Wrapf(err, "Error selecting secrets to rotate for '%s'", rotation.GetNamespacedName())
+	}
+
+	planned := map[string]qsv1a1.RotationResult{}
+	for _, target := range targets {
+		planned[target.key], err = rotateQuarksSecret(ctx, r.client, rotation, target.name, true)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+
+	// an approval of an older spec doesn't approve the current one
+	approved := rotation.IsApproved()
+	rotation.Status.Approved = approved
+	rotation.Status.ObservedGeneration = rotation.Generation
+	rotation.Status.Planned = planned
+	rotation.Status.Results = nil
+	rotation.Status.CompletionTime = nil
+
+	switch {
+	case rotation.Spec.DryRun:
+		rotation.Status.Phase = qsv1a1.RotationPhasePlanned
+		ctxlog.WithEvent(rotation, "RotationPlanned").Infof(ctx, "Dry run of QuarksSecretRotation '%s' selected %d quarks secrets", rotation.GetNamespacedName(), len(planned))
+	case rotation.Spec.RequireApproval && !approved:
+		rotation.Status.Phase = qsv1a1.RotationPhasePendingApproval
+		ctxlog.WithEvent(rotation, "RotationPendingApproval").Infof(ctx, "QuarksSecretRotation '%s' waits for approval", rotation.GetNamespacedName())
+	default:
+		// the rotation is completed anyway, a retry would rotate the
+		// quarks secrets again, which were already rotated
+		results := map[string]qsv1a1.RotationResult{}
+		for _, target := range targets {
+			results[target.key], err = rotateQuarksSecret(ctx, r.client, rotation, target.name, false)
+			if err != nil {
+				results[target.key] = qsv1a1.RotationFailed
+				_ = ctxlog.WithEvent(rotation, "RotationFailed").Errorf(ctx, "Failed to rotate QuarksSecret '%s': %s", target.name.String(), err)
+			}
+		}
+		now := metav1.Now()
+		rotation.Status.Phase = qsv1a1.RotationPhaseCompleted
+		rotation.Status.Results = results
+		rotation.Status.CompletionTime = &now
+	}
+
+	err = r.client.Status().Update(ctx, rotation)
+	if err != nil {
+		return reconcile.Result{}, errors.Wrapf(err, "could not update status of QuarksSecretRotation '%s'", rotation.GetNamespacedName())
+	}
+
+	return reconcile.Result{}, nil
+}
+
+// rotationSelectionFromSpec builds the selection from the spec of the
+// QuarksSecretRotation
+func rotationSelectionFromSpec(spec qsv1a1.QuarksSecretRotationSpec) (rotationSelection, error) {
+	selection := rotationSelection{
+		names:      spec.SecretNames,
+		types:      spec.Types,
+		namespaces: spec.Namespaces,
+	}
+
+	if spec.Selector == nil && len(spec.Types) == 0 {
+		return selection, nil
+	}
+
+	selection.selector = labels.Everything()
+	if spec.Selector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(spec.Selector)
+		if err != nil {
+			return selection, errors.Wrap(err, "invalid label selector")
+		}
+		selection.selector = selector
+	}
+
+	return selection, nil
+}
@@ -0,0 +1,258 @@
+package quarkssecret_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"go.uber.org/zap"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	crc "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	qsv1a1 "code.cloudfoundry.org/quarks-secret/pkg/kube/apis/quarkssecret/v1alpha1"
+	"code.cloudfoundry.org/quarks-secret/pkg/kube/client/clientset/versioned/scheme"
+	"code.cloudfoundry.org/quarks-secret/pkg/kube/controllers"
+	cfakes "code.cloudfoundry.org/quarks-secret/pkg/kube/controllers/fakes"
+	qscontroller "code.cloudfoundry.org/quarks-secret/pkg/kube/controllers/quarkssecret"
+	cfcfg "code.cloudfoundry.org/quarks-utils/pkg/config"
+	"code.cloudfoundry.org/quarks-utils/pkg/ctxlog"
+	"code.cloudfoundry.org/quarks-utils/pkg/pointers"
+	helper "code.cloudfoundry.org/quarks-utils/testing/testhelper"
+)
+
+var _ = Describe("ReconcileQuarksSecretRotation", func() {
+	var (
+		manager      *cfakes.FakeManager
+		reconciler   reconcile.Reconciler
+		request      reconcile.Request
+		ctx          context.Context
+		log          *zap.SugaredLogger
+		config       *cfcfg.Config
+		client       *cfakes.FakeClient
+		statusWriter *cfakes.FakeStatusWriter
+		rotation     *qsv1a1.QuarksSecretRotation
+		qsecs        map[string]*qsv1a1.QuarksSecret
+	)
+
+	// updatedRotation returns the rotation passed to the last status update
+	updatedRotation := func() *qsv1a1.QuarksSecretRotation {
+		for i := statusWriter.UpdateCallCount() - 1; i >= 0; i-- {
+			_, object, _ := statusWriter.UpdateArgsForCall(i)
+			if r, ok := object.(*qsv1a1.QuarksSecretRotation); ok {
+				return r
+			}
+		}
+		Fail("status of rotation was not updated")
+		return nil
+	}
+
+	// rotatedQuarksSecrets returns the number of quarks secret status updates
+	rotatedQuarksSecrets := func() int {
+		n := 0
+		for i := 0; i < statusWriter.UpdateCallCount(); i++ {
+			_, object, _ := statusWriter.UpdateArgsForCall(i)
+			if _, ok := object.(*qsv1a1.QuarksSecret); ok {
+				n++
+			}
+		}
+		return n
+	}
+
+	BeforeEach(func() {
+		err := controllers.AddToScheme(scheme.Scheme)
+		Expect(err).ToNot(HaveOccurred())
+		manager = &cfakes.FakeManager{}
+		request = reconcile.Request{NamespacedName: types.NamespacedName{Name: "rotate", Namespace: "default"}}
+		config = &cfcfg.Config{CtxTimeOut: 10 * time.Second}
+		_, log = helper.NewTestLogger()
+		ctx = ctxlog.NewContextWithRecorder(ctxlog.NewParentContext(log), "TestRecorder", record.NewFakeRecorder(10))
+
+		rotation = &qsv1a1.QuarksSecretRotation{
+			ObjectMeta: metav1.ObjectMeta{Name: "rotate", Namespace: "default", Generation: 1},
+			Spec: qsv1a1.QuarksSecretRotationSpec{
+				SecretNames: []string{"generated", "manual", "missing"},
+			},
+		}
+		qsecs = map[string]*qsv1a1.QuarksSecret{
+			"generated": {
+				ObjectMeta: metav1.ObjectMeta{Name: "generated", Namespace: "default"},
+				Status:     qsv1a1.QuarksSecretStatus{Generated: pointers.Bool(true)},
+			},
+			"manual": {
+				ObjectMeta: metav1.ObjectMeta{Name: "manual", Namespace: "default"},
+				Status:     qsv1a1.QuarksSecretStatus{Generated: pointers.Bool(false)},
+			},
+		}
+
+		client = &cfakes.FakeClient{}
+		client.GetCalls(func(context context.Context, nn types.NamespacedName, object crc.Object) error {
+			switch object := object.(type) {
+			case *qsv1a1.QuarksSecretRotation:
+				rotation.DeepCopyInto(object)
+			case *qsv1a1.QuarksSecret:
+				qsec, ok := qsecs[nn.Name]
+				if !ok {
+					return errors.NewNotFound(schema.GroupResource{}, "not found")
+				}
+				qsec.DeepCopyInto(object)
+			}
+			return nil
+		})
+		client.ListCalls(func(context context.Context, object crc.ObjectList, _ ...crc.ListOption) error {
+			if list, ok := object.(*qsv1a1.QuarksSecretList); ok {
+				for _, qsec := range qsecs {
+					list.Items = append(list.Items, *qsec)
+				}
+			}
+			return nil
+		})
+		statusWriter = &cfakes.FakeStatusWriter{}
+		client.StatusCalls(func() crc.StatusWriter { return statusWriter })
+		manager.GetClientReturns(client)
+	})
+
+	JustBeforeEach(func() {
+		reconciler = qscontroller.NewQuarksSecretRotationReconciler(ctx, config, manager)
+	})
+
+	It("rotates the listed quarks secrets and reports the results", func() {
+		result, err := reconciler.Reconcile(context.Background(), request)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(reconcile.Result{}).To(Equal(result))
+		Expect(rotatedQuarksSecrets()).To(Equal(1))
+
+		r := updatedRotation()
+		Expect(r.Status.Phase).To(Equal(qsv1a1.RotationPhaseCompleted))
+		Expect(r.Status.ObservedGeneration).To(Equal(int64(1)))
+		Expect(r.Status.CompletionTime).ToNot(BeNil())
+		Expect(r.Status.Planned).To(Equal(r.Status.Results))
+		Expect(r.Status.Results).To(Equal(map[string]string{
+			"generated": qsv1a1.RotationRotated,
+			"manual":    qsv1a1.RotationSkippedNotGenerated,
+			"missing":   qsv1a1.RotationNotFound,
+		}))
+	})
+
+	It("records failures and completes the rotation", func() {
+		statusWriter.UpdateCalls(func(_ context.Context, object crc.Object, _ ...crc.UpdateOption) error {
+			if _, ok := object.(*qsv1a1.QuarksSecret); ok {
+				return errors.NewConflict(schema.GroupResource{}, object.GetName(), nil)
+			}
+			return nil
+		})
+
+		_, err := reconciler.Reconcile(context.Background(), request)
+		Expect(err).ToNot(HaveOccurred())
+
+		r := updatedRotation()
+		Expect(r.Status.Phase).To(Equal(qsv1a1.RotationPhaseCompleted))
+		Expect(r.Status.Results).To(HaveKeyWithValue("generated", qsv1a1.RotationFailed))
+	})
+
+	It("only plans the rotation for a dry run", func() {
+		rotation.Spec.DryRun = true
+
+		_, err := reconciler.Reconcile(context.Background(), request)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rotatedQuarksSecrets()).To(Equal(0))
+
+		r := updatedRotation()
+		Expect(r.Status.Phase).To(Equal(qsv1a1.RotationPhasePlanned))
+		Expect(r.Status.Planned).To(HaveKeyWithValue("generated", qsv1a1.RotationRotated))
+		Expect(r.Status.Results).To(BeEmpty())
+	})
+
+	It("waits for approval, if required", func() {
+		rotation.Spec.RequireApproval = true
+
+		_, err := reconciler.Reconcile(context.Background(), request)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rotatedQuarksSecrets()).To(Equal(0))
+		Expect(updatedRotation().Status.Phase).To(Equal(qsv1a1.RotationPhasePendingApproval))
+
+		rotation.Status = updatedRotation().Status
+		rotation.Status.Approved = true
+		_, err = reconciler.Reconcile(context.Background(), request)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rotatedQuarksSecrets()).To(Equal(1))
+		Expect(updatedRotation().Status.Phase).To(Equal(qsv1a1.RotationPhaseCompleted))
+	})
+
+	It("doesn't accept an approval of an older spec", func() {
+		rotation.Spec.RequireApproval = true
+		rotation.Generation = 2
+		rotation.Status.Phase = qsv1a1.RotationPhasePendingApproval
+		rotation.Status.ObservedGeneration = 1
+		rotation.Status.Approved = true
+
+		_, err := reconciler.Reconcile(context.Background(), request)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rotatedQuarksSecrets()).To(Equal(0))
+
+		r := updatedRotation()
+		Expect(r.Status.Phase).To(Equal(qsv1a1.RotationPhasePendingApproval))
+		Expect(r.Status.Approved).To(BeFalse())
+		Expect(r.Status.ObservedGeneration).To(Equal(int64(2)))
+	})
+
+	It("doesn't rotate again after completion", func() {
+		rotation.Status.Phase = qsv1a1.RotationPhaseCompleted
+		rotation.Status.ObservedGeneration = 1
+
+		_, err := reconciler.Reconcile(context.Background(), request)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(statusWriter.UpdateCallCount()).To(Equal(0))
+	})
+
+	It("doesn't rotate again after completion, if the spec changes", func() {
+		rotation.Status.Phase = qsv1a1.RotationPhaseCompleted
+		rotation.Status.ObservedGeneration = 1
+		rotation.Generation = 2
+
+		_, err := reconciler.Reconcile(context.Background(), request)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(statusWriter.UpdateCallCount()).To(Equal(0))
+	})
+
+	It("rotates quarks secrets selected by type", func() {
+		rotation.Spec.SecretNames = nil
+		rotation.Spec.Types = []qsv1a1.SecretType{qsv1a1.Password}
+		qsecs["generated"].Spec.Type = qsv1a1.Password
+		qsecs["manual"].Spec.Type = qsv1a1.Certificate
+
+		_, err := reconciler.Reconcile(context.Background(), request)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(updatedRotation().Status.Results).To(Equal(map[string]string{
+			"generated": qsv1a1.RotationRotated,
+		}))
+	})
+
+	It("doesn't select in namespaces, which don't allow rotations from its namespace", func() {
+		config.MonitoredID = "quarks"
+		rotation.Spec.SecretNames = nil
+		rotation.Spec.Types = []qsv1a1.SecretType{qsv1a1.Password}
+		rotation.Spec.Namespaces = []string{"other"}
+		get := client.GetStub
+		client.GetCalls(func(context context.Context, nn types.NamespacedName, object crc.Object) error {
+			if ns, ok := object.(*corev1.Namespace); ok {
+				ns.Name = nn.Name
+				ns.Labels = map[string]string{qsv1a1.LabelNamespace: "quarks"}
+				return nil
+			}
+			return get(context, nn, object)
+		})
+
+		_, err := reconciler.Reconcile(context.Background(), request)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(client.ListCallCount()).To(Equal(0))
+		Expect(updatedRotation().Status.Results).To(BeEmpty())
+	})
+})
@@ -0,0 +1,246 @@
+package quarkssecret
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	crc "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	qsv1a1 "code.cloudfoundry.org/quarks-secret/pkg/kube/apis/quarkssecret/v1alpha1"
+	"code.cloudfoundry.org/quarks-utils/pkg/ctxlog"
+)
+
+// referencedSecretsIndex is the name of the field index, which maps the names
+// of secrets referenced in a QuarksSecret's request to the QuarksSecret
+const referencedSecretsIndex = "spec.request.secretReferences"
+
+// referencedNamespacesIndex is the name of the field index, which maps the
+// other namespaces, whose secrets are referenced in a QuarksSecret's
+// request, to the QuarksSecret
+const referencedNamespacesIndex = "spec.request.secretReferences.namespace"
+
+// referencedSecretNames returns the names of all secrets whose values are
+// rendered into the generated secret of the QuarksSecret. Secrets in other
+// namespaces are returned as '<namespace>/<name>'.
+// Provided values of other types are only read, when the secret is generated.
+func referencedSecretNames(qsec *qsv1a1.QuarksSecret) []string {
+	names := []string{}
+	switch qsec.Spec.Type {
+	case qsv1a1.TemplatedConfig, qsv1a1.DockerConfigJSON:
+		for _, ref := range qsec.Spec.ProvidedValues {
+			names = append(names, referencedSecretName(qsec.Namespace, ref))
+		}
+	}
+
+	switch qsec.Spec.Type {
+	case qsv1a1.TemplatedConfig:
+		for _, ref := range qsec.Spec.Request.TemplatedConfigRequest.Values {
+			names = append(names, referencedSecretName(qsec.Namespace, ref))
+		}
+	case qsv1a1.DockerConfigJSON:
+		for _, ref := range []qsv1a1.SecretReference{
+			qsec.Spec.Request.ImageCredentialsRequest.Username,
+			qsec.Spec.Request.ImageCredentialsRequest.Password,
+		} {
+			if len(ref.Name) > 0 {
+				names = append(names, referencedSecretName(qsec.Namespace, ref))
+			}
+		}
+	}
+	return names
+}
+
+// referencedSecretName returns the index key of the referenced secret
+func referencedSecretName(namespace string, ref qsv1a1.SecretReference) string {
+	if ns := referencedNamespace(namespace, ref); ns != namespace {
+		return ns + "/" + ref.Name
+	}
+	return ref.Name
+}
+
+// referencedNamespace returns the namespace of the referenced secret
+func referencedNamespace(namespace string, ref qsv1a1.SecretReference) string {
+	if ref.Namespace != "" {
+		return ref.Namespace
+	}
+	return namespace
+}
+
+// indexReferencedNamespaces is the indexer func for referencedNamespacesIndex
+func indexReferencedNamespaces(o crc.Object) []string {
+	qsec, ok := o.(*qsv1a1.QuarksSecret)
+	if !ok {
+		return []string{}
+	}
+
+	namespaces := []string{}
+	seen := map[string]bool{}
+	for _, name := range referencedSecretNames(qsec) {
+		// secrets in other namespaces are indexed as '<namespace>/<name>'
+		if i := strings.Index(name, "/"); i > 0 && !seen[name[:i]] {
+			seen[name[:i]] = true
+			namespaces = append(namespaces, name[:i])
+		}
+	}
+	return namespaces
+}
+
+// indexReferencedSecrets is the indexer func for referencedSecretsIndex
+func indexReferencedSecrets(o crc.Object) []string {
+	qsec, ok := o.(*qsv1a1.QuarksSecret)
+	if !ok {
+		return []string{}
+	}
+	return referencedSecretNames(qsec)
+}
+
+// listReferencingQuarksSecretsReconciles lists all QuarksSecrets in monitored
+// namespaces, which reference the secret in their request.
+func listReferencingQuarksSecretsReconciles(ctx context.Context, client crc.Client, id string, secret *corev1.Secret) ([]reconcile.Request, error) {
+	quarksSecretList := &qsv1a1.QuarksSecretList{}
+	err := client.List(ctx, quarksSecretList,
+		crc.InNamespace(secret.Namespace),
+		crc.MatchingFields{referencedSecretsIndex: secret.Name},
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list QuarksSecrets")
+	}
+
+	// QuarksSecrets in other namespaces reference the secret by its
+	// namespaced name
+	crossNamespaceList := &qsv1a1.QuarksSecretList{}
+	err = client.List(ctx, crossNamespaceList,
+		crc.MatchingFields{referencedSecretsIndex: secret.Namespace + "/" + secret.Name},
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list QuarksSecrets in other namespaces")
+	}
+
+	return monitoredReconciles(ctx, client, id, secret, append(quarksSecretList.Items, crossNamespaceList.Items...))
+}
+
+// listNamespaceReferencingQuarksSecretsReconciles lists all QuarksSecrets in
+// other monitored namespaces, which reference a secret in the namespace.
+func listNamespaceReferencingQuarksSecretsReconciles(ctx context.Context, client crc.Client, id string, ns *corev1.Namespace) ([]reconcile.Request, error) {
+	quarksSecretList := &qsv1a1.QuarksSecretList{}
+	err := client.List(ctx, quarksSecretList,
+		crc.MatchingFields{referencedNamespacesIndex: ns.Name},
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list QuarksSecrets in other namespaces")
+	}
+
+	return monitoredReconciles(ctx, client, id, ns, quarksSecretList.Items)
+}
+
+// monitoredReconciles returns the reconciles for the QuarksSecrets in
+// monitored namespaces. The referenced secret may live in a namespace, which
+// is not monitored.
+func monitoredReconciles(ctx context.Context, client crc.Client, id string, object crc.Object, quarksSecrets []qsv1a1.QuarksSecret) ([]reconcile.Request, error) {
+	result := []reconcile.Request{}
+	seen := map[types.NamespacedName]bool{}
+	monitored := map[string]bool{}
+	for _, quarksSecret := range quarksSecrets {
+		if _, ok := monitored[quarksSecret.Namespace]; !ok {
+			ns := &corev1.Namespace{}
+			if err := client.Get(ctx, types.NamespacedName{Name: quarksSecret.Namespace}, ns); err != nil {
+				return result, errors.Wrapf(err, "failed to get namespace '%s'", quarksSecret.Namespace)
+			}
+			monitored[quarksSecret.Namespace] = qsv1a1.IsMonitoredNamespace(ns, id)
+		}
+		if !monitored[quarksSecret.Namespace] {
+			continue
+		}
+
+		request := reconcile.Request{
+			NamespacedName: types.NamespacedName{
+				Name:      quarksSecret.Name,
+				Namespace: quarksSecret.Namespace,
+			}}
+		if seen[request.NamespacedName] {
+			continue
+		}
+		seen[request.NamespacedName] = true
+		result = append(result, request)
+		ctxlog.NewMappingEvent(object).Debug(ctx, request, "QuarksSecret", object.GetName(), qsv1a1.KubeSecretReference)
+	}
+	return result, nil
+}
+
+// getSecretReferenceValue returns the value of the referenced key in the secret
+func getSecretReferenceValue(ctx context.Context, client crc.Client, namespace string, ref qsv1a1.SecretReference) (string, error) {
+	secret, err := getReferencedSecret(ctx, client, namespace, ref)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return "", newSecNotReadyError(fmt.Sprintf("secret '%s/%s' not found", referencedNamespace(namespace, ref), ref.Name))
+		}
+		return "", err
+	}
+
+	data, ok := secret.Data[ref.Key]
+	if !ok {
+		return "", errors.Errorf("Failed to get secret data key '%s' from '%s/%s'", ref.Key, secret.Namespace, ref.Name)
+	}
+	return string(data), nil
+}
+
+// getReferencedSecret returns the referenced secret for a quarks secret in
+// the namespace. A secret in another namespace is only returned, if it or
+// its namespace allows references from the namespace. Returns the
+// unwrapped error, if the secret doesn't exist.
+func getReferencedSecret(ctx context.Context, client crc.Client, namespace string, ref qsv1a1.SecretReference) (*corev1.Secret, error) {
+	ns := referencedNamespace(namespace, ref)
+
+	secret := &corev1.Secret{}
+	err := client.Get(ctx, types.NamespacedName{Namespace: ns, Name: ref.Name}, secret)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, err
+		}
+		return nil, errors.Wrapf(err, "getting secret '%s/%s'", ns, ref.Name)
+	}
+
+	if ns == namespace || allowsNamespace(secret.GetAnnotations()[qsv1a1.AnnotationAllowReferencesFrom], namespace) {
+		return secret, nil
+	}
+
+	n := &corev1.Namespace{}
+	if err := client.Get(ctx, types.NamespacedName{Name: ns}, n); err != nil {
+		return nil, errors.Wrapf(err, "getting namespace '%s'", ns)
+	}
+	if allowsNamespace(n.GetAnnotations()[qsv1a1.AnnotationAllowReferencesFrom], namespace) {
+		return secret, nil
+	}
+
+	return nil, errors.Errorf("secret '%s/%s' doesn't allow references from namespace '%s'", ns, ref.Name, namespace)
+}
+
+// inputsHash returns a checksum over the request and the values read from
+// referenced secrets, which were used to render a secret
+func inputsHash(request interface{}, values map[string]string) (string, error) {
+	b, err := json.Marshal(struct {
+		Request interface{}       `json:"request"`
+		Values  map[string]string `json:"values"`
+	}{request, values})
+	if err != nil {
+		return "", errors.Wrap(err, "marshalling inputs for hashing")
+	}
+
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// inputsChanged returns true, if the secret was not generated yet or the
+// inputs have changed since it was rendered
+func inputsChanged(qsec *qsv1a1.QuarksSecret, hash string) bool {
+	return !qsec.Status.IsGenerated() || qsec.Status.InputsHash != hash
+}
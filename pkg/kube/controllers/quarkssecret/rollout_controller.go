@@ -0,0 +1,90 @@
+package quarkssecret
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	crc "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	qsv1a1 "code.cloudfoundry.org/quarks-secret/pkg/kube/apis/quarkssecret/v1alpha1"
+	"code.cloudfoundry.org/quarks-utils/pkg/config"
+	"code.cloudfoundry.org/quarks-utils/pkg/ctxlog"
+)
+
+// AddRollout creates a new rollout controller to watch for changes to
+// generated secrets and roll out the workloads listed in their QuarksSecret.
+func AddRollout(ctx context.Context, config *config.Config, mgr manager.Manager) error {
+	ctx = ctxlog.NewContextWithRecorder(ctx, "rollout-reconciler", mgr.GetEventRecorderFor("rollout-recorder"))
+	r := NewRolloutReconciler(ctx, config, mgr)
+
+	c, err := controller.New("rollout-controller", mgr, controller.Options{
+		Reconciler:              r,
+		MaxConcurrentReconciles: config.MaxQuarksSecretWorkers,
+	})
+	if err != nil {
+		return errors.Wrap(err, "Adding rollout controller to manager failed.")
+	}
+
+	nsPred := newNSPredicate(ctx, mgr.GetClient(), config.MonitoredID)
+
+	// Watch for changes to the data of generated secrets. Creating a secret
+	// doesn't trigger a rollout, as workloads are waiting for it anyway.
+	p := predicate.Funcs{
+		CreateFunc:  func(e event.CreateEvent) bool { return false },
+		DeleteFunc:  func(e event.DeleteEvent) bool { return false },
+		GenericFunc: func(e event.GenericEvent) bool { return false },
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			n := e.ObjectNew.(*corev1.Secret)
+			o := e.ObjectOld.(*corev1.Secret)
+
+			return isGeneratedSecret(n) && !reflect.DeepEqual(n.Data, o.Data)
+		},
+	}
+	err = c.Watch(&source.Kind{Type: &corev1.Secret{}}, ownerQuarksSecretHandler(ctx), nsPred, p)
+	if err != nil {
+		return errors.Wrapf(err, "Watching generated secrets failed in rollout controller.")
+	}
+
+	// Watch for QuarksSecrets, which are resumed with a pending rollout
+	err = c.Watch(&source.Kind{Type: &qsv1a1.QuarksSecret{}}, &handler.EnqueueRequestForObject{}, nsPred, resumedPredicate(qsv1a1.WorkRollout))
+	if err != nil {
+		return errors.Wrapf(err, "Watching quarks secrets failed in rollout controller.")
+	}
+
+	return nil
+}
+
+// ownerQuarksSecretHandler enqueues the QuarksSecret, which controls the secret
+func ownerQuarksSecretHandler(ctx context.Context) handler.EventHandler {
+	return handler.EnqueueRequestsFromMapFunc(
+		func(a crc.Object) []reconcile.Request {
+			owner := metav1.GetControllerOf(a)
+			if owner == nil || owner.Kind != qsv1a1.QuarksSecretResourceKind {
+				return []reconcile.Request{}
+			}
+
+			request := reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      owner.Name,
+					Namespace: a.GetNamespace(),
+				}}
+			ctxlog.NewMappingEvent(a).Debug(ctx, request, "QuarksSecret", a.GetName(), qsv1a1.KubeSecretReference)
+			return []reconcile.Request{request}
+		})
+}
+
+// isGeneratedSecret returns true if the secret was created by the operator
+func isGeneratedSecret(o crc.Object) bool {
+	return o.GetLabels()[qsv1a1.LabelKind] == qsv1a1.GeneratedSecretKind
+}
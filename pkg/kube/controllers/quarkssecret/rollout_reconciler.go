@@ -0,0 +1,222 @@
+package quarkssecret
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	qsv1a1 "code.cloudfoundry.org/quarks-secret/pkg/kube/apis/quarkssecret/v1alpha1"
+	"code.cloudfoundry.org/quarks-utils/pkg/config"
+	"code.cloudfoundry.org/quarks-utils/pkg/ctxlog"
+	"code.cloudfoundry.org/quarks-utils/pkg/names"
+)
+
+// NewRolloutReconciler returns a new ReconcileRollout
+func NewRolloutReconciler(ctx context.Context, config *config.Config, mgr manager.Manager) reconcile.Reconciler {
+	return &ReconcileRollout{
+		ctx:    ctx,
+		config: config,
+		client: mgr.GetClient(),
+	}
+}
+
+// ReconcileRollout rolls out the workloads of a QuarksSecret, after its
+// generated secret changed
+type ReconcileRollout struct {
+	ctx    context.Context
+	client client.Client
+	config *config.Config
+}
+
+// Reconcile sets the checksum of the generated secret as an annotation on the
+// pod templates of all rollout targets, which triggers a rollout.
+func (r *ReconcileRollout) Reconcile(_ context.Context, request reconcile.Request) (reconcile.Result, error) {
+	qsec := &qsv1a1.QuarksSecret{}
+
+	// Set the ctx to be Background, as the top-level context for incoming requests.
+	ctx, cancel := context.WithTimeout(r.ctx, r.config.CtxTimeOut)
+	defer cancel()
+
+	ctxlog.Infof(ctx, "Reconciling rollout for QuarksSecret %s", request.NamespacedName)
+	err := r.client.Get(ctx, request.NamespacedName, qsec)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			ctxlog.Info(ctx, "Skip reconcile: quarks secret not found")
+			return reconcile.Result{}, nil
+		}
+		ctxlog.Info(ctx, "Error reading the object")
+		return reconcile.Result{}, errors.Wrap(err, "Error reading quarksSecret")
+	}
+
+	if qsec.Spec.RolloutTargets == nil {
+		ctxlog.Debugf(ctx, "Skip reconcile: QuarksSecret '%s' has no rollout targets", qsec.GetNamespacedName())
+		return reconcile.Result{}, nil
+	}
+
+	if qsec.Spec.Paused {
+		return reconcile.Result{}, deferWork(ctx, r.client, qsec, qsv1a1.WorkRollout)
+	}
+
+	secret, err := GetSourceSecret(ctx, r.client, qsec)
+	if err != nil {
+		if apierrors.IsNotFound(errors.Cause(err)) {
+			ctxlog.Infof(ctx, "Skip reconcile: generated secret '%s/%s' not found", qsec.Namespace, qsec.Spec.SecretName)
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, err
+	}
+
+	checksum, err := secretDataChecksum(secret.Data)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	workloads, err := r.listTargets(ctx, qsec)
+	if err != nil {
+		return reconcile.Result{}, errors.Wrapf(err, "listing rollout targets of QuarksSecret '%s'", qsec.GetNamespacedName())
+	}
+
+	key := checksumAnnotation(qsec.Spec.SecretName)
+	for _, workload := range workloads {
+		template := podTemplate(workload)
+		if template.Annotations[key] == checksum {
+			continue
+		}
+
+		patch := client.MergeFrom(workload.DeepCopyObject().(client.Object))
+		if template.Annotations == nil {
+			template.Annotations = map[string]string{}
+		}
+		template.Annotations[key] = checksum
+
+		err = r.client.Patch(ctx, workload, patch)
+		if err != nil {
+			return reconcile.Result{}, errors.Wrapf(err, "could not roll out '%s/%s'", workload.GetNamespace(), workload.GetName())
+		}
+		ctxlog.WithEvent(qsec, "Rollout").Infof(ctx, "Rolling out '%s/%s' after secret '%s' changed", workload.GetNamespace(), workload.GetName(), secret.Name)
+	}
+
+	return reconcile.Result{}, nil
+}
+
+// listTargets returns the explicitly listed workloads and the ones matching
+// the selector, if it is set
+func (r *ReconcileRollout) listTargets(ctx context.Context, qsec *qsv1a1.QuarksSecret) ([]client.Object, error) {
+	result := []client.Object{}
+	seen := map[string]bool{}
+	add := func(kind string, o client.Object) {
+		id := kind + "/" + o.GetName()
+		if !seen[id] {
+			seen[id] = true
+			result = append(result, o)
+		}
+	}
+
+	for _, w := range qsec.Spec.RolloutTargets.Workloads {
+		workload, err := newWorkload(w.Kind)
+		if err != nil {
+			return nil, err
+		}
+		err = r.client.Get(ctx, types.NamespacedName{Name: w.Name, Namespace: qsec.Namespace}, workload)
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				ctxlog.WithEvent(qsec, "RolloutTargetNotFound").Infof(ctx, "Rollout target %s '%s/%s' not found", w.Kind, qsec.Namespace, w.Name)
+				continue
+			}
+			return nil, err
+		}
+		add(w.Kind, workload)
+	}
+
+	if qsec.Spec.RolloutTargets.Selector == nil {
+		return result, nil
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(qsec.Spec.RolloutTargets.Selector)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid rollout target selector")
+	}
+	opts := []client.ListOption{
+		client.InNamespace(qsec.Namespace),
+		client.MatchingLabelsSelector{Selector: selector},
+	}
+
+	deployments := &appsv1.DeploymentList{}
+	if err := r.client.List(ctx, deployments, opts...); err != nil {
+		return nil, err
+	}
+	for i := range deployments.Items {
+		add(qsv1a1.DeploymentKind, &deployments.Items[i])
+	}
+
+	statefulSets := &appsv1.StatefulSetList{}
+	if err := r.client.List(ctx, statefulSets, opts...); err != nil {
+		return nil, err
+	}
+	for i := range statefulSets.Items {
+		add(qsv1a1.StatefulSetKind, &statefulSets.Items[i])
+	}
+
+	daemonSets := &appsv1.DaemonSetList{}
+	if err := r.client.List(ctx, daemonSets, opts...); err != nil {
+		return nil, err
+	}
+	for i := range daemonSets.Items {
+		add(qsv1a1.DaemonSetKind, &daemonSets.Items[i])
+	}
+
+	return result, nil
+}
+
+// newWorkload returns an empty object for the workload kind
+func newWorkload(kind qsv1a1.WorkloadKind) (client.Object, error) {
+	switch kind {
+	case qsv1a1.DeploymentKind:
+		return &appsv1.Deployment{}, nil
+	case qsv1a1.StatefulSetKind:
+		return &appsv1.StatefulSet{}, nil
+	case qsv1a1.DaemonSetKind:
+		return &appsv1.DaemonSet{}, nil
+	}
+	return nil, errors.Errorf("unsupported rollout target kind: %s", kind)
+}
+
+// podTemplate returns the pod template of the workload
+func podTemplate(o client.Object) *corev1.PodTemplateSpec {
+	switch w := o.(type) {
+	case *appsv1.Deployment:
+		return &w.Spec.Template
+	case *appsv1.StatefulSet:
+		return &w.Spec.Template
+	case *appsv1.DaemonSet:
+		return &w.Spec.Template
+	}
+	return &corev1.PodTemplateSpec{}
+}
+
+// checksumAnnotation returns the pod template annotation key for the secret's checksum
+func checksumAnnotation(secretName string) string {
+	return qsv1a1.AnnotationSecretChecksumPrefix + names.TruncateMD5(secretName, 63-len("checksum-"))
+}
+
+// secretDataChecksum returns a checksum over the secret's data
+func secretDataChecksum(data map[string][]byte) (string, error) {
+	b, err := json.Marshal(data)
+	if err != nil {
+		return "", errors.Wrap(err, "marshalling secret data for hashing")
+	}
+
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:]), nil
+}
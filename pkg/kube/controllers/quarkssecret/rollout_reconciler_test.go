@@ -0,0 +1,184 @@
+package quarkssecret_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"go.uber.org/zap"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	crc "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	qsv1a1 "code.cloudfoundry.org/quarks-secret/pkg/kube/apis/quarkssecret/v1alpha1"
+	"code.cloudfoundry.org/quarks-secret/pkg/kube/client/clientset/versioned/scheme"
+	"code.cloudfoundry.org/quarks-secret/pkg/kube/controllers"
+	cfakes "code.cloudfoundry.org/quarks-secret/pkg/kube/controllers/fakes"
+	qscontroller "code.cloudfoundry.org/quarks-secret/pkg/kube/controllers/quarkssecret"
+	cfcfg "code.cloudfoundry.org/quarks-utils/pkg/config"
+	"code.cloudfoundry.org/quarks-utils/pkg/ctxlog"
+	helper "code.cloudfoundry.org/quarks-utils/testing/testhelper"
+)
+
+var _ = Describe("ReconcileRollout", func() {
+	var (
+		manager    *cfakes.FakeManager
+		reconciler reconcile.Reconciler
+		request    reconcile.Request
+		ctx        context.Context
+		log        *zap.SugaredLogger
+		config     *cfcfg.Config
+		client     *cfakes.FakeClient
+		qSecret    *qsv1a1.QuarksSecret
+		secret     *corev1.Secret
+		deployment *appsv1.Deployment
+		daemonSet  *appsv1.DaemonSet
+		annotation = qsv1a1.AnnotationSecretChecksumPrefix + "generated-secret"
+	)
+
+	BeforeEach(func() {
+		err := controllers.AddToScheme(scheme.Scheme)
+		Expect(err).ToNot(HaveOccurred())
+		manager = &cfakes.FakeManager{}
+		request = reconcile.Request{NamespacedName: types.NamespacedName{Name: "foo", Namespace: "default"}}
+		config = &cfcfg.Config{CtxTimeOut: 10 * time.Second}
+		_, log = helper.NewTestLogger()
+		ctx = ctxlog.NewParentContext(log)
+		qSecret = &qsv1a1.QuarksSecret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo",
+				Namespace: "default",
+			},
+			Spec: qsv1a1.QuarksSecretSpec{
+				Type:       "password",
+				SecretName: "generated-secret",
+				RolloutTargets: &qsv1a1.RolloutTargets{
+					Workloads: []qsv1a1.Workload{{Kind: qsv1a1.DeploymentKind, Name: "app"}},
+				},
+			},
+		}
+		secret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "generated-secret",
+				Namespace: "default",
+			},
+			Data: map[string][]byte{"password": []byte("securepassword")},
+		}
+		deployment = &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "app",
+				Namespace: "default",
+			},
+		}
+		daemonSet = &appsv1.DaemonSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "agent",
+				Namespace: "default",
+				Labels:    map[string]string{"app": "agent"},
+			},
+		}
+
+		client = &cfakes.FakeClient{}
+		client.GetCalls(func(context context.Context, nn types.NamespacedName, object crc.Object) error {
+			switch object := object.(type) {
+			case *qsv1a1.QuarksSecret:
+				qSecret.DeepCopyInto(object)
+			case *corev1.Secret:
+				secret.DeepCopyInto(object)
+			case *appsv1.Deployment:
+				if nn.Name != deployment.Name {
+					return errors.NewNotFound(schema.GroupResource{}, "not found")
+				}
+				deployment.DeepCopyInto(object)
+			}
+			return nil
+		})
+		client.ListCalls(func(context context.Context, object crc.ObjectList, _ ...crc.ListOption) error {
+			if list, ok := object.(*appsv1.DaemonSetList); ok {
+				list.Items = []appsv1.DaemonSet{*daemonSet}
+			}
+			return nil
+		})
+		manager.GetClientReturns(client)
+	})
+
+	JustBeforeEach(func() {
+		reconciler = qscontroller.NewRolloutReconciler(ctx, config, manager)
+	})
+
+	It("does nothing without rollout targets", func() {
+		qSecret.Spec.RolloutTargets = nil
+
+		result, err := reconciler.Reconcile(context.Background(), request)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(reconcile.Result{}).To(Equal(result))
+		Expect(client.PatchCallCount()).To(Equal(0))
+	})
+
+	It("annotates the pod template of listed workloads with the secret checksum", func() {
+		result, err := reconciler.Reconcile(context.Background(), request)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(reconcile.Result{}).To(Equal(result))
+		Expect(client.PatchCallCount()).To(Equal(1))
+
+		_, object, _, _ := client.PatchArgsForCall(0)
+		patched := object.(*appsv1.Deployment)
+		Expect(patched.Name).To(Equal("app"))
+		Expect(patched.Spec.Template.Annotations).To(HaveKey(annotation))
+	})
+
+	It("skips workloads which already use the current secret", func() {
+		_, err := reconciler.Reconcile(context.Background(), request)
+		Expect(err).ToNot(HaveOccurred())
+		_, object, _, _ := client.PatchArgsForCall(0)
+		object.(*appsv1.Deployment).DeepCopyInto(deployment)
+
+		_, err = reconciler.Reconcile(context.Background(), request)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(client.PatchCallCount()).To(Equal(1))
+	})
+
+	It("rolls out again when the secret changed", func() {
+		_, err := reconciler.Reconcile(context.Background(), request)
+		Expect(err).ToNot(HaveOccurred())
+		_, object, _, _ := client.PatchArgsForCall(0)
+		object.(*appsv1.Deployment).DeepCopyInto(deployment)
+		checksum := deployment.Spec.Template.Annotations[annotation]
+
+		secret.Data["password"] = []byte("rotated")
+		_, err = reconciler.Reconcile(context.Background(), request)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(client.PatchCallCount()).To(Equal(2))
+		_, object, _, _ = client.PatchArgsForCall(1)
+		Expect(object.(*appsv1.Deployment).Spec.Template.Annotations[annotation]).ToNot(Equal(checksum))
+	})
+
+	It("rolls out workloads matching the selector", func() {
+		qSecret.Spec.RolloutTargets = &qsv1a1.RolloutTargets{
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "agent"}},
+		}
+
+		_, err := reconciler.Reconcile(context.Background(), request)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(client.ListCallCount()).To(Equal(3))
+		Expect(client.PatchCallCount()).To(Equal(1))
+
+		_, object, _, _ := client.PatchArgsForCall(0)
+		Expect(object.(*appsv1.DaemonSet).Name).To(Equal("agent"))
+	})
+
+	It("ignores missing workloads", func() {
+		qSecret.Spec.RolloutTargets.Workloads = []qsv1a1.Workload{{Kind: qsv1a1.DeploymentKind, Name: "missing"}}
+
+		_, err := reconciler.Reconcile(context.Background(), request)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(client.PatchCallCount()).To(Equal(0))
+	})
+})
@@ -3,6 +3,7 @@ package quarkssecret
 import (
 	"context"
 	"fmt"
+	"reflect"
 
 	"github.com/pkg/errors"
 
@@ -52,7 +53,21 @@ func AddSecretRotation(ctx context.Context, config *config.Config, mgr manager.M
 		},
 		DeleteFunc:  func(e event.DeleteEvent) bool { return false },
 		GenericFunc: func(e event.GenericEvent) bool { return false },
-		UpdateFunc:  func(e event.UpdateEvent) bool { return false },
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			n := e.ObjectNew.(*corev1.ConfigMap)
+			o := e.ObjectOld.(*corev1.ConfigMap)
+
+			// re-trigger rotation if the list of secrets changed
+			_, found := n.GetLabels()[qsv1a1.LabelSecretRotationTrigger]
+			if found && !reflect.DeepEqual(o.Data, n.Data) {
+				ctxlog.NewPredicateEvent(e.ObjectNew).Debug(
+					ctx, e.ObjectNew, "corev1.ConfigMap",
+					fmt.Sprintf("Update predicate passed for '%s/%s'", e.ObjectNew.GetNamespace(), e.ObjectNew.GetName()),
+				)
+				return true
+			}
+			return false
+		},
 	}
 	err = c.Watch(&source.Kind{Type: &corev1.ConfigMap{}}, &handler.EnqueueRequestForObject{}, nsPred, p)
 	if err != nil {
@@ -3,17 +3,21 @@ package quarkssecret
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 
 	"github.com/pkg/errors"
 
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
+	"code.cloudfoundry.org/quarks-secret/pkg/kube/apis"
 	qsv1a1 "code.cloudfoundry.org/quarks-secret/pkg/kube/apis/quarkssecret/v1alpha1"
 	"code.cloudfoundry.org/quarks-utils/pkg/config"
 	"code.cloudfoundry.org/quarks-utils/pkg/ctxlog"
@@ -39,7 +43,7 @@ type ReconcileSecretRotation struct {
 }
 
 // Reconcile reads that state of the cluster and trigger secret rotation for
-// all listed deployments.
+// all listed or selected quarks secrets.
 func (r *ReconcileSecretRotation) Reconcile(_ context.Context, request reconcile.Request) (reconcile.Result, error) {
 	instance := &corev1.ConfigMap{}
 
@@ -58,41 +62,253 @@ func (r *ReconcileSecretRotation) Reconcile(_ context.Context, request reconcile
 		return reconcile.Result{}, errors.Wrap(err, "Error reading quarksSecret")
 	}
 
-	data, found := instance.Data[qsv1a1.RotateQSecretListName]
-	if !found {
-		ctxlog.Debugf(ctx, "QuarksSecret rotation config didn't list any names, key %s not found", qsv1a1.RotateQSecretListName)
-		return reconcile.Result{}, nil
+	selection, err := rotationSelectionFromConfigMap(instance)
+	if err != nil {
+		return reconcile.Result{}, errors.Wrapf(err, "Error reading secrets to rotate from '%s'", request.NamespacedName)
 	}
 
-	names := []string{}
-	err = json.Unmarshal([]byte(data), &names)
+	targets, err := selectRotationTargets(ctx, r.client, r.config.MonitoredID, instance, selection)
 	if err != nil {
-		return reconcile.Result{}, errors.Wrapf(err, "Error un-marshalling list of secrets to rotate from '%s'", request.NamespacedName)
+		return reconcile.Result{}, errors.Wrapf(err, "Error selecting secrets to rotate for '%s'", request.NamespacedName)
+	}
+	if len(targets) == 0 {
+		ctxlog.WithEvent(instance, "RotationNothingSelected").Infof(ctx, "QuarksSecret rotation config '%s' didn't select any quarks secrets", request.NamespacedName)
 	}
 
-	for _, name := range names {
-		qsec := &qsv1a1.QuarksSecret{}
-		qsecname := types.NamespacedName{Name: name, Namespace: instance.Namespace}
-		err := r.client.Get(ctx, qsecname, qsec)
+	// a retry would rotate the quarks secrets again, which were already
+	// rotated, so failures are recorded as results
+	results := map[string]qsv1a1.RotationResult{}
+	for _, target := range targets {
+		results[target.key], err = rotateQuarksSecret(ctx, r.client, instance, target.name, false)
 		if err != nil {
-			ctxlog.Errorf(ctx, "Error getting QuarksSecret '%s', skipping secret rotation: %s", qsecname.String(), err)
-			continue
+			results[target.key] = qsv1a1.RotationFailed
+			_ = ctxlog.WithEvent(instance, "RotationFailed").Errorf(ctx, "Failed to rotate QuarksSecret '%s': %s", target.name.String(), err)
 		}
+	}
+
+	err = r.recordResults(ctx, instance, results)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	return reconcile.Result{}, nil
+}
+
+// rotationSelection lists quarks secrets by name and selects them by labels
+// and types
+type rotationSelection struct {
+	names []string
+	// selector is nil, if quarks secrets are only listed by name
+	selector   labels.Selector
+	types      []qsv1a1.SecretType
+	namespaces []string
+}
+
+// rotationSelectionFromConfigMap parses the selection from the entries of
+// the rotation config map
+func rotationSelectionFromConfigMap(instance *corev1.ConfigMap) (rotationSelection, error) {
+	selection := rotationSelection{}
 
-		// skip manual secrets or the ones that have not yet been generated
-		if qsec.Status.NotGenerated() {
-			ctxlog.Debugf(ctx, "QuarksSecret '%s' cannot be rotated, it was not generated", qsec.GetNamespacedName())
-			continue
+	if data, found := instance.Data[qsv1a1.RotateQSecretListName]; found {
+		err := json.Unmarshal([]byte(data), &selection.names)
+		if err != nil {
+			return selection, errors.Wrapf(err, "Error un-marshalling list of secrets to rotate")
 		}
+	}
+
+	selectorData, hasSelector := instance.Data[qsv1a1.RotateQSecretSelectorName]
+	typesData, hasTypes := instance.Data[qsv1a1.RotateQSecretTypesName]
+	if !hasSelector && !hasTypes {
+		return selection, nil
+	}
 
-		qsec.Status.Generated = pointers.Bool(false)
-		ctxlog.Debugf(ctx, "QuarksSecret '%s' status.generated will be reset to false to trigger regeneration", qsec.GetNamespacedName())
+	selector, err := labels.Parse(selectorData)
+	if err != nil {
+		return selection, errors.Wrapf(err, "Error parsing label selector of secrets to rotate")
+	}
+	selection.selector = selector
 
-		err = r.client.Status().Update(ctx, qsec)
+	if hasTypes {
+		err := json.Unmarshal([]byte(typesData), &selection.types)
 		if err != nil {
-			return reconcile.Result{}, errors.Wrap(err, "Error updating QuarksSecret status")
+			return selection, errors.Wrapf(err, "Error un-marshalling list of secret types to rotate")
 		}
 	}
 
-	return reconcile.Result{}, nil
+	if data, found := instance.Data[qsv1a1.RotateQSecretNamespacesName]; found {
+		err := json.Unmarshal([]byte(data), &selection.namespaces)
+		if err != nil {
+			return selection, errors.Wrapf(err, "Error un-marshalling list of namespaces to rotate secrets in")
+		}
+	}
+
+	return selection, nil
+}
+
+// rotationTarget is a quarks secret selected for rotation
+type rotationTarget struct {
+	// key is used to report the result, it's the name for quarks secrets
+	// in the namespace of the rotation request and 'namespace/name' otherwise
+	key  string
+	name types.NamespacedName
+}
+
+// selectRotationTargets returns the quarks secrets listed by name and the
+// ones selected by labels and types. Unless namespaces are given, selection
+// happens in the namespace of the rotation request. Other namespaces have to
+// be monitored and allow rotations from the request's namespace.
+func selectRotationTargets(ctx context.Context, c client.Client, monitoredID string, request apis.Object, selection rotationSelection) ([]rotationTarget, error) {
+	targets := []rotationTarget{}
+	seen := map[types.NamespacedName]bool{}
+	add := func(name types.NamespacedName) {
+		if seen[name] {
+			return
+		}
+		seen[name] = true
+		key := name.Name
+		if name.Namespace != request.GetNamespace() {
+			key = name.String()
+		}
+		targets = append(targets, rotationTarget{key: key, name: name})
+	}
+
+	for _, name := range selection.names {
+		add(types.NamespacedName{Name: name, Namespace: request.GetNamespace()})
+	}
+
+	if selection.selector == nil {
+		return targets, nil
+	}
+
+	namespaces := selection.namespaces
+	if len(namespaces) == 0 {
+		namespaces = []string{request.GetNamespace()}
+	}
+
+	for _, namespace := range namespaces {
+		if namespace != request.GetNamespace() {
+			ns := &corev1.Namespace{}
+			err := c.Get(ctx, types.NamespacedName{Name: namespace}, ns)
+			if err != nil && !apierrors.IsNotFound(err) {
+				return nil, errors.Wrapf(err, "Error getting namespace '%s'", namespace)
+			}
+			if err != nil || !qsv1a1.IsMonitoredNamespace(ns, monitoredID) {
+				ctxlog.WithEvent(request, "RotationNamespaceSkipped").Infof(ctx, "Namespace '%s' is not monitored, skipping secret rotation", namespace)
+				continue
+			}
+			if !allowsRotationsFrom(ns, request.GetNamespace()) {
+				ctxlog.WithEvent(request, "RotationNamespaceDenied").Infof(ctx, "Namespace '%s' doesn't allow rotations from '%s', skipping secret rotation", namespace, request.GetNamespace())
+				continue
+			}
+		}
+
+		list := &qsv1a1.QuarksSecretList{}
+		err := c.List(ctx, list,
+			client.InNamespace(namespace),
+			client.MatchingLabelsSelector{Selector: selection.selector},
+		)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Error listing QuarksSecrets in namespace '%s'", namespace)
+		}
+
+		for _, qsec := range list.Items {
+			if len(selection.types) > 0 && !containsType(selection.types, qsec.Spec.Type) {
+				continue
+			}
+			add(types.NamespacedName{Name: qsec.Name, Namespace: qsec.Namespace})
+		}
+	}
+
+	return targets, nil
+}
+
+// allowsRotationsFrom returns true, if the namespace allows rotation requests
+// in the source namespace to rotate its quarks secrets
+func allowsRotationsFrom(ns *corev1.Namespace, source string) bool {
+	return allowsNamespace(ns.GetAnnotations()[qsv1a1.AnnotationAllowRotationsFrom], source)
+}
+
+func containsType(secretTypes []qsv1a1.SecretType, secretType qsv1a1.SecretType) bool {
+	for _, t := range secretTypes {
+		if t == secretType {
+			return true
+		}
+	}
+	return false
+}
+
+// rotateQuarksSecret resets the generated status of the quarks secret, which
+// triggers its regeneration. When dryRun is set, it only returns the result
+// rotation would have.
+func rotateQuarksSecret(ctx context.Context, c client.Client, request apis.Object, name types.NamespacedName, dryRun bool) (qsv1a1.RotationResult, error) {
+	qsec := &qsv1a1.QuarksSecret{}
+	err := c.Get(ctx, name, qsec)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			if !dryRun {
+				ctxlog.WithEvent(request, "RotationNotFound").Infof(ctx, "QuarksSecret '%s' not found, skipping secret rotation", name.String())
+			}
+			return qsv1a1.RotationNotFound, nil
+		}
+		return "", errors.Wrapf(err, "Error getting QuarksSecret '%s'", name.String())
+	}
+
+	// skip manual secrets or the ones that have not yet been generated
+	if qsec.Status.NotGenerated() {
+		if !dryRun {
+			ctxlog.WithEvent(request, "RotationSkipped").Infof(ctx, "QuarksSecret '%s' cannot be rotated, it was not generated", qsec.GetNamespacedName())
+		}
+		return qsv1a1.RotationSkippedNotGenerated, nil
+	}
+
+	if dryRun {
+		return qsv1a1.RotationRotated, nil
+	}
+
+	qsec.Status.Generated = pointers.Bool(false)
+	setCondition(qsec, qsv1a1.ConditionGenerated, metav1.ConditionFalse, "Rotating", fmt.Sprintf("Secret '%s' will be regenerated", qsec.Spec.SecretName))
+	ctxlog.Debugf(ctx, "QuarksSecret '%s' status.generated will be reset to false to trigger regeneration", qsec.GetNamespacedName())
+	if qsec.Spec.Paused {
+		// the quarks secret controller regenerates it, once it is resumed
+		addPendingWork(qsec, qsv1a1.WorkGeneration)
+	}
+
+	err = c.Status().Update(ctx, qsec)
+	if err != nil {
+		return "", errors.Wrap(err, "Error updating QuarksSecret status")
+	}
+	ctxlog.WithEvent(request, "Rotated").Infof(ctx, "QuarksSecret '%s' will be regenerated", qsec.GetNamespacedName())
+	return qsv1a1.RotationRotated, nil
+}
+
+// recordResults stores the rotation results in an annotation on the rotation
+// config map, or deletes the config map if requested
+func (r *ReconcileSecretRotation) recordResults(ctx context.Context, instance *corev1.ConfigMap, results map[string]qsv1a1.RotationResult) error {
+	if instance.GetAnnotations()[qsv1a1.AnnotationSecretRotationDelete] == "true" {
+		ctxlog.Infof(ctx, "Deleting processed rotation config '%s/%s'", instance.Namespace, instance.Name)
+		err := r.client.Delete(ctx, instance)
+		if err != nil && !apierrors.IsNotFound(err) {
+			return errors.Wrapf(err, "could not delete rotation config '%s/%s'", instance.Namespace, instance.Name)
+		}
+		return nil
+	}
+
+	data, err := json.Marshal(results)
+	if err != nil {
+		return errors.Wrap(err, "Error marshalling rotation results")
+	}
+
+	patch := client.MergeFrom(instance.DeepCopy())
+	annotations := instance.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[qsv1a1.AnnotationSecretRotationResult] = string(data)
+	instance.SetAnnotations(annotations)
+
+	err = r.client.Patch(ctx, instance, patch)
+	if err != nil {
+		return errors.Wrapf(err, "could not record results on rotation config '%s/%s'", instance.Namespace, instance.Name)
+	}
+	return nil
 }
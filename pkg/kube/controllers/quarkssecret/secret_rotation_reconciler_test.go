@@ -0,0 +1,305 @@
+package quarkssecret_test
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"go.uber.org/zap"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	crc "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	qsv1a1 "code.cloudfoundry.org/quarks-secret/pkg/kube/apis/quarkssecret/v1alpha1"
+	"code.cloudfoundry.org/quarks-secret/pkg/kube/client/clientset/versioned/scheme"
+	"code.cloudfoundry.org/quarks-secret/pkg/kube/controllers"
+	cfakes "code.cloudfoundry.org/quarks-secret/pkg/kube/controllers/fakes"
+	qscontroller "code.cloudfoundry.org/quarks-secret/pkg/kube/controllers/quarkssecret"
+	cfcfg "code.cloudfoundry.org/quarks-utils/pkg/config"
+	"code.cloudfoundry.org/quarks-utils/pkg/ctxlog"
+	"code.cloudfoundry.org/quarks-utils/pkg/pointers"
+	helper "code.cloudfoundry.org/quarks-utils/testing/testhelper"
+)
+
+var _ = Describe("ReconcileSecretRotation", func() {
+	var (
+		manager      *cfakes.FakeManager
+		reconciler   reconcile.Reconciler
+		request      reconcile.Request
+		ctx          context.Context
+		log          *zap.SugaredLogger
+		config       *cfcfg.Config
+		client       *cfakes.FakeClient
+		statusWriter *cfakes.FakeStatusWriter
+		recorder     *record.FakeRecorder
+		configMap    *corev1.ConfigMap
+		qsecs        map[string]*qsv1a1.QuarksSecret
+	)
+
+	results := func() map[string]string {
+		Expect(client.PatchCallCount()).To(Equal(1))
+		_, object, _, _ := client.PatchArgsForCall(0)
+		results := map[string]string{}
+		err := json.Unmarshal([]byte(object.GetAnnotations()[qsv1a1.AnnotationSecretRotationResult]), &results)
+		Expect(err).ToNot(HaveOccurred())
+		return results
+	}
+
+	BeforeEach(func() {
+		err := controllers.AddToScheme(scheme.Scheme)
+		Expect(err).ToNot(HaveOccurred())
+		manager = &cfakes.FakeManager{}
+		request = reconcile.Request{NamespacedName: types.NamespacedName{Name: "rotate", Namespace: "default"}}
+		config = &cfcfg.Config{CtxTimeOut: 10 * time.Second}
+		_, log = helper.NewTestLogger()
+		recorder = record.NewFakeRecorder(10)
+		ctx = ctxlog.NewContextWithRecorder(ctxlog.NewParentContext(log), "TestRecorder", recorder)
+
+		configMap = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "rotate",
+				Namespace: "default",
+				Labels:    map[string]string{qsv1a1.LabelSecretRotationTrigger: "true"},
+			},
+			Data: map[string]string{
+				qsv1a1.RotateQSecretListName: `["generated", "manual", "missing"]`,
+			},
+		}
+		qsecs = map[string]*qsv1a1.QuarksSecret{
+			"generated": {
+				ObjectMeta: metav1.ObjectMeta{Name: "generated", Namespace: "default"},
+				Status:     qsv1a1.QuarksSecretStatus{Generated: pointers.Bool(true)},
+			},
+			"manual": {
+				ObjectMeta: metav1.ObjectMeta{Name: "manual", Namespace: "default"},
+				Status:     qsv1a1.QuarksSecretStatus{Generated: pointers.Bool(false)},
+			},
+		}
+
+		client = &cfakes.FakeClient{}
+		client.GetCalls(func(context context.Context, nn types.NamespacedName, object crc.Object) error {
+			switch object := object.(type) {
+			case *corev1.ConfigMap:
+				configMap.DeepCopyInto(object)
+			case *qsv1a1.QuarksSecret:
+				qsec, ok := qsecs[nn.Name]
+				if !ok {
+					return errors.NewNotFound(schema.GroupResource{}, "not found")
+				}
+				qsec.DeepCopyInto(object)
+			}
+			return nil
+		})
+		statusWriter = &cfakes.FakeStatusWriter{}
+		client.StatusCalls(func() crc.StatusWriter { return statusWriter })
+		manager.GetClientReturns(client)
+	})
+
+	JustBeforeEach(func() {
+		reconciler = qscontroller.NewSecretRotationReconciler(ctx, config, manager)
+	})
+
+	It("resets the generated status of listed quarks secrets", func() {
+		result, err := reconciler.Reconcile(context.Background(), request)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(reconcile.Result{}).To(Equal(result))
+
+		Expect(statusWriter.UpdateCallCount()).To(Equal(1))
+		_, object, _ := statusWriter.UpdateArgsForCall(0)
+		qsec := object.(*qsv1a1.QuarksSecret)
+		Expect(qsec.Name).To(Equal("generated"))
+		Expect(qsec.Status.NotGenerated()).To(BeTrue())
+	})
+
+	It("records the result for each listed quarks secret", func() {
+		_, err := reconciler.Reconcile(context.Background(), request)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(results()).To(Equal(map[string]string{
+			"generated": qsv1a1.RotationRotated,
+			"manual":    qsv1a1.RotationSkippedNotGenerated,
+			"missing":   qsv1a1.RotationNotFound,
+		}))
+		Expect(recorder.Events).To(HaveLen(3))
+	})
+
+	It("records failures and continues with the other quarks secrets", func() {
+		configMap.Data[qsv1a1.RotateQSecretListName] = `["broken", "generated"]`
+		qsecs["broken"] = &qsv1a1.QuarksSecret{
+			ObjectMeta: metav1.ObjectMeta{Name: "broken", Namespace: "default"},
+			Status:     qsv1a1.QuarksSecretStatus{Generated: pointers.Bool(true)},
+		}
+		statusWriter.UpdateCalls(func(_ context.Context, object crc.Object, _ ...crc.UpdateOption) error {
+			if object.GetName() == "broken" {
+				return errors.NewConflict(schema.GroupResource{}, "broken", nil)
+			}
+			return nil
+		})
+
+		_, err := reconciler.Reconcile(context.Background(), request)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(statusWriter.UpdateCallCount()).To(Equal(2))
+		Expect(results()).To(Equal(map[string]string{
+			"broken":    qsv1a1.RotationFailed,
+			"generated": qsv1a1.RotationRotated,
+		}))
+	})
+
+	It("records an empty result, if nothing was selected", func() {
+		configMap.Data[qsv1a1.RotateQSecretListName] = `[]`
+
+		_, err := reconciler.Reconcile(context.Background(), request)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(results()).To(BeEmpty())
+		Expect(recorder.Events).To(HaveLen(1))
+	})
+
+	It("deletes the config map after processing, if requested", func() {
+		configMap.Annotations = map[string]string{qsv1a1.AnnotationSecretRotationDelete: "true"}
+
+		_, err := reconciler.Reconcile(context.Background(), request)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(client.PatchCallCount()).To(Equal(0))
+		Expect(client.DeleteCallCount()).To(Equal(1))
+		_, object, _ := client.DeleteArgsForCall(0)
+		Expect(object.GetName()).To(Equal("rotate"))
+	})
+
+	Context("when selecting quarks secrets", func() {
+		var listOptions []crc.ListOption
+
+		BeforeEach(func() {
+			configMap.Data = map[string]string{
+				qsv1a1.RotateQSecretSelectorName: "app=db",
+				qsv1a1.RotateQSecretTypesName:    `["password"]`,
+			}
+			listOptions = []crc.ListOption{}
+			client.ListCalls(func(_ context.Context, object crc.ObjectList, opts ...crc.ListOption) error {
+				listOptions = append(listOptions, opts...)
+				list := object.(*qsv1a1.QuarksSecretList)
+				list.Items = []qsv1a1.QuarksSecret{
+					{
+						ObjectMeta: metav1.ObjectMeta{Name: "generated", Namespace: "default"},
+						Spec:       qsv1a1.QuarksSecretSpec{Type: qsv1a1.Password},
+					},
+					{
+						ObjectMeta: metav1.ObjectMeta{Name: "cert", Namespace: "default"},
+						Spec:       qsv1a1.QuarksSecretSpec{Type: qsv1a1.Certificate},
+					},
+				}
+				return nil
+			})
+		})
+
+		It("rotates quarks secrets matching the selector and types", func() {
+			_, err := reconciler.Reconcile(context.Background(), request)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(client.ListCallCount()).To(Equal(1))
+			listOpts := &crc.ListOptions{}
+			listOpts.ApplyOptions(listOptions)
+			Expect(listOpts.Namespace).To(Equal("default"))
+			Expect(listOpts.LabelSelector.String()).To(Equal("app=db"))
+
+			Expect(results()).To(Equal(map[string]string{
+				"generated": qsv1a1.RotationRotated,
+			}))
+		})
+
+		It("skips namespaces which are not monitored", func() {
+			configMap.Data[qsv1a1.RotateQSecretNamespacesName] = `["default", "other"]`
+			client.GetCalls(func(context context.Context, nn types.NamespacedName, object crc.Object) error {
+				switch object := object.(type) {
+				case *corev1.ConfigMap:
+					configMap.DeepCopyInto(object)
+				case *corev1.Namespace:
+					return errors.NewNotFound(schema.GroupResource{}, "not found")
+				case *qsv1a1.QuarksSecret:
+					qsecs[nn.Name].DeepCopyInto(object)
+				}
+				return nil
+			})
+
+			_, err := reconciler.Reconcile(context.Background(), request)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(client.ListCallCount()).To(Equal(1))
+			Expect(results()).To(HaveLen(1))
+		})
+
+		Context("when selecting in other namespaces", func() {
+			var namespace *corev1.Namespace
+
+			BeforeEach(func() {
+				config.MonitoredID = "quarks"
+				configMap.Data[qsv1a1.RotateQSecretNamespacesName] = `["default", "other"]`
+				namespace = &corev1.Namespace{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:   "other",
+						Labels: map[string]string{qsv1a1.LabelNamespace: "quarks"},
+					},
+				}
+				client.GetCalls(func(context context.Context, nn types.NamespacedName, object crc.Object) error {
+					switch object := object.(type) {
+					case *corev1.ConfigMap:
+						configMap.DeepCopyInto(object)
+					case *corev1.Namespace:
+						namespace.DeepCopyInto(object)
+					case *qsv1a1.QuarksSecret:
+						qsecs[nn.Name].DeepCopyInto(object)
+					}
+					return nil
+				})
+			})
+
+			It("skips namespaces which don't allow rotations", func() {
+				_, err := reconciler.Reconcile(context.Background(), request)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(client.ListCallCount()).To(Equal(1))
+				Expect(results()).To(HaveLen(1))
+			})
+
+			It("skips namespaces which allow rotations from other namespaces", func() {
+				namespace.Annotations = map[string]string{qsv1a1.AnnotationAllowRotationsFrom: "platform"}
+
+				_, err := reconciler.Reconcile(context.Background(), request)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(client.ListCallCount()).To(Equal(1))
+			})
+
+			It("selects in namespaces which allow rotations", func() {
+				namespace.Annotations = map[string]string{qsv1a1.AnnotationAllowRotationsFrom: "platform, default"}
+
+				_, err := reconciler.Reconcile(context.Background(), request)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(client.ListCallCount()).To(Equal(2))
+				listOpts := &crc.ListOptions{}
+				listOpts.ApplyOptions(listOptions[len(listOptions)-2:])
+				Expect(listOpts.Namespace).To(Equal("other"))
+			})
+		})
+
+		It("returns an error if the selector is invalid", func() {
+			configMap.Data[qsv1a1.RotateQSecretSelectorName] = "app in"
+
+			_, err := reconciler.Reconcile(context.Background(), request)
+			Expect(err).To(HaveOccurred())
+			Expect(statusWriter.UpdateCallCount()).To(Equal(0))
+		})
+	})
+
+	It("returns an error if the list of secrets is invalid", func() {
+		configMap.Data[qsv1a1.RotateQSecretListName] = "generated"
+
+		_, err := reconciler.Reconcile(context.Background(), request)
+		Expect(err).To(HaveOccurred())
+		Expect(statusWriter.UpdateCallCount()).To(Equal(0))
+	})
+})
@@ -5,9 +5,7 @@ import (
 
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
-	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/types"
 
 	"code.cloudfoundry.org/quarks-secret/pkg/helm/template"
 	qsv1a1 "code.cloudfoundry.org/quarks-secret/pkg/kube/apis/quarkssecret/v1alpha1"
@@ -19,34 +17,22 @@ type TemplateEngine interface {
 	ExecuteMap(templates map[string]string, values map[string]interface{}) map[string]string
 }
 
-// renderSecret uses the specified templating engine to render the templates in Spec.Templates with the data from the referenced secrets.
-func (r *ReconcileQuarksSecret) renderSecret(ctx context.Context, namespace string, request qsv1a1.TemplatedConfigRequest) (map[string]string, error) {
-	empty := map[string]string{}
-
-	// Interpolate the given values, and retrieve the secret contents to fill our config with
-	values := map[string]interface{}{}
+// templateValues retrieves the contents of the referenced secrets to fill our config with
+func (r *ReconcileQuarksSecret) templateValues(ctx context.Context, namespace string, request qsv1a1.TemplatedConfigRequest) (map[string]string, error) {
+	values := map[string]string{}
 	for name, ref := range request.Values {
-		secretName := ref.Name
-		field := ref.Key
-
-		existingSecret := &corev1.Secret{}
-		namespacedName := types.NamespacedName{
-			Namespace: namespace,
-			Name:      secretName,
-		}
-		err := r.client.Get(ctx, namespacedName, existingSecret)
+		value, err := getSecretReferenceValue(ctx, r.client, namespace, ref)
 		if err != nil {
-			if apierrors.IsNotFound(err) {
-				return empty, newSecNotReadyError("secret not found")
-			}
-			return empty, errors.Wrap(err, "getting secret")
-		}
-		data, ok := existingSecret.Data[field]
-		if !ok {
-			return empty, errors.Errorf("Failed to get secret data key: %s", field)
+			return map[string]string{}, err
 		}
-		values[name] = string(data)
+		values[name] = value
 	}
+	return values, nil
+}
+
+// renderSecret uses the specified templating engine to render the templates in Spec.Templates with the data from the referenced secrets.
+func (r *ReconcileQuarksSecret) renderSecret(request qsv1a1.TemplatedConfigRequest, values map[string]string) (map[string]string, error) {
+	empty := map[string]string{}
 
 	// Call the specified rendering engine to draw our data
 	var engine TemplateEngine
@@ -56,13 +42,50 @@ func (r *ReconcileQuarksSecret) renderSecret(ctx context.Context, namespace stri
 	default:
 		return empty, errors.New("unsupported template type has been specified")
 	}
-	return engine.ExecuteMap(request.Templates, values), nil
+
+	vars := make(map[string]interface{}, len(values))
+	for name, value := range values {
+		vars[name] = value
+	}
+	return engine.ExecuteMap(request.Templates, vars), nil
+}
+
+// templatedConfigInputs returns the template values, the provided values and
+// the hash over both and the request
+func (r *ReconcileQuarksSecret) templatedConfigInputs(ctx context.Context, qsec *qsv1a1.QuarksSecret) (map[string]string, map[string]string, string, error) {
+	request := qsec.Spec.Request.TemplatedConfigRequest
+	values, err := r.templateValues(ctx, qsec.Namespace, request)
+	if err != nil {
+		return nil, nil, "", err
+	}
+
+	provided, err := providedValues(ctx, r.client, qsec)
+	if err != nil {
+		return nil, nil, "", err
+	}
+
+	hash, err := inputsHash(request, hashedValues(values, provided))
+	if err != nil {
+		return nil, nil, "", err
+	}
+	return values, provided, hash, nil
 }
 
-func (r *ReconcileQuarksSecret) createTemplatedConfigSecret(ctx context.Context, qsec *qsv1a1.QuarksSecret) error {
-	secretData, err := r.renderSecret(ctx, qsec.Namespace, qsec.Spec.Request.TemplatedConfigRequest)
+// createTemplatedConfigSecret renders the templated config into the secret.
+// Returns false if rendering was skipped, because the inputs did not change.
+func (r *ReconcileQuarksSecret) createTemplatedConfigSecret(ctx context.Context, qsec *qsv1a1.QuarksSecret) (bool, error) {
+	request := qsec.Spec.Request.TemplatedConfigRequest
+	values, provided, hash, err := r.templatedConfigInputs(ctx, qsec)
 	if err != nil {
-		return err
+		return false, err
+	}
+	if !inputsChanged(qsec, hash) {
+		return false, nil
+	}
+
+	secretData, err := r.renderSecret(request, values)
+	if err != nil {
+		return false, err
 	}
 
 	secret := &corev1.Secret{
@@ -74,6 +97,12 @@ func (r *ReconcileQuarksSecret) createTemplatedConfigSecret(ctx context.Context,
 		},
 		StringData: secretData,
 	}
+	mergeProvidedValues(secret, provided)
+
+	if err := r.createSecrets(ctx, qsec, secret); err != nil {
+		return false, err
+	}
 
-	return r.createSecrets(ctx, qsec, secret)
+	qsec.Status.InputsHash = hash
+	return true, nil
 }
@@ -0,0 +1,132 @@
+package quarkssecret
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	qsv1a1 "code.cloudfoundry.org/quarks-secret/pkg/kube/apis/quarkssecret/v1alpha1"
+	"code.cloudfoundry.org/quarks-utils/pkg/config"
+	"code.cloudfoundry.org/quarks-utils/pkg/ctxlog"
+)
+
+// AddTrustBundle creates a new trust bundle controller, which merges the CA
+// certificates of the selected QuarksSecrets into the config map of a
+// QuarksTrustBundle.
+func AddTrustBundle(ctx context.Context, config *config.Config, mgr manager.Manager) error {
+	ctx = ctxlog.NewContextWithRecorder(ctx, "trust-bundle-reconciler", mgr.GetEventRecorderFor("trust-bundle-recorder"))
+	r := NewTrustBundleReconciler(ctx, config, mgr, controllerutil.SetControllerReference)
+
+	c, err := controller.New("trust-bundle-controller", mgr, controller.Options{
+		Reconciler:              r,
+		MaxConcurrentReconciles: config.MaxQuarksSecretWorkers,
+	})
+	if err != nil {
+		return errors.Wrap(err, "Adding trust bundle controller to manager failed.")
+	}
+
+	nsPred := newNSPredicate(ctx, mgr.GetClient(), config.MonitoredID)
+
+	allBundles := handler.EnqueueRequestsFromMapFunc(
+		func(a client.Object) []reconcile.Request {
+			reconciles, err := trustBundleReconciles(ctx, mgr.GetClient())
+			if err != nil {
+				ctxlog.Errorf(ctx, "Failed to calculate trust bundle reconciles for '%s/%s': %v", a.GetNamespace(), a.GetName(), err)
+			}
+
+			return reconciles
+		})
+
+	// Watch for trust bundles, which are created or whose spec changes
+	p := predicate.Funcs{
+		CreateFunc:  func(e event.CreateEvent) bool { return true },
+		DeleteFunc:  func(e event.DeleteEvent) bool { return false },
+		GenericFunc: func(e event.GenericEvent) bool { return false },
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			n := e.ObjectNew.(*qsv1a1.QuarksTrustBundle)
+			o := e.ObjectOld.(*qsv1a1.QuarksTrustBundle)
+
+			return !reflect.DeepEqual(n.Spec, o.Spec)
+		},
+	}
+	err = c.Watch(&source.Kind{Type: &qsv1a1.QuarksTrustBundle{}}, &handler.EnqueueRequestForObject{}, p)
+	if err != nil {
+		return errors.Wrapf(err, "Watching trust bundles failed in trust bundle controller.")
+	}
+
+	// Watch for generated secrets, whose data changes, e.g. on CA rotation
+	p = predicate.Funcs{
+		CreateFunc:  func(e event.CreateEvent) bool { return isGeneratedSecret(e.Object) },
+		DeleteFunc:  func(e event.DeleteEvent) bool { return false },
+		GenericFunc: func(e event.GenericEvent) bool { return false },
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			n := e.ObjectNew.(*corev1.Secret)
+			o := e.ObjectOld.(*corev1.Secret)
+
+			return isGeneratedSecret(n) && !reflect.DeepEqual(n.Data, o.Data)
+		},
+	}
+	err = c.Watch(&source.Kind{Type: &corev1.Secret{}}, allBundles, nsPred, p)
+	if err != nil {
+		return errors.Wrapf(err, "Watching generated secrets failed in trust bundle controller.")
+	}
+
+	// Watch for quarks secrets, which are deleted or change their labels.
+	// The reconciler skips quarks secrets in namespaces, which are not
+	// monitored.
+	p = predicate.Funcs{
+		CreateFunc:  func(e event.CreateEvent) bool { return false },
+		DeleteFunc:  func(e event.DeleteEvent) bool { return true },
+		GenericFunc: func(e event.GenericEvent) bool { return false },
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			return !reflect.DeepEqual(e.ObjectNew.GetLabels(), e.ObjectOld.GetLabels())
+		},
+	}
+	err = c.Watch(&source.Kind{Type: &qsv1a1.QuarksSecret{}}, allBundles, p)
+	if err != nil {
+		return errors.Wrapf(err, "Watching quarks secrets failed in trust bundle controller.")
+	}
+
+	// Watch for namespaces, which appear, disappear or change their labels
+	p = predicate.Funcs{
+		CreateFunc:  func(e event.CreateEvent) bool { return true },
+		DeleteFunc:  func(e event.DeleteEvent) bool { return true },
+		GenericFunc: func(e event.GenericEvent) bool { return false },
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			return !reflect.DeepEqual(e.ObjectNew.GetLabels(), e.ObjectOld.GetLabels())
+		},
+	}
+	err = c.Watch(&source.Kind{Type: &corev1.Namespace{}}, allBundles, p)
+	if err != nil {
+		return errors.Wrapf(err, "Watching namespaces failed in trust bundle controller.")
+	}
+
+	return nil
+}
+
+// trustBundleReconciles lists all trust bundles, since every quarks secret
+// and namespace may be selected by any of them
+func trustBundleReconciles(ctx context.Context, client client.Client) ([]reconcile.Request, error) {
+	bundles := &qsv1a1.QuarksTrustBundleList{}
+	if err := client.List(ctx, bundles); err != nil {
+		return nil, errors.Wrap(err, "failed to list QuarksTrustBundles")
+	}
+
+	result := make([]reconcile.Request, len(bundles.Items))
+	for i, bundle := range bundles.Items {
+		result[i] = reconcile.Request{NamespacedName: types.NamespacedName{Name: bundle.Name}}
+	}
+	return result, nil
+}
@@ -0,0 +1,334 @@
+package quarkssecret
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	qsv1a1 "code.cloudfoundry.org/quarks-secret/pkg/kube/apis/quarkssecret/v1alpha1"
+	"code.cloudfoundry.org/quarks-utils/pkg/config"
+	"code.cloudfoundry.org/quarks-utils/pkg/ctxlog"
+)
+
+// NewTrustBundleReconciler returns a new ReconcileTrustBundle
+func NewTrustBundleReconciler(ctx context.Context, config *config.Config, mgr manager.Manager, srf setReferenceFunc) reconcile.Reconciler {
+	return &ReconcileTrustBundle{
+		ctx:          ctx,
+		config:       config,
+		client:       mgr.GetClient(),
+		scheme:       mgr.GetScheme(),
+		setReference: srf,
+	}
+}
+
+// ReconcileTrustBundle maintains the config maps of QuarksTrustBundles
+type ReconcileTrustBundle struct {
+	ctx          context.Context
+	client       client.Client
+	scheme       *runtime.Scheme
+	config       *config.Config
+	setReference setReferenceFunc
+}
+
+// bundledCertificate is a parsed CA certificate of the bundle
+type bundledCertificate struct {
+	qsv1a1.BundledCertificate
+	raw []byte
+}
+
+// Reconcile gathers the CA certificates of the quarks secrets selected by
+// the QuarksTrustBundle from all monitored namespaces and writes them,
+// deduplicated and ordered by subject, to the bundle's config map in the
+// selected namespaces.
+func (r *ReconcileTrustBundle) Reconcile(_ context.Context, request reconcile.Request) (reconcile.Result, error) {
+	bundle := &qsv1a1.QuarksTrustBundle{}
+
+	// Set the ctx to be Background, as the top-level context for incoming requests.
+	ctx, cancel := context.WithTimeout(r.ctx, r.config.CtxTimeOut)
+	defer cancel()
+
+	ctxlog.Infof(ctx, "Reconciling QuarksTrustBundle %s", request.Name)
+	err := r.client.Get(ctx, types.NamespacedName{Name: request.Name}, bundle)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			ctxlog.Info(ctx, "Skip reconcile: quarks trust bundle not found")
+			return reconcile.Result{}, nil
+		}
+		ctxlog.Info(ctx, "Error reading the object")
+		return reconcile.Result{}, errors.Wrap(err, "Error reading quarksTrustBundle")
+	}
+
+	sources, err := r.monitoredNamespaces(ctx, bundle.Spec.SourceNamespaceSelector)
+	if err != nil {
+		return reconcile.Result{}, ctxlog.WithEvent(bundle, "TrustBundleFailed").Errorf(ctx, "Failed to list source namespaces of QuarksTrustBundle '%s': %s", bundle.Name, err)
+	}
+
+	certs, err := r.gatherCertificates(ctx, bundle, sources)
+	if err != nil {
+		return reconcile.Result{}, ctxlog.WithEvent(bundle, "TrustBundleFailed").Errorf(ctx, "Failed to gather certificates of QuarksTrustBundle '%s': %s", bundle.Name, err)
+	}
+
+	targets, err := r.monitoredNamespaces(ctx, bundle.Spec.NamespaceSelector)
+	if err != nil {
+		return reconcile.Result{}, ctxlog.WithEvent(bundle, "TrustBundleFailed").Errorf(ctx, "Failed to list namespaces of QuarksTrustBundle '%s': %s", bundle.Name, err)
+	}
+
+	var buf bytes.Buffer
+	for _, cert := range certs {
+		if err := pem.Encode(&buf, &pem.Block{Type: "CERTIFICATE", Bytes: cert.raw}); err != nil {
+			return reconcile.Result{}, errors.Wrap(err, "could not encode certificate")
+		}
+	}
+
+	var published []string
+	for _, ns := range targets {
+		ok, err := r.writeConfigMap(ctx, bundle, ns, buf.String())
+		if err != nil {
+			return reconcile.Result{}, ctxlog.WithEvent(bundle, "TrustBundleFailed").Errorf(ctx, "Failed to write QuarksTrustBundle '%s': %s", bundle.Name, err)
+		}
+		if ok {
+			published = append(published, ns)
+		}
+	}
+
+	if err := r.pruneConfigMaps(ctx, bundle, published); err != nil {
+		return reconcile.Result{}, ctxlog.WithEvent(bundle, "TrustBundleFailed").Errorf(ctx, "Failed to prune QuarksTrustBundle '%s': %s", bundle.Name, err)
+	}
+
+	status := qsv1a1.QuarksTrustBundleStatus{Namespaces: published}
+	for _, cert := range certs {
+		status.Certificates = append(status.Certificates, cert.BundledCertificate)
+	}
+	status.LastReconcile = bundle.Status.LastReconcile
+	if !reflect.DeepEqual(status, bundle.Status) || status.LastReconcile == nil {
+		now := metav1.Now()
+		status.LastReconcile = &now
+		bundle.Status = status
+		if err := r.client.Status().Update(ctx, bundle); err != nil {
+			return reconcile.Result{}, errors.Wrapf(err, "could not update status of QuarksTrustBundle '%s'", bundle.Name)
+		}
+	}
+
+	return reconcile.Result{}, nil
+}
+
+// monitoredNamespaces lists the monitored namespaces, which match the
+// selector. A nil selector selects all monitored namespaces.
+func (r *ReconcileTrustBundle) monitoredNamespaces(ctx context.Context, ls *metav1.LabelSelector) ([]string, error) {
+	selector := labels.Everything()
+	if ls != nil {
+		var err error
+		selector, err = metav1.LabelSelectorAsSelector(ls)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid namespace selector")
+		}
+	}
+
+	namespaces := &corev1.NamespaceList{}
+	err := r.client.List(ctx, namespaces, client.MatchingLabelsSelector{Selector: selector})
+	if err != nil {
+		return nil, errors.Wrap(err, "could not list namespaces")
+	}
+
+	result := []string{}
+	for i := range namespaces.Items {
+		ns := &namespaces.Items[i]
+		if !qsv1a1.IsMonitoredNamespace(ns, r.config.MonitoredID) || !ns.GetDeletionTimestamp().IsZero() {
+			continue
+		}
+		result = append(result, ns.Name)
+	}
+	sort.Strings(result)
+	return result, nil
+}
+
+// gatherCertificates returns the CA certificates of the selected quarks
+// secrets. The certificate of a quarks secret is included, if it is a CA,
+// and all certificates of its 'ca' key are included. Expired certificates
+// are skipped.
+func (r *ReconcileTrustBundle) gatherCertificates(ctx context.Context, bundle *qsv1a1.QuarksTrustBundle, namespaces []string) ([]bundledCertificate, error) {
+	selector, err := metav1.LabelSelectorAsSelector(&bundle.Spec.QuarksSecretSelector)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid quarks secret selector")
+	}
+
+	list := &qsv1a1.QuarksSecretList{}
+	err = r.client.List(ctx, list, client.MatchingLabelsSelector{Selector: selector})
+	if err != nil {
+		return nil, errors.Wrap(err, "could not list quarks secrets")
+	}
+	sort.Slice(list.Items, func(i, j int) bool {
+		return list.Items[i].GetNamespacedName() < list.Items[j].GetNamespacedName()
+	})
+
+	monitored := map[string]bool{}
+	for _, ns := range namespaces {
+		monitored[ns] = true
+	}
+
+	now := time.Now()
+	seen := map[string]bool{}
+	certs := []bundledCertificate{}
+	for i := range list.Items {
+		qsec := &list.Items[i]
+		if !monitored[qsec.Namespace] || (qsec.Spec.Type != qsv1a1.Certificate && qsec.Spec.Type != qsv1a1.TLS) {
+			continue
+		}
+
+		secret := &corev1.Secret{}
+		err := r.client.Get(ctx, types.NamespacedName{Name: qsec.Spec.SecretName, Namespace: qsec.Namespace}, secret)
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				ctxlog.Debugf(ctx, "Skip quarks secret '%s': secret not found", qsec.GetNamespacedName())
+				continue
+			}
+			return nil, errors.Wrapf(err, "could not get secret '%s/%s'", qsec.Namespace, qsec.Spec.SecretName)
+		}
+
+		key := "certificate"
+		if qsec.Spec.Type == qsv1a1.TLS {
+			key = corev1.TLSCertKey
+		}
+		candidates := parseCACertificates(secret.Data[key])
+		candidates = append(candidates, parseCACertificates(secret.Data["ca"])...)
+
+		for _, cert := range candidates {
+			fingerprint := fingerprintSHA256(cert.Raw)
+			if seen[fingerprint] || now.After(cert.NotAfter) {
+				continue
+			}
+			seen[fingerprint] = true
+
+			notAfter := metav1.NewTime(cert.NotAfter)
+			certs = append(certs, bundledCertificate{
+				BundledCertificate: qsv1a1.BundledCertificate{
+					QuarksSecret: qsec.GetNamespacedName(),
+					Subject:      cert.Subject.String(),
+					Fingerprint:  fingerprint,
+					NotAfter:     &notAfter,
+				},
+				raw: cert.Raw,
+			})
+		}
+	}
+
+	sort.SliceStable(certs, func(i, j int) bool {
+		if certs[i].Subject != certs[j].Subject {
+			return certs[i].Subject < certs[j].Subject
+		}
+		return certs[i].Fingerprint < certs[j].Fingerprint
+	})
+	return certs, nil
+}
+
+// parseCACertificates returns the CA certificates in the PEM data. Blocks,
+// which can't be parsed or are no CAs, are skipped.
+func parseCACertificates(data []byte) []*x509.Certificate {
+	certs := []*x509.Certificate{}
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			return certs
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil || !cert.IsCA {
+			continue
+		}
+		certs = append(certs, cert)
+	}
+}
+
+// writeConfigMap creates or updates the bundle's config map in the
+// namespace. Existing config maps, which don't belong to the bundle, are
+// never overwritten.
+func (r *ReconcileTrustBundle) writeConfigMap(ctx context.Context, bundle *qsv1a1.QuarksTrustBundle, namespace string, pemBundle string) (bool, error) {
+	name := bundle.Spec.ConfigMapName
+
+	existing := &corev1.ConfigMap{}
+	err := r.client.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, existing)
+	if err == nil && existing.GetAnnotations()[qsv1a1.AnnotationTrustBundleOf] != bundle.Name {
+		ctxlog.WithEvent(bundle, "TrustBundleReconcile").Infof(ctx, "Skip trust bundle: config map '%s/%s' exists and does not belong to the bundle", namespace, name)
+		return false, nil
+	}
+	if err != nil && !apierrors.IsNotFound(err) {
+		return false, errors.Wrapf(err, "could not get config map '%s/%s'", namespace, name)
+	}
+
+	configMap := &corev1.ConfigMap{}
+	configMap.Name = name
+	configMap.Namespace = namespace
+	op, err := controllerutil.CreateOrUpdate(ctx, r.client, configMap, func() error {
+		if configMap.Labels == nil {
+			configMap.Labels = map[string]string{}
+		}
+		configMap.Labels[qsv1a1.LabelKind] = qsv1a1.TrustBundleConfigMapKind
+
+		if configMap.Annotations == nil {
+			configMap.Annotations = map[string]string{}
+		}
+		configMap.Annotations[qsv1a1.AnnotationTrustBundleOf] = bundle.Name
+
+		configMap.Data = map[string]string{bundle.BundleKey(): pemBundle}
+		return r.setReference(bundle, configMap, r.scheme)
+	})
+	if err != nil {
+		return false, errors.Wrapf(err, "could not create or update config map '%s/%s'", namespace, name)
+	}
+	if op != controllerutil.OperationResultNone {
+		ctxlog.WithEvent(bundle, "TrustBundleReconcile").Infof(ctx, "Trust bundle config map '%s' has been %s in namespace '%s'", name, op, namespace)
+	}
+
+	return true, nil
+}
+
+// pruneConfigMaps deletes the config maps of the bundle, which are not in
+// one of the published namespaces or have been renamed
+func (r *ReconcileTrustBundle) pruneConfigMaps(ctx context.Context, bundle *qsv1a1.QuarksTrustBundle, published []string) error {
+	list := &corev1.ConfigMapList{}
+	err := r.client.List(ctx, list, client.MatchingLabels{qsv1a1.LabelKind: qsv1a1.TrustBundleConfigMapKind})
+	if err != nil {
+		return errors.Wrap(err, "could not list trust bundle config maps")
+	}
+
+	keep := map[string]bool{}
+	for _, ns := range published {
+		keep[ns] = true
+	}
+
+	for i := range list.Items {
+		configMap := &list.Items[i]
+		if configMap.GetAnnotations()[qsv1a1.AnnotationTrustBundleOf] != bundle.Name {
+			continue
+		}
+		if keep[configMap.Namespace] && configMap.Name == bundle.Spec.ConfigMapName {
+			continue
+		}
+
+		if err := r.client.Delete(ctx, configMap); err != nil && !apierrors.IsNotFound(err) {
+			return errors.Wrapf(err, "could not delete config map '%s/%s'", configMap.Namespace, configMap.Name)
+		}
+		ctxlog.WithEvent(bundle, "TrustBundleReconcile").Infof(ctx, "Trust bundle config map '%s' has been deleted in namespace '%s'", configMap.Name, configMap.Namespace)
+	}
+	return nil
+}
@@ -0,0 +1,262 @@
+package quarkssecret_test
+
+import (
+	"context"
+	"encoding/pem"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"go.uber.org/zap"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	crc "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"code.cloudfoundry.org/quarks-secret/pkg/credsgen"
+	inmemorygenerator "code.cloudfoundry.org/quarks-secret/pkg/credsgen/in_memory_generator"
+	qsv1a1 "code.cloudfoundry.org/quarks-secret/pkg/kube/apis/quarkssecret/v1alpha1"
+	"code.cloudfoundry.org/quarks-secret/pkg/kube/client/clientset/versioned/scheme"
+	"code.cloudfoundry.org/quarks-secret/pkg/kube/controllers"
+	cfakes "code.cloudfoundry.org/quarks-secret/pkg/kube/controllers/fakes"
+	qscontroller "code.cloudfoundry.org/quarks-secret/pkg/kube/controllers/quarkssecret"
+	cfcfg "code.cloudfoundry.org/quarks-utils/pkg/config"
+	"code.cloudfoundry.org/quarks-utils/pkg/ctxlog"
+	helper "code.cloudfoundry.org/quarks-utils/testing/testhelper"
+)
+
+var _ = Describe("ReconcileTrustBundle", func() {
+	var (
+		manager          *cfakes.FakeManager
+		reconciler       reconcile.Reconciler
+		request          reconcile.Request
+		ctx              context.Context
+		log              *zap.SugaredLogger
+		config           *cfcfg.Config
+		client           *cfakes.FakeClient
+		statusWriter     *cfakes.FakeStatusWriter
+		bundle           *qsv1a1.QuarksTrustBundle
+		quarksSecrets    []qsv1a1.QuarksSecret
+		secrets          map[string]*corev1.Secret
+		configMaps       []corev1.ConfigMap
+		namespaces       []corev1.Namespace
+		ca               credsgen.Certificate
+		otherCA          credsgen.Certificate
+		leaf             credsgen.Certificate
+		setReferenceFunc func(owner, object metav1.Object, scheme *runtime.Scheme) error
+	)
+
+	// written returns the config maps, which were created
+	written := func() []*corev1.ConfigMap {
+		result := []*corev1.ConfigMap{}
+		for i := 0; i < client.CreateCallCount(); i++ {
+			_, object, _ := client.CreateArgsForCall(i)
+			result = append(result, object.(*corev1.ConfigMap))
+		}
+		return result
+	}
+
+	// bundled returns the certificates in the PEM bundle
+	bundled := func(data string) [][]byte {
+		result := [][]byte{}
+		rest := []byte(data)
+		for {
+			var block *pem.Block
+			block, rest = pem.Decode(rest)
+			if block == nil {
+				return result
+			}
+			result = append(result, pem.EncodeToMemory(block))
+		}
+	}
+
+	monitored := func(name string) corev1.Namespace {
+		return corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: map[string]string{qsv1a1.LabelNamespace: "staging"},
+		}}
+	}
+
+	BeforeEach(func() {
+		err := controllers.AddToScheme(scheme.Scheme)
+		Expect(err).ToNot(HaveOccurred())
+		manager = &cfakes.FakeManager{}
+		request = reconcile.Request{NamespacedName: types.NamespacedName{Name: "platform-cas"}}
+		config = &cfcfg.Config{CtxTimeOut: 10 * time.Second, MonitoredID: "staging"}
+		_, log = helper.NewTestLogger()
+		ctx = ctxlog.NewContextWithRecorder(ctxlog.NewParentContext(log), "TestRecorder", record.NewFakeRecorder(10))
+		setReferenceFunc = func(owner, object metav1.Object, scheme *runtime.Scheme) error { return nil }
+
+		inMemory := inmemorygenerator.NewInMemoryGenerator(log)
+		ca, err = inMemory.GenerateCertificate("ca", credsgen.CertificateGenerationRequest{IsCA: true, CommonName: "a.example.com"})
+		Expect(err).ToNot(HaveOccurred())
+		otherCA, err = inMemory.GenerateCertificate("other-ca", credsgen.CertificateGenerationRequest{IsCA: true, CommonName: "b.example.com"})
+		Expect(err).ToNot(HaveOccurred())
+		leaf, err = inMemory.GenerateCertificate("leaf", credsgen.CertificateGenerationRequest{CommonName: "leaf.example.com", CA: ca})
+		Expect(err).ToNot(HaveOccurred())
+
+		bundle = &qsv1a1.QuarksTrustBundle{
+			ObjectMeta: metav1.ObjectMeta{Name: "platform-cas"},
+			Spec: qsv1a1.QuarksTrustBundleSpec{
+				ConfigMapName: "ca-bundle",
+				QuarksSecretSelector: metav1.LabelSelector{
+					MatchLabels: map[string]string{"trust": "platform"},
+				},
+			},
+		}
+		quarksSecrets = []qsv1a1.QuarksSecret{
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "other-ca", Namespace: "system"},
+				Spec:       qsv1a1.QuarksSecretSpec{Type: qsv1a1.Certificate, SecretName: "other-ca"},
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "ca", Namespace: "system"},
+				Spec:       qsv1a1.QuarksSecretSpec{Type: qsv1a1.Certificate, SecretName: "ca"},
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "leaf", Namespace: "app"},
+				Spec:       qsv1a1.QuarksSecretSpec{Type: qsv1a1.TLS, SecretName: "leaf"},
+			},
+		}
+		secrets = map[string]*corev1.Secret{
+			"system/ca":       {Data: map[string][]byte{"certificate": ca.Certificate, "ca": ca.Certificate}},
+			"system/other-ca": {Data: map[string][]byte{"certificate": otherCA.Certificate}},
+			"app/leaf":        {Data: map[string][]byte{"tls.crt": leaf.Certificate, "ca": ca.Certificate}},
+		}
+		configMaps = []corev1.ConfigMap{}
+		namespaces = []corev1.Namespace{
+			monitored("system"),
+			monitored("app"),
+			{ObjectMeta: metav1.ObjectMeta{Name: "unmonitored"}},
+		}
+
+		client = &cfakes.FakeClient{}
+		client.GetCalls(func(context context.Context, nn types.NamespacedName, object crc.Object) error {
+			switch object := object.(type) {
+			case *qsv1a1.QuarksTrustBundle:
+				bundle.DeepCopyInto(object)
+				return nil
+			case *corev1.Secret:
+				if secret, ok := secrets[nn.Namespace+"/"+nn.Name]; ok {
+					secret.DeepCopyInto(object)
+					return nil
+				}
+			case *corev1.ConfigMap:
+				for i := range configMaps {
+					if configMaps[i].Name == nn.Name && configMaps[i].Namespace == nn.Namespace {
+						configMaps[i].DeepCopyInto(object)
+						return nil
+					}
+				}
+			}
+			return apierrors.NewNotFound(schema.GroupResource{}, nn.Name)
+		})
+		client.ListCalls(func(context context.Context, object crc.ObjectList, _ ...crc.ListOption) error {
+			switch object := object.(type) {
+			case *qsv1a1.QuarksSecretList:
+				object.Items = quarksSecrets
+			case *corev1.ConfigMapList:
+				object.Items = configMaps
+			case *corev1.NamespaceList:
+				object.Items = namespaces
+			}
+			return nil
+		})
+		statusWriter = &cfakes.FakeStatusWriter{}
+		client.StatusCalls(func() crc.StatusWriter { return statusWriter })
+		manager.GetClientReturns(client)
+		manager.GetSchemeReturns(scheme.Scheme)
+	})
+
+	JustBeforeEach(func() {
+		reconciler = qscontroller.NewTrustBundleReconciler(ctx, config, manager, setReferenceFunc)
+	})
+
+	It("writes the deduplicated and ordered CA certificates to the monitored namespaces", func() {
+		_, err := reconciler.Reconcile(context.Background(), request)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(written()).To(HaveLen(2))
+		Expect(written()[0].Namespace).To(Equal("app"))
+		Expect(written()[1].Namespace).To(Equal("system"))
+
+		configMap := written()[0]
+		Expect(configMap.Name).To(Equal("ca-bundle"))
+		Expect(configMap.Labels).To(HaveKeyWithValue(qsv1a1.LabelKind, qsv1a1.TrustBundleConfigMapKind))
+		Expect(configMap.Annotations).To(HaveKeyWithValue(qsv1a1.AnnotationTrustBundleOf, "platform-cas"))
+		Expect(bundled(configMap.Data[qsv1a1.DefaultTrustBundleKey])).To(Equal([][]byte{ca.Certificate, otherCA.Certificate}))
+
+		Expect(statusWriter.UpdateCallCount()).To(Equal(1))
+		_, object, _ := statusWriter.UpdateArgsForCall(0)
+		status := object.(*qsv1a1.QuarksTrustBundle).Status
+		Expect(status.Namespaces).To(Equal([]string{"app", "system"}))
+		Expect(status.Certificates).To(HaveLen(2))
+		Expect(status.Certificates[0].QuarksSecret).To(Equal("app/leaf"))
+		Expect(status.Certificates[0].Subject).To(Equal("CN=a.example.com"))
+		Expect(status.Certificates[1].QuarksSecret).To(Equal("system/other-ca"))
+		Expect(status.LastReconcile).ToNot(BeNil())
+	})
+
+	It("skips certificates in the 'ca' key, which are no CAs", func() {
+		secrets["system/ca"].Data["ca"] = append(append([]byte{}, ca.Certificate...), leaf.Certificate...)
+
+		_, err := reconciler.Reconcile(context.Background(), request)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(written()).To(HaveLen(2))
+		Expect(bundled(written()[1].Data[qsv1a1.DefaultTrustBundleKey])).To(Equal([][]byte{ca.Certificate, otherCA.Certificate}))
+	})
+
+	It("only bundles quarks secrets from the selected source namespaces", func() {
+		bundle.Spec.SourceNamespaceSelector = &metav1.LabelSelector{}
+		namespaces = []corev1.Namespace{monitored("app")}
+
+		_, err := reconciler.Reconcile(context.Background(), request)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(written()).To(HaveLen(1))
+		Expect(bundled(written()[0].Data[qsv1a1.DefaultTrustBundleKey])).To(Equal([][]byte{ca.Certificate}))
+	})
+
+	It("does not overwrite config maps, which don't belong to the bundle", func() {
+		configMaps = []corev1.ConfigMap{
+			{ObjectMeta: metav1.ObjectMeta{Name: "ca-bundle", Namespace: "app"}},
+		}
+
+		_, err := reconciler.Reconcile(context.Background(), request)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(written()).To(HaveLen(1))
+		Expect(written()[0].Namespace).To(Equal("system"))
+		Expect(client.UpdateCallCount()).To(Equal(0))
+	})
+
+	It("deletes config maps in namespaces, which are no longer selected", func() {
+		bundle.Spec.NamespaceSelector = &metav1.LabelSelector{MatchLabels: map[string]string{"tier": "apps"}}
+		namespaces = []corev1.Namespace{}
+		configMaps = []corev1.ConfigMap{
+			{ObjectMeta: metav1.ObjectMeta{
+				Name:        "ca-bundle",
+				Namespace:   "app",
+				Labels:      map[string]string{qsv1a1.LabelKind: qsv1a1.TrustBundleConfigMapKind},
+				Annotations: map[string]string{qsv1a1.AnnotationTrustBundleOf: "platform-cas"},
+			}},
+			{ObjectMeta: metav1.ObjectMeta{
+				Name:        "ca-bundle",
+				Namespace:   "system",
+				Labels:      map[string]string{qsv1a1.LabelKind: qsv1a1.TrustBundleConfigMapKind},
+				Annotations: map[string]string{qsv1a1.AnnotationTrustBundleOf: "other-bundle"},
+			}},
+		}
+
+		_, err := reconciler.Reconcile(context.Background(), request)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(written()).To(BeEmpty())
+		Expect(client.DeleteCallCount()).To(Equal(1))
+		_, object, _ := client.DeleteArgsForCall(0)
+		Expect(object.GetNamespace()).To(Equal("app"))
+	})
+})
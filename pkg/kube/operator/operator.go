@@ -19,7 +19,7 @@ import (
 )
 
 // NewManager adds schemes, controllers and starts the manager
-func NewManager(ctx context.Context, config *config.Config, cfg *rest.Config, options manager.Options) (manager.Manager, error) {
+func NewManager(ctx context.Context, config *config.Config, cfg *rest.Config, options manager.Options, controllerOptions controllers.Options) (manager.Manager, error) {
 	mgr, err := manager.New(cfg, options)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to initialize new manager")
@@ -36,7 +36,7 @@ func NewManager(ctx context.Context, config *config.Config, cfg *rest.Config, op
 	}
 
 	// Setup all Controllers
-	err = controllers.AddToManager(ctx, config, mgr)
+	err = controllers.AddToManager(ctx, config, mgr, controllerOptions)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to add controllers to manager")
 	}
@@ -51,27 +51,114 @@ func ApplyCRDs(ctx context.Context, config *rest.Config) error {
 		return errors.Wrap(err, "Could not get kube client")
 	}
 
-	b := crd.New(
-		qsv1a1.QuarksSecretResourceName,
-		extv1.CustomResourceDefinitionNames{
-			Kind:       qsv1a1.QuarksSecretResourceKind,
-			Plural:     qsv1a1.QuarksSecretResourcePlural,
-			ShortNames: qsv1a1.QuarksSecretResourceShortNames,
+	for _, def := range []struct {
+		Name               string
+		CustomResourceName extv1.CustomResourceDefinitionNames
+		Validation         *extv1.CustomResourceValidation
+		PrinterColumns     []extv1.CustomResourceColumnDefinition
+		Scope              extv1.ResourceScope
+	}{
+		{
+			qsv1a1.QuarksSecretResourceName,
+			extv1.CustomResourceDefinitionNames{
+				Kind:       qsv1a1.QuarksSecretResourceKind,
+				Plural:     qsv1a1.QuarksSecretResourcePlural,
+				ShortNames: qsv1a1.QuarksSecretResourceShortNames,
+			},
+			&qsv1a1.QuarksSecretValidation,
+			qsv1a1.QuarksSecretAdditionalPrinterColumns,
+			extv1.NamespaceScoped,
 		},
-		qsv1a1.SchemeGroupVersion,
-	)
+		{
+			qsv1a1.QuarksSecretRotationResourceName,
+			extv1.CustomResourceDefinitionNames{
+				Kind:       qsv1a1.QuarksSecretRotationResourceKind,
+				Plural:     qsv1a1.QuarksSecretRotationResourcePlural,
+				ShortNames: qsv1a1.QuarksSecretRotationResourceShortNames,
+			},
+			&qsv1a1.QuarksSecretRotationValidation,
+			qsv1a1.QuarksSecretRotationAdditionalPrinterColumns,
+			extv1.NamespaceScoped,
+		},
+		{
+			qsv1a1.QuarksSecretCopyGrantResourceName,
+			extv1.CustomResourceDefinitionNames{
+				Kind:       qsv1a1.QuarksSecretCopyGrantResourceKind,
+				Plural:     qsv1a1.QuarksSecretCopyGrantResourcePlural,
+				ShortNames: qsv1a1.QuarksSecretCopyGrantResourceShortNames,
+			},
+			&qsv1a1.QuarksSecretCopyGrantValidation,
+			qsv1a1.QuarksSecretCopyGrantAdditionalPrinterColumns,
+			extv1.NamespaceScoped,
+		},
+		{
+			qsv1a1.QuarksTrustBundleResourceName,
+			extv1.CustomResourceDefinitionNames{
+				Kind:       qsv1a1.QuarksTrustBundleResourceKind,
+				Plural:     qsv1a1.QuarksTrustBundleResourcePlural,
+				ShortNames: qsv1a1.QuarksTrustBundleResourceShortNames,
+			},
+			&qsv1a1.QuarksTrustBundleValidation,
+			qsv1a1.QuarksTrustBundleAdditionalPrinterColumns,
+			extv1.ClusterScoped,
+		},
+		{
+			qsv1a1.ClusterQuarksSecretResourceName,
+			extv1.CustomResourceDefinitionNames{
+				Kind:       qsv1a1.ClusterQuarksSecretResourceKind,
+				Plural:     qsv1a1.ClusterQuarksSecretResourcePlural,
+				ShortNames: qsv1a1.ClusterQuarksSecretResourceShortNames,
+			},
+			&qsv1a1.ClusterQuarksSecretValidation,
+			qsv1a1.ClusterQuarksSecretAdditionalPrinterColumns,
+			extv1.ClusterScoped,
+		},
+		{
+			qsv1a1.QuarksIssuerResourceName,
+			extv1.CustomResourceDefinitionNames{
+				Kind:       qsv1a1.QuarksIssuerResourceKind,
+				Plural:     qsv1a1.QuarksIssuerResourcePlural,
+				ShortNames: qsv1a1.QuarksIssuerResourceShortNames,
+			},
+			&qsv1a1.QuarksIssuerValidation,
+			qsv1a1.QuarksIssuerAdditionalPrinterColumns,
+			extv1.NamespaceScoped,
+		},
+		{
+			qsv1a1.ClusterQuarksIssuerResourceName,
+			extv1.CustomResourceDefinitionNames{
+				Kind:       qsv1a1.ClusterQuarksIssuerResourceKind,
+				Plural:     qsv1a1.ClusterQuarksIssuerResourcePlural,
+				ShortNames: qsv1a1.ClusterQuarksIssuerResourceShortNames,
+			},
+			&qsv1a1.ClusterQuarksIssuerValidation,
+			qsv1a1.ClusterQuarksIssuerAdditionalPrinterColumns,
+			extv1.ClusterScoped,
+		},
+	} {
+		err = applyCRD(ctx, client, def.Name, def.CustomResourceName, def.Validation, def.PrinterColumns, def.Scope)
+		if err != nil {
+			return err
+		}
+	}
 
-	err = b.WithValidation(&qsv1a1.QuarksSecretValidation).
-		WithAdditionalPrinterColumns(qsv1a1.QuarksSecretAdditionalPrinterColumns).
-		Build().
-		Apply(ctx, client)
+	return nil
+}
+
+func applyCRD(ctx context.Context, client extv1client.ApiextensionsV1beta1Interface, crdName string, names extv1.CustomResourceDefinitionNames, validation *extv1.CustomResourceValidation, printerColumns []extv1.CustomResourceColumnDefinition, scope extv1.ResourceScope) error {
+	b := crd.New(crdName, names, qsv1a1.SchemeGroupVersion).
+		WithValidation(validation).
+		WithAdditionalPrinterColumns(printerColumns).
+		Build()
+	// The builder only creates namespaced CRDs
+	b.CRD.Spec.Scope = scope
+	err := b.Apply(ctx, client)
 	if err != nil {
-		return errors.Wrapf(err, "failed to apply CRD '%s'", qsv1a1.QuarksSecretResourceName)
+		return errors.Wrapf(err, "failed to apply CRD '%s'", crdName)
 	}
-	err = crd.WaitForCRDReady(ctx, client, qsv1a1.QuarksSecretResourceName)
+	err = crd.WaitForCRDReady(ctx, client, crdName)
 	if err != nil {
-		return errors.Wrapf(err, "failed to wait for CRD '%s' ready", qsv1a1.QuarksSecretResourceName)
+		return errors.Wrapf(err, "failed to wait for CRD '%s' ready", crdName)
 	}
-
 	return nil
 }